@@ -5,6 +5,7 @@ import (
 	"flag"
 	"fmt"
 	"os"
+	"strings"
 
 	"github.com/DanielMSchmidt/tf-provider-migrate/internal/migrate"
 )
@@ -20,6 +21,10 @@ func main() {
 		runCheck(os.Args[2:])
 	case "migrate":
 		runMigrate(os.Args[2:])
+	case "migrate-resources":
+		runMigrateResources(os.Args[2:])
+	case "init-templates":
+		runInitTemplates(os.Args[2:])
 	case "-h", "--help", "help":
 		usage()
 	default:
@@ -32,14 +37,32 @@ func main() {
 func runCheck(args []string) {
 	flags := flag.NewFlagSet("check", flag.ExitOnError)
 	path := flags.String("path", ".", "path to provider repo (default: current directory)")
+	config := flags.String("config", "", "path to the config file (default .tf-provider-migrate.yaml in the module or repository root)")
 	registry := flags.String("registry-address", "", "registry address (e.g. registry.terraform.io/org/name)")
 	providerName := flags.String("provider-name", "", "provider type name override (default derived)")
+	protocol := flags.Int("protocol-version", 0, "plugin protocol version served by the muxed provider, 5 or 6 (default 5)")
+	layoutDir := flags.String("layout-dir", "", "directory of the generated framework package, relative to the module (default framework)")
+	layoutPackage := flags.String("layout-package", "", "package name of the generated framework package (default derived from --layout-dir)")
+	layoutSplit := flags.Bool("layout-split", false, "generate resources/ and datasources/ subpackages next to provider.go")
+	templates := flags.String("templates", "", "directory with templates overriding the built-in ones (see init-templates)")
+	configure := flags.String("configure", "", "framework Configure: shared reads the SDK meta value, independent ports the SDK configure function (default shared)")
+	noUpgrade := flags.Bool("no-upgrade", false, "fail instead of upgrading go.mod requirements below the required version")
 	flags.Parse(args)
 
 	opts := migrate.Options{
 		Path:            *path,
+		ConfigFile:      *config,
 		RegistryAddress: *registry,
 		ProviderName:    *providerName,
+		ProtocolVersion: *protocol,
+		Layout: migrate.Layout{
+			Dir:     *layoutDir,
+			Package: *layoutPackage,
+			Split:   *layoutSplit,
+		},
+		TemplatesDir: *templates,
+		Configure:    *configure,
+		NoUpgrade:    *noUpgrade,
 	}
 
 	report, err := migrate.Check(opts)
@@ -49,27 +72,49 @@ func runCheck(args []string) {
 	}
 
 	fmt.Printf("check OK: %s\n", report.Summary())
+	printDetails(report)
 }
 
 func runMigrate(args []string) {
 	flags := flag.NewFlagSet("migrate", flag.ExitOnError)
 	path := flags.String("path", ".", "path to provider repo (default: current directory)")
+	config := flags.String("config", "", "path to the config file (default .tf-provider-migrate.yaml in the module or repository root)")
 	registry := flags.String("registry-address", "", "registry address (e.g. registry.terraform.io/org/name)")
 	providerName := flags.String("provider-name", "", "provider type name override (default derived)")
 	dryRun := flags.Bool("dry-run", false, "show planned changes without writing files")
+	protocol := flags.Int("protocol-version", 0, "plugin protocol version served by the muxed provider, 5 or 6 (default 5)")
+	layoutDir := flags.String("layout-dir", "", "directory of the generated framework package, relative to the module (default framework)")
+	layoutPackage := flags.String("layout-package", "", "package name of the generated framework package (default derived from --layout-dir)")
+	layoutSplit := flags.Bool("layout-split", false, "generate resources/ and datasources/ subpackages next to provider.go")
+	templates := flags.String("templates", "", "directory with templates overriding the built-in ones (see init-templates)")
+	configure := flags.String("configure", "", "framework Configure: shared reads the SDK meta value, independent ports the SDK configure function (default shared)")
+	noUpgrade := flags.Bool("no-upgrade", false, "fail instead of upgrading go.mod requirements below the required version")
+	force := flags.Bool("force", false, "overwrite existing files that were not generated by tf-provider-migrate")
 	flags.Parse(args)
 
 	opts := migrate.Options{
 		Path:            *path,
+		ConfigFile:      *config,
 		RegistryAddress: *registry,
 		ProviderName:    *providerName,
-		DryRun:          *dryRun,
+		ProtocolVersion: *protocol,
+		Layout: migrate.Layout{
+			Dir:     *layoutDir,
+			Package: *layoutPackage,
+			Split:   *layoutSplit,
+		},
+		TemplatesDir: *templates,
+		Configure:    *configure,
+		DryRun:       *dryRun,
+		NoUpgrade:    *noUpgrade,
+		Force:        *force,
 	}
 
 	report, err := migrate.Migrate(opts)
 	if err != nil {
 		if errors.Is(err, migrate.ErrDryRun) {
 			fmt.Println(report.Summary())
+			printDetails(report)
 			return
 		}
 		fmt.Fprintf(os.Stderr, "migrate failed: %v\n", err)
@@ -77,16 +122,108 @@ func runMigrate(args []string) {
 	}
 
 	fmt.Printf("migrate OK: %s\n", report.Summary())
+	printDetails(report)
+}
+
+func runMigrateResources(args []string) {
+	flags := flag.NewFlagSet("migrate-resources", flag.ExitOnError)
+	path := flags.String("path", ".", "path to provider repo (default: current directory)")
+	config := flags.String("config", "", "path to the config file (default .tf-provider-migrate.yaml in the module or repository root)")
+	providerName := flags.String("provider-name", "", "provider type name override (default derived)")
+	resources := flags.String("resource", "", "comma separated resource type names to migrate")
+	dataSources := flags.String("data-source", "", "comma separated data source type names to migrate")
+	all := flags.Bool("all", false, "migrate every resource and data source not on a skip list")
+	timeouts := flags.String("timeouts", "", "declare timeouts as a block or as attributes; attributes need protocol version 6 (default block)")
+	dryRun := flags.Bool("dry-run", false, "show planned changes without writing files")
+	protocol := flags.Int("protocol-version", 0, "plugin protocol version served by the muxed provider, 5 or 6 (default: the one main.go serves)")
+	layoutDir := flags.String("layout-dir", "", "directory of the generated framework package, relative to the module (default framework)")
+	layoutPackage := flags.String("layout-package", "", "package name of the generated framework package (default derived from --layout-dir)")
+	layoutSplit := flags.Bool("layout-split", false, "generate resources/ and datasources/ subpackages next to provider.go")
+	templates := flags.String("templates", "", "directory with templates overriding the built-in ones (see init-templates)")
+	noUpgrade := flags.Bool("no-upgrade", false, "fail instead of upgrading go.mod requirements below the required version")
+	force := flags.Bool("force", false, "overwrite existing files that were not generated by tf-provider-migrate")
+	flags.Parse(args)
+
+	opts := migrate.Options{
+		Path:            *path,
+		ConfigFile:      *config,
+		ProviderName:    *providerName,
+		ProtocolVersion: *protocol,
+		Layout: migrate.Layout{
+			Dir:     *layoutDir,
+			Package: *layoutPackage,
+			Split:   *layoutSplit,
+		},
+		TemplatesDir: *templates,
+		Resources:    splitList(*resources),
+		DataSources:  splitList(*dataSources),
+		AllResources: *all,
+		Timeouts:     *timeouts,
+		DryRun:       *dryRun,
+		NoUpgrade:    *noUpgrade,
+		Force:        *force,
+	}
+
+	report, err := migrate.MigrateResources(opts)
+	if err != nil {
+		if errors.Is(err, migrate.ErrDryRun) {
+			fmt.Println(report.Summary())
+			printDetails(report)
+			return
+		}
+		fmt.Fprintf(os.Stderr, "migrate-resources failed: %v\n", err)
+		os.Exit(1)
+	}
+
+	fmt.Printf("migrate-resources OK: %s\n", report.Summary())
+	printDetails(report)
+}
+
+func splitList(value string) []string {
+	var items []string
+	for _, item := range strings.Split(value, ",") {
+		if item = strings.TrimSpace(item); item != "" {
+			items = append(items, item)
+		}
+	}
+	return items
+}
+
+func runInitTemplates(args []string) {
+	flags := flag.NewFlagSet("init-templates", flag.ExitOnError)
+	out := flags.String("out", "templates", "directory to write the default templates to")
+	force := flags.Bool("force", false, "overwrite existing template files")
+	flags.Parse(args)
+
+	written, err := migrate.ExportTemplates(*out, *force)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "init-templates failed: %v\n", err)
+		os.Exit(1)
+	}
+
+	for _, file := range written {
+		fmt.Printf("wrote %s\n", file)
+	}
+}
+
+func printDetails(report migrate.Report) {
+	for _, line := range report.Details() {
+		fmt.Printf("  - %s\n", line)
+	}
 }
 
 func usage() {
 	fmt.Fprintln(os.Stderr, "tf-provider-migrate")
 	fmt.Fprintln(os.Stderr, "")
 	fmt.Fprintln(os.Stderr, "Usage:")
-	fmt.Fprintln(os.Stderr, "  tf-provider-migrate check [--path PATH] [--registry-address ADDR] [--provider-name NAME]")
-	fmt.Fprintln(os.Stderr, "  tf-provider-migrate migrate [--path PATH] [--registry-address ADDR] [--provider-name NAME] [--dry-run]")
+	fmt.Fprintln(os.Stderr, "  tf-provider-migrate check [--path PATH] [--config FILE] [--registry-address ADDR] [--provider-name NAME] [--protocol-version 5|6] [--layout-dir DIR] [--layout-package NAME] [--layout-split] [--templates DIR] [--configure shared|independent] [--no-upgrade]")
+	fmt.Fprintln(os.Stderr, "  tf-provider-migrate migrate [--path PATH] [--config FILE] [--registry-address ADDR] [--provider-name NAME] [--protocol-version 5|6] [--layout-dir DIR] [--layout-package NAME] [--layout-split] [--templates DIR] [--configure shared|independent] [--dry-run] [--no-upgrade]")
+	fmt.Fprintln(os.Stderr, "  tf-provider-migrate migrate-resources [--path PATH] [--config FILE] [--resource NAMES] [--data-source NAMES] [--all] [--timeouts block|attributes] [--layout-dir DIR] [--layout-package NAME] [--layout-split] [--templates DIR] [--dry-run]")
+	fmt.Fprintln(os.Stderr, "  tf-provider-migrate init-templates [--out DIR] [--force]")
 	fmt.Fprintln(os.Stderr, "")
 	fmt.Fprintln(os.Stderr, "Commands:")
-	fmt.Fprintln(os.Stderr, "  check    validate provider is suitable for migration")
-	fmt.Fprintln(os.Stderr, "  migrate  add muxing and framework scaffolding")
+	fmt.Fprintln(os.Stderr, "  check              validate provider is suitable for migration")
+	fmt.Fprintln(os.Stderr, "  migrate            add muxing and framework scaffolding")
+	fmt.Fprintln(os.Stderr, "  migrate-resources  move resources and data sources to the framework provider")
+	fmt.Fprintln(os.Stderr, "  init-templates     export the built-in templates for use with --templates")
 }
@@ -3,3 +3,5 @@ module github.com/DanielMSchmidt/tf-provider-migrate
 go 1.22.0
 
 require golang.org/x/mod v0.20.0
+
+require gopkg.in/yaml.v3 v3.0.1
@@ -0,0 +1,425 @@
+package migrate
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+
+	"golang.org/x/mod/semver"
+	"gopkg.in/yaml.v3"
+)
+
+const (
+	configFileName = ".tf-provider-migrate.yaml"
+	envPrefix      = "TF_PROVIDER_MIGRATE_"
+)
+
+// Sources a setting can come from, in increasing order of precedence.
+const (
+	SourceDefault = "default"
+	SourceDerived = "derived"
+	SourceConfig  = "config"
+	SourceEnv     = "env"
+	SourceFlag    = "flag"
+)
+
+// Config is the repository level configuration read from
+// .tf-provider-migrate.yaml.
+type Config struct {
+	ProviderName    string                       `yaml:"provider_name"`
+	RegistryAddress string                       `yaml:"registry_address"`
+	ProtocolVersion int                          `yaml:"protocol_version"`
+	Templates       string                       `yaml:"templates"`
+	Layout          ConfigLayout                 `yaml:"layout"`
+	Timeouts        string                       `yaml:"timeouts"`
+	Configure       string                       `yaml:"configure"`
+	Dependencies    Dependencies                 `yaml:"dependencies"`
+	NoUpgrade       bool                         `yaml:"no_upgrade"`
+	Resources       ConfigSkipList               `yaml:"resources"`
+	DataSources     ConfigSkipList               `yaml:"data_sources"`
+	Attributes      map[string]AttributeOverride `yaml:"attributes"`
+	MaxItemsOne     map[string]string            `yaml:"max_items_one"`
+}
+
+type ConfigLayout struct {
+	Dir     string `yaml:"dir"`
+	Package string `yaml:"package"`
+	Split   bool   `yaml:"split"`
+}
+
+type ConfigSkipList struct {
+	Skip []string `yaml:"skip"`
+}
+
+// Dependencies pins the versions of the modules the generated code requires.
+// Empty fields fall back to versions selected from the SDK version in go.mod.
+type Dependencies struct {
+	Framework string `yaml:"framework"`
+	Mux       string `yaml:"mux"`
+	PluginGo  string `yaml:"plugin_go"`
+	Timeouts  string `yaml:"timeouts"`
+	JSONTypes string `yaml:"jsontypes"`
+	TimeTypes string `yaml:"timetypes"`
+	NetTypes  string `yaml:"nettypes"`
+}
+
+// AttributeOverride replaces parts of the translation of a single attribute.
+// Overrides are keyed by path, e.g. "provider.endpoint" or
+// "provider.auth.token" for an attribute nested in a block, and
+// "example_widget.name" for an attribute of a resource or data source.
+type AttributeOverride struct {
+	// Literal is a Go expression used verbatim as the framework attribute.
+	Literal     string  `yaml:"literal"`
+	Description *string `yaml:"description"`
+	Sensitive   *bool   `yaml:"sensitive"`
+	Skip        bool    `yaml:"skip"`
+}
+
+// Setting records the effective value of an option and where it came from.
+type Setting struct {
+	Name   string
+	Value  string
+	Source string
+}
+
+func (s Setting) String() string {
+	return fmt.Sprintf("%s=%s (%s)", s.Name, s.Value, s.Source)
+}
+
+// findConfigFile looks for the config file in the module root and its parent
+// directories, stopping at the repository root.
+func findConfigFile(moduleRoot string) string {
+	path := moduleRoot
+	for {
+		candidate := filepath.Join(path, configFileName)
+		if fileExists(candidate) {
+			return candidate
+		}
+		if _, err := os.Stat(filepath.Join(path, ".git")); err == nil {
+			return ""
+		}
+
+		parent := filepath.Dir(path)
+		if parent == path {
+			return ""
+		}
+		path = parent
+	}
+}
+
+func loadConfig(path string) (Config, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return Config{}, err
+	}
+
+	var cfg Config
+	dec := yaml.NewDecoder(bytes.NewReader(data))
+	dec.KnownFields(true)
+	if err := dec.Decode(&cfg); err != nil && !errors.Is(err, io.EOF) {
+		return Config{}, fmt.Errorf("%s: %w", path, err)
+	}
+	return cfg, nil
+}
+
+// resolveOptions merges the config file and environment into opts. Values
+// set in opts (from flags) win over the environment, which wins over the
+// config file. Boolean flags can only switch a setting on. served is the
+// protocol of an already muxed main.go, which replaces the default protocol
+// version when set.
+func resolveOptions(opts Options, moduleRoot string, served int, lookupEnv func(string) (string, bool)) (Options, []Setting, error) {
+	configPath, configSource := opts.ConfigFile, SourceFlag
+	if configPath == "" {
+		configPath, configSource = envValue(lookupEnv, "config"), SourceEnv
+	}
+	if configPath == "" {
+		configPath, configSource = findConfigFile(moduleRoot), SourceDefault
+	}
+
+	var cfg Config
+	var settings []Setting
+	if configPath != "" {
+		loaded, err := loadConfig(configPath)
+		if err != nil {
+			return Options{}, nil, err
+		}
+		cfg = loaded
+		settings = append(settings, Setting{Name: "config", Value: configPath, Source: configSource})
+	}
+
+	merge := func(name string, target *string, configValue string) {
+		switch {
+		case *target != "":
+			settings = append(settings, Setting{Name: name, Value: *target, Source: SourceFlag})
+		case envValue(lookupEnv, name) != "":
+			*target = envValue(lookupEnv, name)
+			settings = append(settings, Setting{Name: name, Value: *target, Source: SourceEnv})
+		case configValue != "":
+			*target = configValue
+			settings = append(settings, Setting{Name: name, Value: *target, Source: SourceConfig})
+		}
+	}
+
+	merge("provider_name", &opts.ProviderName, cfg.ProviderName)
+	merge("registry_address", &opts.RegistryAddress, cfg.RegistryAddress)
+	merge("templates", &opts.TemplatesDir, configRelative(configPath, cfg.Templates))
+	merge("layout.dir", &opts.Layout.Dir, cfg.Layout.Dir)
+	merge("layout.package", &opts.Layout.Package, cfg.Layout.Package)
+
+	split := ""
+	if opts.Layout.Split {
+		split = "true"
+	}
+	configSplit := ""
+	if cfg.Layout.Split {
+		configSplit = "true"
+	}
+	merge("layout.split", &split, configSplit)
+	if split != "" {
+		val, err := strconv.ParseBool(split)
+		if err != nil {
+			return Options{}, nil, fmt.Errorf("layout.split: %w", err)
+		}
+		opts.Layout.Split = val
+	}
+
+	protocol := ""
+	if opts.ProtocolVersion != 0 {
+		protocol = strconv.Itoa(opts.ProtocolVersion)
+	}
+	configProtocol := ""
+	if cfg.ProtocolVersion != 0 {
+		configProtocol = strconv.Itoa(cfg.ProtocolVersion)
+	}
+	merge("protocol_version", &protocol, configProtocol)
+	switch {
+	case protocol == "" && served != 0:
+		opts.ProtocolVersion = served
+		settings = append(settings, Setting{Name: "protocol_version", Value: strconv.Itoa(served), Source: SourceDerived})
+	case protocol == "":
+		opts.ProtocolVersion = 5
+		settings = append(settings, Setting{Name: "protocol_version", Value: "5", Source: SourceDefault})
+	default:
+		val, err := strconv.Atoi(protocol)
+		if err != nil || (val != 5 && val != 6) {
+			return Options{}, nil, fmt.Errorf("protocol_version must be 5 or 6, got %q", protocol)
+		}
+		opts.ProtocolVersion = val
+	}
+
+	merge("timeouts", &opts.Timeouts, cfg.Timeouts)
+	switch opts.Timeouts {
+	case "":
+		opts.Timeouts = timeoutsBlock
+	case timeoutsBlock:
+	case timeoutsAttributes:
+		if opts.ProtocolVersion != 6 {
+			return Options{}, nil, fmt.Errorf("timeouts %q requires protocol_version 6", opts.Timeouts)
+		}
+	default:
+		return Options{}, nil, fmt.Errorf("timeouts must be %q or %q, got %q", timeoutsBlock, timeoutsAttributes, opts.Timeouts)
+	}
+
+	merge("configure", &opts.Configure, cfg.Configure)
+	switch opts.Configure {
+	case "":
+		opts.Configure = configureShared
+	case configureShared, configureIndependent:
+	default:
+		return Options{}, nil, fmt.Errorf("configure must be %q or %q, got %q", configureShared, configureIndependent, opts.Configure)
+	}
+
+	merge("dependencies.framework", &opts.Dependencies.Framework, cfg.Dependencies.Framework)
+	merge("dependencies.mux", &opts.Dependencies.Mux, cfg.Dependencies.Mux)
+	merge("dependencies.plugin_go", &opts.Dependencies.PluginGo, cfg.Dependencies.PluginGo)
+	merge("dependencies.timeouts", &opts.Dependencies.Timeouts, cfg.Dependencies.Timeouts)
+	merge("dependencies.jsontypes", &opts.Dependencies.JSONTypes, cfg.Dependencies.JSONTypes)
+	merge("dependencies.timetypes", &opts.Dependencies.TimeTypes, cfg.Dependencies.TimeTypes)
+	merge("dependencies.nettypes", &opts.Dependencies.NetTypes, cfg.Dependencies.NetTypes)
+	for _, version := range []string{opts.Dependencies.Framework, opts.Dependencies.Mux, opts.Dependencies.PluginGo, opts.Dependencies.Timeouts, opts.Dependencies.JSONTypes, opts.Dependencies.TimeTypes, opts.Dependencies.NetTypes} {
+		if version != "" && !semver.IsValid(version) {
+			return Options{}, nil, fmt.Errorf("dependency version %q is not a valid semantic version", version)
+		}
+	}
+
+	noUpgrade := ""
+	if opts.NoUpgrade {
+		noUpgrade = "true"
+	}
+	configNoUpgrade := ""
+	if cfg.NoUpgrade {
+		configNoUpgrade = "true"
+	}
+	merge("no_upgrade", &noUpgrade, configNoUpgrade)
+	if noUpgrade != "" {
+		val, err := strconv.ParseBool(noUpgrade)
+		if err != nil {
+			return Options{}, nil, fmt.Errorf("no_upgrade: %w", err)
+		}
+		opts.NoUpgrade = val
+	}
+
+	if len(opts.SkipResources) == 0 && len(cfg.Resources.Skip) > 0 {
+		opts.SkipResources = cfg.Resources.Skip
+		settings = append(settings, Setting{Name: "resources.skip", Value: fmt.Sprint(opts.SkipResources), Source: SourceConfig})
+	}
+	if len(opts.SkipDataSources) == 0 && len(cfg.DataSources.Skip) > 0 {
+		opts.SkipDataSources = cfg.DataSources.Skip
+		settings = append(settings, Setting{Name: "data_sources.skip", Value: fmt.Sprint(opts.SkipDataSources), Source: SourceConfig})
+	}
+	if opts.AttributeOverrides == nil && len(cfg.Attributes) > 0 {
+		opts.AttributeOverrides = cfg.Attributes
+		settings = append(settings, Setting{Name: "attributes", Value: fmt.Sprintf("%d overrides", len(cfg.Attributes)), Source: SourceConfig})
+	}
+	if opts.MaxItemsOne == nil && len(cfg.MaxItemsOne) > 0 {
+		opts.MaxItemsOne = cfg.MaxItemsOne
+		settings = append(settings, Setting{Name: "max_items_one", Value: fmt.Sprintf("%d blocks", len(cfg.MaxItemsOne)), Source: SourceConfig})
+	}
+	for key, strategy := range opts.MaxItemsOne {
+		switch strategy {
+		case maxItemsOneList, maxItemsOneSingleBlock:
+		case maxItemsOneSingleAttribute:
+			if opts.ProtocolVersion != 6 {
+				return Options{}, nil, fmt.Errorf("max_items_one %s: %q requires protocol_version 6", key, strategy)
+			}
+		default:
+			return Options{}, nil, fmt.Errorf("max_items_one %s must be %q, %q or %q, got %q", key, maxItemsOneList, maxItemsOneSingleBlock, maxItemsOneSingleAttribute, strategy)
+		}
+	}
+
+	return opts, settings, nil
+}
+
+// envValue reads the environment variable for a setting name, e.g.
+// TF_PROVIDER_MIGRATE_LAYOUT_DIR for "layout.dir".
+func envValue(lookupEnv func(string) (string, bool), name string) string {
+	key := envPrefix + strings.ToUpper(strings.ReplaceAll(name, ".", "_"))
+	val, _ := lookupEnv(key)
+	return val
+}
+
+// applyAttributeOverrides applies the overrides keyed "provider.*" to the
+// provider schema. Other keys must name a registered resource or data source;
+// migrate-resources applies them with applyResourceOverrides.
+func applyAttributeOverrides(info *ProviderInfo, overrides map[string]AttributeOverride) error {
+	if len(overrides) == 0 {
+		return nil
+	}
+
+	used := map[string]bool{}
+	info.Attributes, info.Blocks = overrideSchema("provider", info.Attributes, info.Blocks, overrides, used)
+
+	scopes := map[string]bool{}
+	for _, ref := range info.Resources {
+		scopes[ref.Name] = true
+	}
+	for _, ref := range info.DataSources {
+		scopes[ref.Name] = true
+	}
+
+	keys := make([]string, 0, len(overrides))
+	for key := range overrides {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	for _, key := range keys {
+		if used[key] {
+			continue
+		}
+		scope, _, _ := strings.Cut(key, ".")
+		if scope == "provider" {
+			return fmt.Errorf("attribute override %q does not match a provider attribute", key)
+		}
+		if !scopes[scope] {
+			return fmt.Errorf("attribute override %q does not match the provider, a resource or a data source", key)
+		}
+	}
+
+	return nil
+}
+
+// applyResourceOverrides applies the overrides keyed by the type name of a
+// resource or data source to its schema, including nested blocks, and drops
+// the mappings of the attributes an override skips or replaces by a literal.
+// used collects the keys that matched.
+func applyResourceOverrides(info *ResourceInfo, overrides map[string]AttributeOverride, used map[string]bool) {
+	if len(overrides) == 0 {
+		return
+	}
+
+	info.Attributes, info.Blocks = overrideSchema(info.Name, info.Attributes, info.Blocks, overrides, used)
+	var mappings []SchemaMapping
+	for _, mapping := range info.Mappings {
+		if override, ok := overrides[info.Name+"."+mapping.Path]; ok && (override.Skip || override.Literal != "") {
+			continue
+		}
+		mappings = append(mappings, mapping)
+	}
+	info.Mappings = mappings
+}
+
+// checkResourceOverrides reports an override of a migrated resource or data
+// source that matched none of its attributes.
+func checkResourceOverrides(overrides map[string]AttributeOverride, migrated, used map[string]bool) error {
+	keys := make([]string, 0, len(overrides))
+	for key := range overrides {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	for _, key := range keys {
+		scope, _, _ := strings.Cut(key, ".")
+		if migrated[scope] && !used[key] {
+			return fmt.Errorf("attribute override %q does not match an attribute of %s", key, scope)
+		}
+	}
+	return nil
+}
+
+func overrideSchema(prefix string, attrs []Attribute, blocks []Block, overrides map[string]AttributeOverride, used map[string]bool) ([]Attribute, []Block) {
+	attrs = overrideAttributes(prefix, attrs, overrides, used)
+	overridden := make([]Block, len(blocks))
+	for i, block := range blocks {
+		block.Attributes, block.Blocks = overrideSchema(prefix+"."+block.Name, block.Attributes, block.Blocks, overrides, used)
+		overridden[i] = block
+	}
+	return attrs, overridden
+}
+
+func overrideAttributes(prefix string, attrs []Attribute, overrides map[string]AttributeOverride, used map[string]bool) []Attribute {
+	result := make([]Attribute, 0, len(attrs))
+	for _, attr := range attrs {
+		key := prefix + "." + attr.Name
+		override, ok := overrides[key]
+		if !ok {
+			result = append(result, attr)
+			continue
+		}
+
+		used[key] = true
+		if override.Skip {
+			continue
+		}
+		attr.Literal = override.Literal
+		if override.Description != nil {
+			attr.Description = *override.Description
+		}
+		if override.Sensitive != nil {
+			attr.Sensitive = *override.Sensitive
+		}
+		result = append(result, attr)
+	}
+	return result
+}
+
+func configRelative(configPath, value string) string {
+	if value == "" || configPath == "" || filepath.IsAbs(value) {
+		return value
+	}
+	return filepath.Join(filepath.Dir(configPath), value)
+}
@@ -0,0 +1,105 @@
+package migrate
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestApplyAttributeOverrides(t *testing.T) {
+	t.Parallel()
+
+	schema := func() ProviderInfo {
+		return ProviderInfo{
+			Attributes: []Attribute{{Name: "token", Type: "string", Optional: true}},
+			Blocks: []Block{{
+				Name:       "auth",
+				Attributes: []Attribute{{Name: "user", Type: "string", Optional: true}},
+				Blocks: []Block{{
+					Name:       "oidc",
+					Attributes: []Attribute{{Name: "secret", Type: "string", Optional: true}},
+				}},
+			}},
+			Resources: []ResourceRef{{Name: "example_widget"}},
+		}
+	}
+	sensitive := true
+
+	tests := []struct {
+		name     string
+		key      string
+		override AttributeOverride
+		// attr selects the overridden attribute in the result.
+		attr    func(ProviderInfo) []Attribute
+		wantErr string
+	}{
+		{
+			name:     "top-level attribute",
+			key:      "provider.token",
+			override: AttributeOverride{Sensitive: &sensitive},
+			attr:     func(info ProviderInfo) []Attribute { return info.Attributes },
+		},
+		{
+			name:     "block attribute",
+			key:      "provider.auth.user",
+			override: AttributeOverride{Sensitive: &sensitive},
+			attr:     func(info ProviderInfo) []Attribute { return info.Blocks[0].Attributes },
+		},
+		{
+			name:     "nested block attribute",
+			key:      "provider.auth.oidc.secret",
+			override: AttributeOverride{Sensitive: &sensitive},
+			attr:     func(info ProviderInfo) []Attribute { return info.Blocks[0].Blocks[0].Attributes },
+		},
+		{
+			name:     "skipped nested block attribute",
+			key:      "provider.auth.oidc.secret",
+			override: AttributeOverride{Skip: true},
+			attr:     func(info ProviderInfo) []Attribute { return info.Blocks[0].Blocks[0].Attributes },
+		},
+		{
+			name:     "resource attribute left to migrate-resources",
+			key:      "example_widget.name",
+			override: AttributeOverride{Skip: true},
+		},
+		{
+			name:     "unknown provider attribute",
+			key:      "provider.auth.oidc.nope",
+			override: AttributeOverride{Skip: true},
+			wantErr:  `attribute override "provider.auth.oidc.nope" does not match a provider attribute`,
+		},
+		{
+			name:     "unknown scope",
+			key:      "example_gadget.name",
+			override: AttributeOverride{Skip: true},
+			wantErr:  `attribute override "example_gadget.name" does not match the provider, a resource or a data source`,
+		},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			info := schema()
+			err := applyAttributeOverrides(&info, map[string]AttributeOverride{tt.key: tt.override})
+			if tt.wantErr != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+					t.Fatalf("expected an error containing %q, got %v", tt.wantErr, err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("apply overrides: %v", err)
+			}
+			if tt.attr == nil {
+				return
+			}
+			attrs := tt.attr(info)
+			switch {
+			case tt.override.Skip && len(attrs) != 0:
+				t.Fatalf("expected %s to be skipped, got %+v", tt.key, attrs)
+			case !tt.override.Skip && (len(attrs) != 1 || !attrs[0].Sensitive):
+				t.Fatalf("expected %s to be Sensitive, got %+v", tt.key, attrs)
+			}
+		})
+	}
+}
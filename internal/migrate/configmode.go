@@ -0,0 +1,205 @@
+package migrate
+
+import (
+	"bytes"
+	"fmt"
+	"strings"
+)
+
+const (
+	configModeAttr  = "attr"
+	configModeBlock = "block"
+
+	// attributeModeNested renders a block as a list or set nested attribute,
+	// which needs protocol version 6.
+	attributeModeNested = "nested"
+	// attributeModeObject renders a block as a list or set attribute of
+	// types.ObjectType, for protocol version 5.
+	attributeModeObject = "object"
+)
+
+// computedOnly reports whether the SDK schema of the block is Computed but
+// neither Optional nor Required.
+func (b Block) computedOnly() bool {
+	return b.Computed && !b.Optional && !b.Required
+}
+
+// isAttribute reports whether Terraform sees the block as an attribute: its
+// ConfigMode is SchemaConfigModeAttr, or it is computed-only and not forced
+// to be a block.
+func (b Block) isAttribute() bool {
+	return b.ConfigMode == configModeAttr || (b.ConfigMode == "" && b.computedOnly())
+}
+
+// computedSchema marks the nested schema of a computed-only block computed,
+// since the SDK ignores any other flag there.
+func computedSchema(attrs []Attribute, blocks []Block) ([]Attribute, []Block) {
+	computedAttrs := make([]Attribute, len(attrs))
+	for i, attr := range attrs {
+		attr.Optional, attr.Required, attr.Computed = false, false, true
+		computedAttrs[i] = attr
+	}
+	computedBlocks := make([]Block, len(blocks))
+	for i, block := range blocks {
+		block.Optional, block.Required, block.Computed = false, false, true
+		block.Attributes, block.Blocks = computedSchema(block.Attributes, block.Blocks)
+		computedBlocks[i] = block
+	}
+	return computedAttrs, computedBlocks
+}
+
+// applyConfigModes sets the AttributeMode of the blocks of info, and of the
+// prior schemas of its state upgraders, that Terraform sees as attributes.
+// Each block that becomes an attribute is recorded in the mappings.
+func applyConfigModes(info *ResourceInfo, protocolVersion int) {
+	info.Blocks = configModeBlocks("", info.Blocks, protocolVersion, "", &info.Mappings)
+	for i := range info.StateUpgrades {
+		upgrade := &info.StateUpgrades[i]
+		upgrade.Blocks = configModeBlocks("", upgrade.Blocks, protocolVersion, "", nil)
+	}
+}
+
+// configModeBlocks returns a copy of blocks with the AttributeMode set for
+// the protocol version. Blocks nested in an attribute are attributes too, so
+// they inherit its mode. mappings may be nil.
+func configModeBlocks(prefix string, blocks []Block, protocolVersion int, inherited string, mappings *[]SchemaMapping) []Block {
+	mode := attributeModeObject
+	if protocolVersion == 6 {
+		mode = attributeModeNested
+	}
+
+	moded := make([]Block, len(blocks))
+	for i, block := range blocks {
+		block.AttributeMode = inherited
+		if inherited == "" && block.isAttribute() {
+			block.AttributeMode = mode
+			if mappings != nil {
+				*mappings = append(*mappings, configModeMapping(prefix, block))
+			}
+		}
+		block.Blocks = configModeBlocks(prefix+block.Name+".", block.Blocks, protocolVersion, block.AttributeMode, mappings)
+		moded[i] = block
+	}
+	return moded
+}
+
+func configModeMapping(prefix string, block Block) SchemaMapping {
+	field := "ConfigMode: SchemaConfigModeAttr"
+	position := block.Fields["ConfigMode"]
+	if block.ConfigMode == "" {
+		field = "Computed"
+		position = block.Fields["Computed"]
+	}
+
+	kind := "List"
+	if block.Kind == "set" {
+		kind = "Set"
+	}
+	result := fmt.Sprintf("schema.%sNestedAttribute", kind)
+	if block.AttributeMode == attributeModeObject {
+		result = fmt.Sprintf("schema.%sAttribute of types.ObjectType (protocol 5 object attributes drop the flags, plan modifiers and defaults of nested attributes; protocol 6 keeps them as a nested attribute)", kind)
+	}
+	return SchemaMapping{Path: prefix + block.Name, Field: field, Position: position, Result: result}
+}
+
+// splitSchemaBlocks separates the blocks declared in the Blocks of a schema
+// from those declared with its Attributes: single nested attributes and
+// blocks rendered as attributes.
+func splitSchemaBlocks(blocks []Block) (schemaBlocks, nestedAttrs []Block) {
+	for _, block := range blocks {
+		if block.Strategy == maxItemsOneSingleAttribute || block.AttributeMode != "" {
+			nestedAttrs = append(nestedAttrs, block)
+		} else {
+			schemaBlocks = append(schemaBlocks, block)
+		}
+	}
+	return schemaBlocks, nestedAttrs
+}
+
+// renderAttributeBlockLiteral renders a block with an AttributeMode, e.g.
+// schema.ListNestedAttribute{...} or
+// schema.ListAttribute{ElementType: types.ObjectType{...}}.
+func renderAttributeBlockLiteral(block Block) string {
+	kind := "List"
+	if block.Kind == "set" {
+		kind = "Set"
+	}
+
+	var buf bytes.Buffer
+	if block.AttributeMode == attributeModeObject {
+		fmt.Fprintf(&buf, "schema.%sAttribute{", kind)
+	} else {
+		fmt.Fprintf(&buf, "schema.%sNestedAttribute{", kind)
+	}
+	if block.Description != "" {
+		fmt.Fprintf(&buf, "Description: %q,", block.Description)
+	}
+	switch {
+	case block.Required:
+		buf.WriteString("Required: true,")
+	case block.Optional && block.Computed:
+		buf.WriteString("Optional: true,Computed: true,")
+	case block.Computed:
+		buf.WriteString("Computed: true,")
+	default:
+		buf.WriteString("Optional: true,")
+	}
+	if block.Sensitive {
+		buf.WriteString("Sensitive: true,")
+	}
+	if len(block.PlanModifiers) > 0 {
+		fmt.Fprintf(&buf, "PlanModifiers: []planmodifier.%s{%s},", kind, strings.Join(block.PlanModifiers, ", "))
+	}
+
+	if block.AttributeMode == attributeModeObject {
+		fmt.Fprintf(&buf, "ElementType: %s,", renderObjectType(block))
+		buf.WriteString("}")
+		return buf.String()
+	}
+
+	buf.WriteString("NestedObject: schema.NestedAttributeObject{Attributes: map[string]schema.Attribute{")
+	for _, attr := range sortedAttributes(block.Attributes) {
+		fmt.Fprintf(&buf, "%q: %s,", attr.Name, renderAttributeLiteral(attr))
+	}
+	for _, nested := range sortedBlocks(block.Blocks) {
+		fmt.Fprintf(&buf, "%q: %s,", nested.Name, renderBlockLiteral(nested))
+	}
+	buf.WriteString("}},}")
+	return buf.String()
+}
+
+// renderObjectType renders the object type of the elements of a block, e.g.
+// types.ObjectType{AttrTypes: map[string]attr.Type{"name": types.StringType}}.
+func renderObjectType(block Block) string {
+	var fields []string
+	for _, attr := range sortedAttributes(block.Attributes) {
+		fields = append(fields, fmt.Sprintf("%q: %s", attr.Name, renderAttrType(attr)))
+	}
+	for _, nested := range sortedBlocks(block.Blocks) {
+		collection := "ListType"
+		if nested.Kind == "set" {
+			collection = "SetType"
+		}
+		fields = append(fields, fmt.Sprintf("%q: types.%s{ElemType: %s}", nested.Name, collection, renderObjectType(nested)))
+	}
+	return fmt.Sprintf("types.ObjectType{AttrTypes: map[string]attr.Type{%s}}", strings.Join(fields, ", "))
+}
+
+// renderAttrType renders the attr.Type of an attribute, e.g. types.StringType.
+func renderAttrType(attr Attribute) string {
+	if attr.CustomType != "" {
+		return attr.CustomType
+	}
+	return renderElemType(&ElemType{Type: attr.Type, Elem: attr.ElemType})
+}
+
+// usesObjectTypes reports whether any block is rendered with an object type,
+// which refers to the attr package.
+func usesObjectTypes(blocks []Block) bool {
+	for _, block := range blocks {
+		if block.AttributeMode == attributeModeObject || usesObjectTypes(block.Blocks) {
+			return true
+		}
+	}
+	return false
+}
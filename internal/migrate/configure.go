@@ -0,0 +1,277 @@
+package migrate
+
+import (
+	"fmt"
+	"go/ast"
+	"go/token"
+	"go/types"
+	"strconv"
+)
+
+const (
+	// configureShared hands the meta value of the SDK provider to the
+	// framework resources and data sources; configureIndependent ports the
+	// SDK configure function to the framework Configure.
+	configureShared      = "shared"
+	configureIndependent = "independent"
+)
+
+// MetaType is the concrete type of the meta value returned by the SDK
+// provider's ConfigureContextFunc or ConfigureFunc, e.g. *Client declared in
+// the provider package. Framework resources and data sources get it typed
+// from the provider data in Configure.
+type MetaType struct {
+	Name    string
+	Pointer bool
+	// Import is the import path of the package declaring the type and
+	// Package its name.
+	Import  string
+	Package string
+}
+
+// alias is the name the generated code imports the package of the type as.
+func (m MetaType) alias() string {
+	return packageAlias(m.Package)
+}
+
+// packageAlias is the name the generated code imports a package of the
+// module named pkg as, avoiding the packages the templates import.
+func packageAlias(pkg string) string {
+	switch pkg {
+	case "provider", "resource", "datasource", "schema", "types", "diag", "path", "context", "fmt", "sync":
+		return "sdk" + pkg
+	default:
+		return pkg
+	}
+}
+
+// typeExpr is the type as generated code refers to it, e.g. *sdkprovider.Client.
+func (m MetaType) typeExpr() string {
+	expr := m.alias() + "." + m.Name
+	if m.Pointer {
+		expr = "*" + expr
+	}
+	return expr
+}
+
+// importSpec is the import of the package declaring the type.
+func (m MetaType) importSpec() string {
+	if m.alias() != importName(m.Import) {
+		return m.alias() + " " + strconv.Quote(m.Import)
+	}
+	return strconv.Quote(m.Import)
+}
+
+// metaTypeData returns the MetaType and MetaImport template values, both
+// empty when the meta type is unknown.
+func metaTypeData(meta *MetaType) (string, string) {
+	if meta == nil {
+		return "", ""
+	}
+	return meta.typeExpr(), meta.importSpec()
+}
+
+// parseConfigureFunc finds the concrete type of the meta value the SDK
+// configure function returns: a function literal or a function of the
+// module. Every return statement returning a value other than nil must
+// agree on the type.
+func parseConfigureFunc(expr ast.Expr, res resolver) (*MetaType, error) {
+	var body *ast.BlockStmt
+	if lit, ok := expr.(*ast.FuncLit); ok {
+		body = lit.Body
+	} else if fn := res.lookupFunc(expr); fn != nil && fn.Body != nil {
+		body = fn.Body
+	} else {
+		return nil, fmt.Errorf("the function is not declared in the module")
+	}
+
+	var meta *MetaType
+	var err error
+	ast.Inspect(body, func(n ast.Node) bool {
+		if err != nil {
+			return false
+		}
+		switch v := n.(type) {
+		case *ast.FuncLit:
+			return false
+		case *ast.ReturnStmt:
+			if len(v.Results) == 0 || isIdent(v.Results[0], "nil") {
+				return false
+			}
+			var found *MetaType
+			found, err = res.valueType(v.Results[0], body)
+			if err == nil && meta != nil && *found != *meta {
+				err = fmt.Errorf("returns both %s and %s", meta.typeExpr(), found.typeExpr())
+			}
+			meta = found
+			return false
+		}
+		return true
+	})
+	if err != nil {
+		return nil, err
+	}
+	if meta == nil {
+		return nil, fmt.Errorf("the function never returns a meta value")
+	}
+	return meta, nil
+}
+
+// valueType returns the type of a composite literal, the address of one, a
+// local variable of scope, or the result of a function of the module.
+func (res resolver) valueType(expr ast.Expr, scope *ast.BlockStmt) (*MetaType, error) {
+	switch v := expr.(type) {
+	case *ast.UnaryExpr:
+		if lit, ok := v.X.(*ast.CompositeLit); ok && v.Op == token.AND {
+			meta, err := res.declaredType(lit.Type)
+			if err != nil {
+				return nil, err
+			}
+			meta.Pointer = true
+			return meta, nil
+		}
+	case *ast.CompositeLit:
+		return res.declaredType(v.Type)
+	case *ast.Ident:
+		for _, stmt := range scope.List {
+			if value := localValue(stmt, v.Name); value != nil {
+				return res.valueType(value, scope)
+			}
+			if typ := localType(stmt, v.Name); typ != nil {
+				return res.declaredType(typ)
+			}
+		}
+		return nil, fmt.Errorf("%s is not declared in the function body", v.Name)
+	case *ast.CallExpr:
+		fn := res.lookupFunc(v.Fun)
+		if fn == nil || fn.Type.Results == nil || len(fn.Type.Results.List) == 0 {
+			return nil, fmt.Errorf("%s is not a function of the module", types.ExprString(v.Fun))
+		}
+		return res.declaredType(fn.Type.Results.List[0].Type)
+	}
+	return nil, fmt.Errorf("%s is not a literal, local variable or function call", types.ExprString(expr))
+}
+
+// declaredType resolves a type expression, such as *Client or *api.Client,
+// to the package declaring it.
+func (res resolver) declaredType(expr ast.Expr) (*MetaType, error) {
+	switch v := expr.(type) {
+	case *ast.StarExpr:
+		meta, err := res.declaredType(v.X)
+		if err != nil {
+			return nil, err
+		}
+		meta.Pointer = true
+		return meta, nil
+	case *ast.Ident:
+		if !res.typeNames[v.Name] {
+			return nil, fmt.Errorf("type %s is not declared in the module", v.Name)
+		}
+		tokenFile := res.fset.File(v.Pos())
+		importPath, ok := res.packages[tokenFile.Name()]
+		if !ok {
+			return nil, fmt.Errorf("the package declaring %s has no import path", v.Name)
+		}
+		if !ast.IsExported(v.Name) {
+			return nil, fmt.Errorf("type %s is unexported", v.Name)
+		}
+		return &MetaType{Name: v.Name, Import: importPath, Package: res.files[tokenFile.Name()].Name.Name}, nil
+	case *ast.SelectorExpr:
+		pkg, ok := v.X.(*ast.Ident)
+		if !ok {
+			break
+		}
+		importPath, ok := res.importPath(pkg)
+		if !ok {
+			return nil, fmt.Errorf("package %s is not imported", pkg.Name)
+		}
+		return &MetaType{Name: v.Sel.Name, Import: importPath, Package: importName(importPath)}, nil
+	}
+	return nil, fmt.Errorf("type %s is not a named type", types.ExprString(expr))
+}
+
+// localType returns the type stmt declares name with, as in var c *Client.
+func localType(stmt ast.Stmt, name string) ast.Expr {
+	decl, ok := stmt.(*ast.DeclStmt)
+	if !ok {
+		return nil
+	}
+	gen, ok := decl.Decl.(*ast.GenDecl)
+	if !ok || gen.Tok != token.VAR {
+		return nil
+	}
+	for _, spec := range gen.Specs {
+		valueSpec, ok := spec.(*ast.ValueSpec)
+		if !ok || valueSpec.Type == nil {
+			continue
+		}
+		for _, ident := range valueSpec.Names {
+			if ident.Name == name {
+				return valueSpec.Type
+			}
+		}
+	}
+	return nil
+}
+
+// qualifyPackage lets t call the exported functions and use the exported
+// types and constants of the package declaring the SDK function in file,
+// which the generated code imports under packageAlias.
+func (t *translator) qualifyPackage(res resolver, file string) {
+	importPath, ok := res.packages[file]
+	if !ok || res.files[file] == nil {
+		return
+	}
+	t.pkgNames = map[string]bool{}
+	for name, f := range res.files {
+		if res.packages[name] != importPath {
+			continue
+		}
+		for _, decl := range f.Decls {
+			switch d := decl.(type) {
+			case *ast.FuncDecl:
+				if d.Recv == nil {
+					t.pkgNames[d.Name.Name] = true
+				}
+			case *ast.GenDecl:
+				for _, name := range declaredNames(&ast.DeclStmt{Decl: d}) {
+					t.pkgNames[name] = true
+				}
+			}
+		}
+	}
+	t.pkgAlias = packageAlias(res.files[file].Name.Name)
+	t.pkgImport = importPath
+}
+
+// translateConfigure ports the SDK configure function of info to the body of
+// the framework Configure method for --configure independent. The provider
+// configuration is read into the config model and the meta value the
+// function returns becomes the provider data.
+func translateConfigure(info ProviderInfo, providerName string) (crudTranslation, []string, error) {
+	fn := info.configure
+	if fn.field == "" {
+		return crudTranslation{}, nil, fmt.Errorf("configure %s: the SDK provider has no ConfigureContextFunc or ConfigureFunc", configureIndependent)
+	}
+	if fn.file == nil {
+		return crudTranslation{}, nil, fmt.Errorf("configure %s: %s %s is not declared in the module", configureIndependent, fn.field, fn.name)
+	}
+	scope := ResourceInfo{Name: providerName, Attributes: info.Attributes}
+	translation, err := translateCRUD(fn, "configure", scope, info.res)
+	if err != nil {
+		return crudTranslation{}, nil, fmt.Errorf("configure %s: %s: %w", configureIndependent, fn.field, err)
+	}
+	var notes []string
+	if translation.TODOs > 0 {
+		notes = append(notes, fmt.Sprintf("provider configure: %d statements of %s left as TODO(migrate)", translation.TODOs, fn.field))
+	}
+	return translation, notes, nil
+}
+
+// metaTypeNotes describes how framework resources get the meta value.
+func metaTypeNotes(info ProviderInfo) []string {
+	if info.MetaType == nil {
+		return nil
+	}
+	return []string{fmt.Sprintf("provider: the SDK provider configures a %s; framework resources and data sources get it from the provider data with providerMeta in Configure", info.MetaType.typeExpr())}
+}
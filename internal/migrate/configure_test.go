@@ -0,0 +1,161 @@
+package migrate
+
+import (
+	"go/ast"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestParseConfigureFunc(t *testing.T) {
+	t.Parallel()
+
+	res := testResolver(t, map[string]string{
+		"provider/provider.go": `package provider
+
+import (
+	"context"
+	"net/http"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+
+	"example.com/terraform-provider-example/internal/api"
+)
+
+type Client struct{}
+
+type Other struct{}
+
+type client struct{}
+
+func newClient() *Client {
+	return &Client{}
+}
+
+func configureLiteral(ctx context.Context, d *schema.ResourceData) (interface{}, diag.Diagnostics) {
+	if d.Get("endpoint").(string) == "" {
+		return nil, diag.Errorf("endpoint is required")
+	}
+	return &Client{}, nil
+}
+
+func configureValue(ctx context.Context, d *schema.ResourceData) (interface{}, diag.Diagnostics) {
+	c := Client{}
+	return c, nil
+}
+
+func configureDeclared(ctx context.Context, d *schema.ResourceData) (interface{}, diag.Diagnostics) {
+	var c *Client
+	return c, nil
+}
+
+func configureCall(ctx context.Context, d *schema.ResourceData) (interface{}, diag.Diagnostics) {
+	retry := func() error { return nil }
+	_ = retry
+	return newClient(), nil
+}
+
+func configurePackage(ctx context.Context, d *schema.ResourceData) (interface{}, diag.Diagnostics) {
+	return api.New(), nil
+}
+
+func configurePackageType(ctx context.Context, d *schema.ResourceData) (interface{}, diag.Diagnostics) {
+	var c *api.Client
+	return c, nil
+}
+
+var configureFuncLit = func(ctx context.Context, d *schema.ResourceData) (interface{}, diag.Diagnostics) {
+	return &Client{}, nil
+}
+
+func configureNone(ctx context.Context, d *schema.ResourceData) (interface{}, diag.Diagnostics) {
+	return nil, nil
+}
+
+func configureMixed(ctx context.Context, d *schema.ResourceData) (interface{}, diag.Diagnostics) {
+	if d.Get("legacy").(bool) {
+		return &Other{}, nil
+	}
+	return &Client{}, nil
+}
+
+func configureUnexported(ctx context.Context, d *schema.ResourceData) (interface{}, diag.Diagnostics) {
+	return &client{}, nil
+}
+
+func configureExternal(ctx context.Context, d *schema.ResourceData) (interface{}, diag.Diagnostics) {
+	return http.DefaultClient, nil
+}
+`,
+		"internal/api/api.go": `package api
+
+type Client struct{}
+
+func New() *Client {
+	return &Client{}
+}
+`,
+	})
+
+	var funcLit ast.Expr
+	for _, file := range res.files {
+		ast.Inspect(file, func(n ast.Node) bool {
+			if spec, ok := n.(*ast.ValueSpec); ok && spec.Names[0].Name == "configureFuncLit" {
+				funcLit = spec.Values[0]
+			}
+			return funcLit == nil
+		})
+	}
+
+	providerType := func(name string, pointer bool) *MetaType {
+		return &MetaType{Name: name, Pointer: pointer, Import: testModulePath + "/provider", Package: "provider"}
+	}
+	tests := []struct {
+		name    string
+		expr    ast.Expr
+		want    *MetaType
+		wantErr string
+	}{
+		{name: "composite literal", expr: ast.NewIdent("configureLiteral"), want: providerType("Client", true)},
+		{name: "local value", expr: ast.NewIdent("configureValue"), want: providerType("Client", false)},
+		{name: "declared local", expr: ast.NewIdent("configureDeclared"), want: providerType("Client", true)},
+		{name: "module function", expr: ast.NewIdent("configureCall"), want: providerType("Client", true)},
+		{
+			name: "function of another package",
+			expr: ast.NewIdent("configurePackage"),
+			want: &MetaType{Name: "Client", Pointer: true, Import: testModulePath + "/internal/api", Package: "api"},
+		},
+		{
+			name: "type of another package",
+			expr: ast.NewIdent("configurePackageType"),
+			want: &MetaType{Name: "Client", Pointer: true, Import: testModulePath + "/internal/api", Package: "api"},
+		},
+		{name: "function literal", expr: funcLit, want: providerType("Client", true)},
+		{name: "not declared", expr: ast.NewIdent("configureMissing"), wantErr: "the function is not declared in the module"},
+		{name: "no meta value", expr: ast.NewIdent("configureNone"), wantErr: "the function never returns a meta value"},
+		{name: "different types", expr: ast.NewIdent("configureMixed"), wantErr: "returns both *sdkprovider.Other and *sdkprovider.Client"},
+		{name: "unexported type", expr: ast.NewIdent("configureUnexported"), wantErr: "type client is unexported"},
+		{name: "value of another module", expr: ast.NewIdent("configureExternal"), wantErr: "http.DefaultClient is not a literal, local variable or function call"},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			got, err := parseConfigureFunc(tt.expr, res)
+			if tt.wantErr != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+					t.Fatalf("expected an error containing %q, got %v", tt.wantErr, err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("parse configure function: %v", err)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Fatalf("unexpected meta type %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+}
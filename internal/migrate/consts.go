@@ -0,0 +1,366 @@
+package migrate
+
+import (
+	"fmt"
+	"go/ast"
+	"go/constant"
+	"go/token"
+	"strconv"
+	"strings"
+)
+
+// basicTypes are the predeclared types a constant may be converted to.
+var basicTypes = map[string]bool{
+	"string": true, "bool": true,
+	"int": true, "int8": true, "int16": true, "int32": true, "int64": true,
+	"uint": true, "uint8": true, "uint16": true, "uint32": true, "uint64": true,
+	"float32": true, "float64": true, "byte": true, "rune": true,
+}
+
+// constValue evaluates expr as a constant: literals, package level
+// constants of the module, conversions, operators, and fmt.Sprintf,
+// fmt.Sprint and strings.Join over constant arguments.
+func (res resolver) constValue(expr ast.Expr) (constant.Value, bool) {
+	return res.evalConst(expr, map[ast.Expr]bool{})
+}
+
+// evalConst evaluates expr; seen holds the values of the constants being
+// evaluated, so that a cycle fails instead of recursing forever.
+func (res resolver) evalConst(expr ast.Expr, seen map[ast.Expr]bool) (constant.Value, bool) {
+	switch v := expr.(type) {
+	case *ast.BasicLit:
+		value := constant.MakeFromLiteral(v.Value, v.Kind, 0)
+		return value, value.Kind() != constant.Unknown
+	case *ast.ParenExpr:
+		return res.evalConst(v.X, seen)
+	case *ast.Ident:
+		if v.Name == "true" || v.Name == "false" {
+			return constant.MakeBool(v.Name == "true"), true
+		}
+		return res.evalNamedConst(res.consts[v.Name], seen)
+	case *ast.SelectorExpr:
+		pkg, ok := v.X.(*ast.Ident)
+		if !ok {
+			return nil, false
+		}
+		importPath, ok := res.importPath(pkg)
+		if !ok {
+			return nil, false
+		}
+		return res.evalNamedConst(res.pkgConsts[importPath+"."+v.Sel.Name], seen)
+	case *ast.UnaryExpr:
+		x, ok := res.evalConst(v.X, seen)
+		if !ok {
+			return nil, false
+		}
+		switch {
+		case v.Op == token.NOT && x.Kind() == constant.Bool,
+			(v.Op == token.SUB || v.Op == token.ADD) && isNumeric(x),
+			v.Op == token.XOR && x.Kind() == constant.Int:
+			return constant.UnaryOp(v.Op, x, 0), true
+		}
+		return nil, false
+	case *ast.BinaryExpr:
+		x, ok := res.evalConst(v.X, seen)
+		if !ok {
+			return nil, false
+		}
+		y, ok := res.evalConst(v.Y, seen)
+		if !ok {
+			return nil, false
+		}
+		return binaryConst(v.Op, x, y)
+	case *ast.CallExpr:
+		return res.evalCall(v, seen)
+	}
+	return nil, false
+}
+
+// evalNamedConst evaluates the declared value of a constant, or fails when
+// value is nil because the identifier is not a constant of the module.
+func (res resolver) evalNamedConst(value ast.Expr, seen map[ast.Expr]bool) (constant.Value, bool) {
+	if value == nil || seen[value] {
+		return nil, false
+	}
+	seen[value] = true
+	defer delete(seen, value)
+	return res.evalConst(value, seen)
+}
+
+func binaryConst(op token.Token, x, y constant.Value) (constant.Value, bool) {
+	switch op {
+	case token.EQL, token.NEQ, token.LSS, token.LEQ, token.GTR, token.GEQ:
+		if !comparableConsts(op, x, y) {
+			return nil, false
+		}
+		return constant.MakeBool(constant.Compare(x, op, y)), true
+	case token.LAND, token.LOR:
+		if x.Kind() != constant.Bool || y.Kind() != constant.Bool {
+			return nil, false
+		}
+	case token.ADD:
+		if x.Kind() == constant.String || y.Kind() == constant.String {
+			if x.Kind() != y.Kind() {
+				return nil, false
+			}
+			return constant.BinaryOp(x, op, y), true
+		}
+		if !isNumeric(x) || !isNumeric(y) {
+			return nil, false
+		}
+	case token.SUB, token.MUL:
+		if !isNumeric(x) || !isNumeric(y) {
+			return nil, false
+		}
+	case token.QUO:
+		if !isNumeric(x) || !isNumeric(y) || constant.Sign(y) == 0 {
+			return nil, false
+		}
+		if x.Kind() == constant.Int && y.Kind() == constant.Int {
+			op = token.QUO_ASSIGN
+		}
+	case token.REM, token.AND, token.OR, token.XOR, token.AND_NOT:
+		if x.Kind() != constant.Int || y.Kind() != constant.Int || (op == token.REM && constant.Sign(y) == 0) {
+			return nil, false
+		}
+	case token.SHL, token.SHR:
+		s, ok := constant.Uint64Val(y)
+		if x.Kind() != constant.Int || !ok {
+			return nil, false
+		}
+		return constant.Shift(x, op, uint(s)), true
+	default:
+		return nil, false
+	}
+	return constant.BinaryOp(x, op, y), true
+}
+
+func isNumeric(v constant.Value) bool {
+	return v.Kind() == constant.Int || v.Kind() == constant.Float
+}
+
+func comparableConsts(op token.Token, x, y constant.Value) bool {
+	if isNumeric(x) && isNumeric(y) {
+		return true
+	}
+	if x.Kind() != y.Kind() {
+		return false
+	}
+	return x.Kind() == constant.String || (x.Kind() == constant.Bool && (op == token.EQL || op == token.NEQ))
+}
+
+// evalCall evaluates a conversion, e.g. string(keyName) or
+// attributeKey("name"), or a formatting call of the standard library.
+func (res resolver) evalCall(call *ast.CallExpr, seen map[ast.Expr]bool) (constant.Value, bool) {
+	if ident, ok := call.Fun.(*ast.Ident); ok {
+		if len(call.Args) == 1 && (basicTypes[ident.Name] || res.typeNames[ident.Name]) {
+			return res.evalConst(call.Args[0], seen)
+		}
+		return nil, false
+	}
+	sel, ok := call.Fun.(*ast.SelectorExpr)
+	if !ok {
+		return nil, false
+	}
+	pkg, ok := sel.X.(*ast.Ident)
+	if !ok {
+		return nil, false
+	}
+	importPath, _ := res.importPath(pkg)
+	switch importPath + "." + sel.Sel.Name {
+	case "fmt.Sprintf":
+		args, ok := res.evalArgs(call.Args, seen)
+		if !ok || len(args) == 0 {
+			return nil, false
+		}
+		format, ok := args[0].(string)
+		if !ok {
+			return nil, false
+		}
+		return constant.MakeString(fmt.Sprintf(format, args[1:]...)), true
+	case "fmt.Sprint":
+		args, ok := res.evalArgs(call.Args, seen)
+		if !ok {
+			return nil, false
+		}
+		return constant.MakeString(fmt.Sprint(args...)), true
+	case "strings.Join":
+		if len(call.Args) != 2 {
+			return nil, false
+		}
+		lit, ok := call.Args[0].(*ast.CompositeLit)
+		if !ok {
+			return nil, false
+		}
+		elems, ok := res.evalArgs(lit.Elts, seen)
+		if !ok {
+			return nil, false
+		}
+		sep, ok := res.evalConst(call.Args[1], seen)
+		if !ok || sep.Kind() != constant.String {
+			return nil, false
+		}
+		values := make([]string, len(elems))
+		for i, elem := range elems {
+			s, ok := elem.(string)
+			if !ok {
+				return nil, false
+			}
+			values[i] = s
+		}
+		return constant.MakeString(strings.Join(values, constant.StringVal(sep))), true
+	}
+	return nil, false
+}
+
+// evalArgs evaluates the arguments of a formatting call to Go values.
+func (res resolver) evalArgs(exprs []ast.Expr, seen map[ast.Expr]bool) ([]interface{}, bool) {
+	args := make([]interface{}, len(exprs))
+	for i, expr := range exprs {
+		value, ok := res.evalConst(expr, seen)
+		if !ok {
+			return nil, false
+		}
+		switch value.Kind() {
+		case constant.String:
+			args[i] = constant.StringVal(value)
+		case constant.Bool:
+			args[i] = constant.BoolVal(value)
+		case constant.Int:
+			n, exact := constant.Int64Val(value)
+			if !exact {
+				return nil, false
+			}
+			args[i] = int(n)
+		case constant.Float:
+			f, _ := constant.Float64Val(value)
+			args[i] = f
+		default:
+			return nil, false
+		}
+	}
+	return args, true
+}
+
+func (res resolver) evalString(expr ast.Expr) (string, bool) {
+	value, ok := res.constValue(expr)
+	if !ok || value.Kind() != constant.String {
+		return "", false
+	}
+	return constant.StringVal(value), true
+}
+
+// evalStrings evaluates a []string literal, or a package level variable
+// initialized with one, to its elements.
+func (res resolver) evalStrings(expr ast.Expr) ([]string, bool) {
+	lit, ok := ast.Unparen(expr).(*ast.CompositeLit)
+	if ident, named := ast.Unparen(expr).(*ast.Ident); named {
+		lit, ok = res.varSlices[ident.Name]
+	}
+	if !ok {
+		return nil, false
+	}
+	arrayType, ok := lit.Type.(*ast.ArrayType)
+	if !ok || !isIdent(arrayType.Elt, "string") {
+		return nil, false
+	}
+	values := make([]string, 0, len(lit.Elts))
+	for _, elt := range lit.Elts {
+		value, ok := res.evalString(elt)
+		if !ok {
+			return nil, false
+		}
+		values = append(values, value)
+	}
+	return values, true
+}
+
+func (res resolver) evalBool(expr ast.Expr) (bool, bool) {
+	value, ok := res.constValue(expr)
+	if !ok || value.Kind() != constant.Bool {
+		return false, false
+	}
+	return constant.BoolVal(value), true
+}
+
+func (res resolver) evalInt(expr ast.Expr) (int, bool) {
+	value, ok := res.constValue(expr)
+	if !ok {
+		return 0, false
+	}
+	value = constant.ToInt(value)
+	if value.Kind() != constant.Int {
+		return 0, false
+	}
+	n, exact := constant.Int64Val(value)
+	return int(n), exact
+}
+
+// defaultLiteral returns the Go source of a constant Default value, or an
+// empty string for anything else, e.g. a constant from another module.
+// Literals keep their SDK spelling.
+func (res resolver) defaultLiteral(expr ast.Expr) string {
+	switch v := expr.(type) {
+	case *ast.BasicLit:
+		return v.Value
+	case *ast.UnaryExpr:
+		if lit, ok := v.X.(*ast.BasicLit); ok && v.Op == token.SUB {
+			return "-" + lit.Value
+		}
+	}
+	value, ok := res.constValue(expr)
+	if !ok {
+		return ""
+	}
+	switch value.Kind() {
+	case constant.String:
+		return strconv.Quote(constant.StringVal(value))
+	case constant.Bool, constant.Int:
+		return value.ExactString()
+	case constant.Float:
+		f, _ := constant.Float64Val(value)
+		return strconv.FormatFloat(f, 'g', -1, 64)
+	}
+	return ""
+}
+
+// applyUnevaluatedDescriptions records the descriptions of info that are not
+// constant expressions in the mappings.
+func applyUnevaluatedDescriptions(info *ResourceInfo) {
+	walkUnevaluatedDescriptions(info.Attributes, info.Blocks, func(path, expr string, pos token.Position) {
+		info.Mappings = append(info.Mappings, SchemaMapping{
+			Path:     path,
+			Field:    "Description",
+			Position: pos,
+			Result:   "not evaluated: " + expr + " is not a constant expression, the framework schema has no description",
+		})
+	})
+}
+
+// unevaluatedDescriptionNotes warns about the descriptions of a schema that
+// are not constant expressions, e.g. for scope "resource example_widget".
+func unevaluatedDescriptionNotes(scope string, attrs []Attribute, blocks []Block) []string {
+	var notes []string
+	walkUnevaluatedDescriptions(attrs, blocks, func(path, expr string, _ token.Position) {
+		notes = append(notes, fmt.Sprintf("%s: Description of %s is not a constant expression (%s) and is left out of the framework schema", scope, path, expr))
+	})
+	return notes
+}
+
+func walkUnevaluatedDescriptions(attrs []Attribute, blocks []Block, visit func(path, expr string, pos token.Position)) {
+	var walk func(prefix string, attrs []Attribute, blocks []Block)
+	walk = func(prefix string, attrs []Attribute, blocks []Block) {
+		for _, attr := range attrs {
+			if attr.DescriptionExpr != "" {
+				visit(prefix+attr.Name, attr.DescriptionExpr, attr.Fields["Description"])
+			}
+		}
+		for _, block := range blocks {
+			if block.DescriptionExpr != "" {
+				visit(prefix+block.Name, block.DescriptionExpr, block.Fields["Description"])
+			}
+			walk(prefix+block.Name+".", block.Attributes, block.Blocks)
+		}
+	}
+	walk("", attrs, blocks)
+}
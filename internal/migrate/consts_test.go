@@ -0,0 +1,116 @@
+package migrate
+
+import (
+	"go/ast"
+	"go/token"
+	"testing"
+)
+
+func TestConstValue(t *testing.T) {
+	t.Parallel()
+
+	res := testResolver(t, map[string]string{
+		"provider/consts.go": `package provider
+
+import (
+	"fmt"
+	"strings"
+
+	"example.com/terraform-provider-example/internal/names"
+)
+
+type kind string
+
+const (
+	prefix      = "example"
+	typed  kind = "typed"
+	size        = 2 << 3
+	first       = second
+	second      = first
+)
+
+var variable = "variable"
+
+var (
+	literal    = "x"
+	concat     = prefix + "_thing"
+	conversion = string(typed)
+	arithmetic = size * 2
+	negation   = -size
+	not        = !true
+	comparison = size > 10
+	sprintf    = fmt.Sprintf("%s_%d", prefix, 3)
+	join       = strings.Join([]string{prefix, "x"}, "-")
+	pkgConst   = names.Thing + "s"
+	cycle      = first
+	fromVar    = variable
+	call       = strings.ToUpper(prefix)
+	mismatched = prefix + size
+	divideZero = size / 0
+	unknownPkg = other.Name
+)
+`,
+		"internal/names/names.go": `package names
+
+const Thing = "thing"
+`,
+	})
+
+	values := map[string]ast.Expr{}
+	for _, file := range res.files {
+		for _, decl := range file.Decls {
+			gen, ok := decl.(*ast.GenDecl)
+			if !ok || gen.Tok != token.VAR {
+				continue
+			}
+			for _, spec := range gen.Specs {
+				valueSpec := spec.(*ast.ValueSpec)
+				for i, name := range valueSpec.Names {
+					values[name.Name] = valueSpec.Values[i]
+				}
+			}
+		}
+	}
+
+	tests := []struct {
+		name string
+		want string
+	}{
+		{name: "literal", want: `"x"`},
+		{name: "concat", want: `"example_thing"`},
+		{name: "conversion", want: `"typed"`},
+		{name: "arithmetic", want: "32"},
+		{name: "negation", want: "-16"},
+		{name: "not", want: "false"},
+		{name: "comparison", want: "true"},
+		{name: "sprintf", want: `"example_3"`},
+		{name: "join", want: `"example-x"`},
+		{name: "pkgConst", want: `"things"`},
+		{name: "cycle"},
+		{name: "fromVar"},
+		{name: "call"},
+		{name: "mismatched"},
+		{name: "divideZero"},
+		{name: "unknownPkg"},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			expr, ok := values[tt.name]
+			if !ok {
+				t.Fatalf("no variable %s in source", tt.name)
+			}
+			value, ok := res.constValue(expr)
+			switch {
+			case tt.want == "" && ok:
+				t.Fatalf("expected no constant value, got %s", value.ExactString())
+			case tt.want != "" && !ok:
+				t.Fatalf("expected %s, got no constant value", tt.want)
+			case ok && value.ExactString() != tt.want:
+				t.Fatalf("expected %s, got %s", tt.want, value.ExactString())
+			}
+		})
+	}
+}
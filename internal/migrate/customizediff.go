@@ -0,0 +1,297 @@
+package migrate
+
+import (
+	"fmt"
+	"go/ast"
+	"go/token"
+	"strconv"
+	"strings"
+)
+
+const customdiffImport = "github.com/hashicorp/terraform-plugin-sdk/v2/helper/customdiff"
+
+// DiffRule is one function of an SDK CustomizeDiff, with customdiff.All and
+// customdiff.Sequence flattened.
+type DiffRule struct {
+	// Combinator is the customdiff function, e.g. "ForceNewIfChange", or
+	// empty for a custom function.
+	Combinator string
+	// Attribute is the top-level attribute a combinator applies to, when
+	// the resource has it.
+	Attribute string
+	// RequiresReplaceIf names the generated condition function when the
+	// rule became a RequiresReplaceIf plan modifier of Attribute.
+	RequiresReplaceIf string
+
+	// rule is the rule as written; cond is the condition of a combinator.
+	rule crudFunc
+	cond crudFunc
+}
+
+// parseCustomizeDiff reads the CustomizeDiff field of a schema.Resource
+// literal. customdiff.ForceNewIf and customdiff.ForceNewIfChange on a
+// top-level attribute become RequiresReplaceIf plan modifiers; every other
+// rule is ported to ModifyPlan.
+func parseCustomizeDiff(lit *ast.CompositeLit, info *ResourceInfo, res resolver) {
+	value, ok := compositeValue(lit, "CustomizeDiff")
+	if !ok {
+		return
+	}
+	file := res.files[res.fset.File(value.Pos()).Name()]
+	imports := fileImports(file)
+
+	attrs := map[string]int{}
+	for i, attr := range info.Attributes {
+		attrs[attr.Name] = i
+	}
+
+	var flatten func(expr ast.Expr)
+	flatten = func(expr ast.Expr) {
+		combinator := ""
+		call, ok := expr.(*ast.CallExpr)
+		if ok {
+			if sel, ok := call.Fun.(*ast.SelectorExpr); ok {
+				if pkg, ok := sel.X.(*ast.Ident); ok && imports[pkg.Name] == customdiffImport {
+					combinator = sel.Sel.Name
+				}
+			}
+		}
+		if combinator == "All" || combinator == "Sequence" {
+			for _, arg := range call.Args {
+				flatten(arg)
+			}
+			return
+		}
+
+		rule := DiffRule{Combinator: combinator}
+		if combinator == "" {
+			rule.rule = resolveFunc("CustomizeDiff", expr, res)
+			info.CustomizeDiff = append(info.CustomizeDiff, rule)
+			info.Mappings = append(info.Mappings, SchemaMapping{
+				Path:     "CustomizeDiff",
+				Field:    rule.rule.describe(),
+				Position: res.fset.Position(expr.Pos()),
+				Result:   "ModifyPlan stub",
+			})
+			return
+		}
+
+		rule.rule = crudFunc{field: "CustomizeDiff", name: "customdiff." + combinator, file: file, node: call}
+		result := "ModifyPlan stub"
+		path := "CustomizeDiff"
+		if key, ok := stringArg(call, 0); ok && len(call.Args) == 2 {
+			rule.cond = resolveFunc(fmt.Sprintf("customdiff.%s(%q)", combinator, key), call.Args[1], res)
+			if i, ok := attrs[key]; ok && info.Attributes[i].Literal == "" {
+				switch combinator {
+				case "ForceNewIf", "ForceNewIfChange":
+					attr := &info.Attributes[i]
+					rule.Attribute = key
+					rule.RequiresReplaceIf = lowerGoName(info.Name+"_"+key) + "RequiresReplaceIf"
+					description := fmt.Sprintf("Ported from the SDK customdiff.%s.", combinator)
+					modifier := fmt.Sprintf("%s.RequiresReplaceIf(%s, %q, %q)", planModifierPackage(attr.Type), rule.RequiresReplaceIf, description, description)
+					attr.PlanModifiers = append(attr.PlanModifiers, modifier)
+					path, result = key, fmt.Sprintf("%s.RequiresReplaceIf(%s)", planModifierPackage(attr.Type), rule.RequiresReplaceIf)
+				case "ComputedIf":
+					rule.Attribute = key
+					path, result = key, "ModifyPlan: unknown when the condition holds"
+				}
+			}
+		}
+		info.CustomizeDiff = append(info.CustomizeDiff, rule)
+		info.Mappings = append(info.Mappings, SchemaMapping{
+			Path:     path,
+			Field:    "CustomizeDiff customdiff." + combinator,
+			Position: res.fset.Position(call.Pos()),
+			Result:   result,
+		})
+	}
+	flatten(value)
+}
+
+// describe names fn for notes and TODOs: its name, or its field for function
+// literals.
+func (fn crudFunc) describe() string {
+	if fn.name != "" {
+		return fn.name
+	}
+	return fn.field
+}
+
+func stringArg(call *ast.CallExpr, i int) (string, bool) {
+	if len(call.Args) <= i {
+		return "", false
+	}
+	lit, ok := call.Args[i].(*ast.BasicLit)
+	if !ok {
+		return "", false
+	}
+	value, err := strconv.Unquote(lit.Value)
+	return value, err == nil
+}
+
+// translateCustomizeDiff returns the ModifyPlan body and the condition
+// functions of RequiresReplaceIf plan modifiers for the CustomizeDiff rules
+// of a resource. A ForceNewIfChange condition that only compares the old and
+// new value is translated; other conditions are quoted next to a TODO and
+// fail the plan with an error diagnostic, or default to false for
+// ComputedIf. The condition functions are always generated, since the schema
+// refers to them.
+func translateCustomizeDiff(info ResourceInfo, res resolver) (modifyPlan, planFuncs crudTranslation) {
+	attrs := map[string]Attribute{}
+	for _, attr := range info.Attributes {
+		attrs[attr.Name] = attr
+	}
+
+	var plan, funcs []string
+	todos := 0
+	names := map[string]int{}
+	for _, rule := range info.CustomizeDiff {
+		switch {
+		case rule.RequiresReplaceIf != "":
+			attr := attrs[rule.Attribute]
+			kind := strings.TrimSuffix(frameworkAttributeType(attr.Type), "Attribute")
+			body := ""
+			if cond, ok := changeCondition(rule, attr); ok {
+				body = fmt.Sprintf("// Ported from the SDK condition of customdiff.%s(%q). Null and unknown\n// values read as the zero value, like old and new in the SDK.\nresp.RequiresReplace = %s", rule.Combinator, rule.Attribute, cond)
+			} else {
+				stub := diffRuleStub("condition", rule.cond, res)
+				body = fmt.Sprintf("%s\n// Until the condition is ported, a change of %s fails the plan instead of\n// replacing the resource.\nresp.Diagnostics.AddError(%q, %q)",
+					stub, rule.Attribute, "Condition not ported",
+					fmt.Sprintf("The SDK condition of customdiff.%s(%q), which decides whether a change of %s replaces the resource, has not been ported to %s.", rule.Combinator, rule.Attribute, rule.Attribute, rule.RequiresReplaceIf))
+				todos++
+			}
+			funcs = append(funcs, fmt.Sprintf("func %s(ctx context.Context, req planmodifier.%sRequest, resp *%s.RequiresReplaceIfFuncResponse) {\n%s\n}",
+				rule.RequiresReplaceIf, kind, planModifierPackage(attr.Type), body))
+
+		case rule.Combinator == "ComputedIf" && rule.Attribute != "":
+			stub := diffRuleStub("condition", rule.cond, res)
+			name := lowerGoName(rule.Attribute) + "Computed"
+			if names[name]++; names[name] > 1 {
+				name += strconv.Itoa(names[name])
+			}
+			attr := attrs[rule.Attribute]
+			plan = append(plan, fmt.Sprintf("// customdiff.ComputedIf: %s is unknown in the plan when the condition holds.\n%s := func() bool {\n%s\nreturn false\n}\nif %s() {\nresp.Diagnostics.Append(resp.Plan.SetAttribute(ctx, path.Root(%q), %s)...)\n}",
+				rule.Attribute, name, stub, name, rule.Attribute, unknownValue(attr)))
+			modifyPlan.Imports = []string{strconv.Quote(frameworkModule + "/path")}
+			modifyPlan.UsesTypes = true
+
+		default:
+			stub := diffRuleStub("CustomizeDiff rule", rule.rule, res)
+			plan = append(plan, stub)
+		}
+	}
+
+	if len(plan) > 0 {
+		modifyPlan.Body = "if req.Plan.Raw.IsNull() {\n// CustomizeDiff does not run when the resource is destroyed.\nreturn\n}\n\n" + strings.Join(plan, "\n\n")
+		modifyPlan.TODOs = len(plan)
+	}
+	planFuncs.Body = strings.Join(funcs, "\n\n")
+	planFuncs.TODOs = todos
+	return modifyPlan, planFuncs
+}
+
+// changeCondition translates the condition of a customdiff.ForceNewIfChange
+// whose body is a single return of comparisons between old.(T), new.(T) and
+// literals, with old and new read from the state and plan value.
+func changeCondition(rule DiffRule, attr Attribute) (string, bool) {
+	if rule.Combinator != "ForceNewIfChange" || attr.CustomType != "" {
+		return "", false
+	}
+	var typ *ast.FuncType
+	var body *ast.BlockStmt
+	switch fn := rule.cond.node.(type) {
+	case *ast.FuncLit:
+		typ, body = fn.Type, fn.Body
+	case *ast.FuncDecl:
+		typ, body = fn.Type, fn.Body
+	default:
+		return "", false
+	}
+	var params []string
+	for _, field := range typ.Params.List {
+		for _, name := range field.Names {
+			params = append(params, name.Name)
+		}
+	}
+	if len(params) != 4 || body == nil || len(body.List) != 1 {
+		return "", false
+	}
+	ret, ok := body.List[0].(*ast.ReturnStmt)
+	if !ok || len(ret.Results) != 1 {
+		return "", false
+	}
+
+	values := map[string]string{params[1]: "req.StateValue", params[2]: "req.PlanValue"}
+	var translate func(expr ast.Expr) (string, bool)
+	translate = func(expr ast.Expr) (string, bool) {
+		switch e := expr.(type) {
+		case *ast.BasicLit:
+			return e.Value, true
+		case *ast.Ident:
+			return e.Name, e.Name == "true" || e.Name == "false"
+		case *ast.ParenExpr:
+			inner, ok := translate(e.X)
+			return "(" + inner + ")", ok
+		case *ast.UnaryExpr:
+			inner, ok := translate(e.X)
+			return e.Op.String() + inner, ok && (e.Op == token.NOT || e.Op == token.SUB)
+		case *ast.BinaryExpr:
+			switch e.Op {
+			case token.EQL, token.NEQ, token.LSS, token.LEQ, token.GTR, token.GEQ, token.LAND, token.LOR,
+				token.ADD, token.SUB, token.MUL, token.QUO:
+			default:
+				return "", false
+			}
+			x, okX := translate(e.X)
+			y, okY := translate(e.Y)
+			return x + " " + e.Op.String() + " " + y, okX && okY
+		case *ast.TypeAssertExpr:
+			ident, ok := e.X.(*ast.Ident)
+			if !ok || values[ident.Name] == "" {
+				return "", false
+			}
+			field := values[ident.Name]
+			switch typeName := exprText(e.Type); {
+			case attr.Type == "string" && typeName == "string":
+				return field + ".ValueString()", true
+			case attr.Type == "bool" && typeName == "bool":
+				return field + ".ValueBool()", true
+			case attr.Type == "int" && typeName == "int":
+				return "int(" + field + ".ValueInt64())", true
+			case attr.Type == "float" && typeName == "float64":
+				return field + ".ValueFloat64()", true
+			}
+		}
+		return "", false
+	}
+	return translate(ret.Results[0])
+}
+
+// diffRuleStub is referenceStub for a CustomizeDiff rule or condition,
+// falling back to a TODO naming the function when its source cannot be read.
+func diffRuleStub(kind string, fn crudFunc, res resolver) string {
+	stub, err := referenceStub(kind, fn, res)
+	if err != nil {
+		return fmt.Sprintf("// TODO(migrate): port the SDK %s %s (%v).", kind, fn.describe(), err)
+	}
+	return stub
+}
+
+func unknownValue(attr Attribute) string {
+	switch attr.Type {
+	case "bool":
+		return "types.BoolUnknown()"
+	case "int":
+		return "types.Int64Unknown()"
+	case "float":
+		return "types.Float64Unknown()"
+	case "list":
+		return fmt.Sprintf("types.ListUnknown(%s)", renderElementType(attr))
+	case "set":
+		return fmt.Sprintf("types.SetUnknown(%s)", renderElementType(attr))
+	case "map":
+		return fmt.Sprintf("types.MapUnknown(%s)", renderElementType(attr))
+	default:
+		return "types.StringUnknown()"
+	}
+}
@@ -0,0 +1,105 @@
+package migrate
+
+import (
+	"go/ast"
+	"strings"
+	"testing"
+)
+
+func TestTranslateRequiresReplaceIf(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name      string
+		rule      string
+		want      string
+		wantTODOs int
+	}{
+		{
+			name: "comparison of old and new",
+			rule: `customdiff.ForceNewIfChange("size", func(ctx context.Context, old, new, meta interface{}) bool {
+				return new.(int) < old.(int)
+			})`,
+			want: "resp.RequiresReplace = int(req.PlanValue.ValueInt64()) < int(req.StateValue.ValueInt64())",
+		},
+		{
+			name: "named function with literals",
+			rule: `customdiff.ForceNewIfChange("name", nameChanged)`,
+			want: `resp.RequiresReplace = req.StateValue.ValueString() != "" && !(req.PlanValue.ValueString() == "default")`,
+		},
+		{
+			name: "condition calling a function",
+			rule: `customdiff.ForceNewIfChange("name", func(ctx context.Context, old, new, meta interface{}) bool {
+				return strings.HasPrefix(new.(string), old.(string))
+			})`,
+			want:      `resp.Diagnostics.AddError("Condition not ported", "The SDK condition of customdiff.ForceNewIfChange(\"name\"), which decides whether a change of name replaces the resource, has not been ported to exampleThingNameRequiresReplaceIf.")`,
+			wantTODOs: 1,
+		},
+		{
+			name: "condition on the resource diff",
+			rule: `customdiff.ForceNewIf("size", func(ctx context.Context, d *schema.ResourceDiff, meta interface{}) bool {
+				return d.Get("size").(int) > 10
+			})`,
+			want:      "// TODO(migrate): port the SDK condition customdiff.ForceNewIf(\"size\"):",
+			wantTODOs: 1,
+		},
+		{
+			name: "mismatched type",
+			rule: `customdiff.ForceNewIfChange("size", func(ctx context.Context, old, new, meta interface{}) bool {
+				return new.(string) != old.(string)
+			})`,
+			want:      `resp.Diagnostics.AddError("Condition not ported"`,
+			wantTODOs: 1,
+		},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			res := testResolver(t, map[string]string{"resource.go": `package provider
+
+import (
+	"context"
+	"strings"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/customdiff"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+)
+
+func nameChanged(ctx context.Context, before, after, meta interface{}) bool {
+	return before.(string) != "" && !(after.(string) == "default")
+}
+
+func resourceThing() *schema.Resource {
+	return &schema.Resource{
+		CustomizeDiff: ` + tt.rule + `,
+	}
+}
+`})
+			var lit *ast.CompositeLit
+			ast.Inspect(res.funcs["resourceThing"], func(n ast.Node) bool {
+				if v, ok := n.(*ast.CompositeLit); ok && lit == nil {
+					lit = v
+				}
+				return lit == nil
+			})
+
+			info := ResourceInfo{Name: "example_thing", Attributes: []Attribute{
+				{Name: "name", Type: "string", Required: true},
+				{Name: "size", Type: "int", Optional: true},
+			}}
+			parseCustomizeDiff(lit, &info, res)
+			_, funcs := translateCustomizeDiff(info, res)
+			if !strings.Contains(funcs.Body, tt.want) {
+				t.Errorf("expected %q in the condition function:\n%s", tt.want, funcs.Body)
+			}
+			if strings.Contains(funcs.Body, "resp.RequiresReplace = true") {
+				t.Errorf("expected no unconditional replacement:\n%s", funcs.Body)
+			}
+			if funcs.TODOs != tt.wantTODOs {
+				t.Errorf("expected %d TODOs, got %d", tt.wantTODOs, funcs.TODOs)
+			}
+		})
+	}
+}
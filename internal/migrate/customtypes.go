@@ -0,0 +1,278 @@
+package migrate
+
+import (
+	"fmt"
+	"go/ast"
+	"go/types"
+	"strconv"
+	"strings"
+)
+
+const (
+	sdkStructurePackage  = pluginSDKModule + "/helper/structure"
+	sdkValidationPackage = pluginSDKModule + "/helper/validation"
+)
+
+// customType is a framework custom type an SDK string attribute maps to.
+type customType struct {
+	// Type is the schema CustomType, e.g. jsontypes.NormalizedType{}.
+	Type string
+	// NewValue formats the value of a Go string for d.Set, or is empty when
+	// the constructor returns diagnostics.
+	NewValue string
+	Module   string
+	// Note is added to the report mapping.
+	Note string
+}
+
+var (
+	jsonNormalizedType = customType{
+		Type:     "jsontypes.NormalizedType{}",
+		NewValue: "jsontypes.NewNormalizedValue(%s)",
+		Module:   jsonTypesModule,
+	}
+	rfc3339Type = customType{
+		Type:   "timetypes.RFC3339Type{}",
+		Module: timeTypesModule,
+	}
+	ipv4AddressType = customType{
+		Type:     "iptypes.IPv4AddressType{}",
+		NewValue: "iptypes.NewIPv4AddressValue(%s)",
+		Module:   netTypesModule,
+	}
+	ipv6AddressType = customType{
+		Type:     "iptypes.IPv6AddressType{}",
+		NewValue: "iptypes.NewIPv6AddressValue(%s)",
+		Module:   netTypesModule,
+	}
+)
+
+// customTypePatterns maps the SDK functions recognised on string attributes
+// to the custom type that keeps their behaviour.
+var customTypePatterns = map[string]customType{
+	sdkStructurePackage + ".SuppressJsonDiff": jsonNormalizedType,
+	sdkValidationPackage + ".StringIsJSON":    jsonNormalizedType,
+	sdkValidationPackage + ".IsRFC3339Time":   rfc3339Type,
+	sdkValidationPackage + ".IsIPv4Address":   ipv4AddressType,
+	sdkValidationPackage + ".IsIPv6Address":   ipv6AddressType,
+}
+
+// cidrValidatorFunc accepts IPv4 and IPv6 prefixes, while each cidrtypes
+// custom type only accepts one of them. Its attributes stay strings and get
+// a generated validator instead.
+const cidrValidatorFunc = sdkValidationPackage + ".IsCIDR"
+
+// applyCustomTypes sets the CustomType of string attributes whose
+// ValidateFunc or DiffSuppressFunc is a well-known SDK function, and the
+// validator of validation.IsCIDR attributes. A DiffSuppressFunc without a
+// custom type is reported for resources, since the framework has no
+// equivalent.
+func applyCustomTypes(info *ResourceInfo) {
+	info.Attributes, info.Blocks = customTypeSchema("", info.Attributes, info.Blocks, *info, &info.Mappings)
+}
+
+func customTypeSchema(prefix string, attrs []Attribute, blocks []Block, info ResourceInfo, mappings *[]SchemaMapping) ([]Attribute, []Block) {
+	typed := make([]Attribute, len(attrs))
+	for i, attr := range attrs {
+		typed[i] = customTypeAttribute(prefix, attr, info, mappings)
+	}
+
+	typedBlocks := make([]Block, len(blocks))
+	for i, block := range blocks {
+		block.Attributes, block.Blocks = customTypeSchema(prefix+block.Name+".", block.Attributes, block.Blocks, info, mappings)
+		typedBlocks[i] = block
+	}
+	return typed, typedBlocks
+}
+
+func customTypeAttribute(prefix string, attr Attribute, info ResourceInfo, mappings *[]SchemaMapping) Attribute {
+	if attr.Literal != "" {
+		return attr
+	}
+
+	record := func(field, fn, result string) {
+		*mappings = append(*mappings, SchemaMapping{
+			Path:     prefix + attr.Name,
+			Field:    field + " " + shortFuncName(fn),
+			Position: attr.Fields[field],
+			Result:   result,
+		})
+	}
+
+	suppressed := false
+	for _, field := range []string{"DiffSuppressFunc", "ValidateFunc"} {
+		fn := attr.DiffSuppressFunc
+		if field == "ValidateFunc" {
+			fn = attr.ValidateFunc
+			if _, ok := attr.Fields[field]; !ok {
+				field = "ValidateDiagFunc"
+			}
+		}
+		if fn == cidrValidatorFunc && attr.Type == "string" {
+			validator := cidrValidatorName(info) + "{}"
+			attr.Validators = append(attr.Validators, validator)
+			record(field, fn, validator+" (generated: validation.IsCIDR accepts IPv4 and IPv6 prefixes, a cidrtypes custom type only one family)")
+			continue
+		}
+		typ, ok := customTypePatterns[fn]
+		if !ok || attr.Type != "string" {
+			continue
+		}
+		if attr.CustomType == "" {
+			attr.CustomType = typ.Type
+		}
+		suppressed = suppressed || field == "DiffSuppressFunc"
+		result := typ.Type
+		if typ.Note != "" {
+			result += " (" + typ.Note + ")"
+		}
+		record(field, fn, result)
+	}
+
+	if attr.DiffSuppressFunc != "" && !suppressed && !info.DataSource {
+		record("DiffSuppressFunc", attr.DiffSuppressFunc, "not preserved: the framework has no DiffSuppressFunc; use a custom type with semantic equality or a plan modifier")
+	}
+	return attr
+}
+
+// customTypeModules returns the modules of the custom types used by a
+// schema.
+func customTypeModules(attrs []Attribute, blocks []Block) []string {
+	var modules []string
+	seen := map[string]bool{}
+	var collect func([]Attribute, []Block)
+	collect = func(attrs []Attribute, blocks []Block) {
+		for _, attr := range attrs {
+			if typ, ok := lookupCustomType(attr.CustomType); ok && !seen[typ.Module] {
+				seen[typ.Module] = true
+				modules = append(modules, typ.Module)
+			}
+		}
+		for _, block := range blocks {
+			collect(block.Attributes, block.Blocks)
+		}
+	}
+	collect(attrs, blocks)
+	return modules
+}
+
+// cidrValidatorName names the validator type generated for the
+// validation.IsCIDR attributes of a resource or data source.
+func cidrValidatorName(info ResourceInfo) string {
+	kind := "Resource"
+	if info.DataSource {
+		kind = "DataSource"
+	}
+	return lowerGoName(info.Name) + kind + "CIDRValidator"
+}
+
+// cidrValidator returns the validator type of the validation.IsCIDR
+// attributes of a resource or data source, or an empty translation when it
+// has none.
+func cidrValidator(info ResourceInfo) crudTranslation {
+	name := cidrValidatorName(info)
+	if !usesValidator(info.Attributes, info.Blocks, name+"{}") {
+		return crudTranslation{}
+	}
+	return crudTranslation{
+		Body: fmt.Sprintf(`// %[1]s checks that a value is an IPv4 or IPv6
+// CIDR prefix, like the SDK validation.IsCIDR.
+type %[1]s struct{}
+
+func (v %[1]s) Description(ctx context.Context) string {
+	return "value must be a CIDR prefix"
+}
+
+func (v %[1]s) MarkdownDescription(ctx context.Context) string {
+	return v.Description(ctx)
+}
+
+func (v %[1]s) ValidateString(ctx context.Context, req validator.StringRequest, resp *validator.StringResponse) {
+	if req.ConfigValue.IsNull() || req.ConfigValue.IsUnknown() {
+		return
+	}
+	if _, _, err := net.ParseCIDR(req.ConfigValue.ValueString()); err != nil {
+		resp.Diagnostics.AddAttributeError(req.Path, "Invalid CIDR prefix", fmt.Sprintf("%%q is not a valid CIDR prefix: %%s", req.ConfigValue.ValueString(), err))
+	}
+}`, name),
+		Imports: []string{`"fmt"`, `"net"`, strconv.Quote(frameworkModule + "/schema/validator")},
+	}
+}
+
+// usesValidator reports whether an attribute of a schema has the validator
+// expression.
+func usesValidator(attrs []Attribute, blocks []Block, expr string) bool {
+	for _, attr := range attrs {
+		for _, validator := range attr.Validators {
+			if validator == expr {
+				return true
+			}
+		}
+	}
+	for _, block := range blocks {
+		if usesValidator(block.Attributes, block.Blocks, expr) {
+			return true
+		}
+	}
+	return false
+}
+
+// customTypeValue returns the model value type of a custom type, e.g.
+// jsontypes.Normalized for jsontypes.NormalizedType{}.
+func customTypeValue(typ string) string {
+	return strings.TrimSuffix(strings.TrimSuffix(typ, "{}"), "Type")
+}
+
+// lookupCustomType returns the custom type with the schema CustomType typ.
+func lookupCustomType(typ string) (customType, bool) {
+	for _, pattern := range customTypePatterns {
+		if pattern.Type == typ {
+			return pattern, true
+		}
+	}
+	return customType{}, false
+}
+
+// unwrapDiagFunc returns the function wrapped by validation.ToDiagFunc.
+func unwrapDiagFunc(expr ast.Expr) ast.Expr {
+	call, ok := expr.(*ast.CallExpr)
+	if !ok || len(call.Args) != 1 {
+		return expr
+	}
+	if sel, ok := call.Fun.(*ast.SelectorExpr); ok && sel.Sel.Name == "ToDiagFunc" {
+		return call.Args[0]
+	}
+	return expr
+}
+
+// qualifiedFunc returns a package function as import path and name, and
+// any other expression as written.
+func qualifiedFunc(expr ast.Expr, res resolver) string {
+	sel, ok := expr.(*ast.SelectorExpr)
+	if !ok {
+		return types.ExprString(expr)
+	}
+	pkg, ok := sel.X.(*ast.Ident)
+	if !ok {
+		return types.ExprString(expr)
+	}
+	file := res.files[res.fset.File(expr.Pos()).Name()]
+	if file == nil {
+		return types.ExprString(expr)
+	}
+	importPath, ok := fileImports(file)[pkg.Name]
+	if !ok {
+		return types.ExprString(expr)
+	}
+	return importPath + "." + sel.Sel.Name
+}
+
+// shortFuncName returns a qualifiedFunc result the way it is usually
+// written, e.g. validation.IsCIDR.
+func shortFuncName(fn string) string {
+	i := strings.LastIndex(fn, ".")
+	if i < 0 || !strings.Contains(fn[:i], "/") {
+		return fn
+	}
+	return importName(fn[:i]) + fn[i:]
+}
@@ -14,82 +14,161 @@ const (
 	frameworkModule = "github.com/hashicorp/terraform-plugin-framework"
 	muxModule       = "github.com/hashicorp/terraform-plugin-mux"
 	pluginGoModule  = "github.com/hashicorp/terraform-plugin-go"
+	timeoutsModule  = "github.com/hashicorp/terraform-plugin-framework-timeouts"
+	jsonTypesModule = "github.com/hashicorp/terraform-plugin-framework-jsontypes"
+	timeTypesModule = "github.com/hashicorp/terraform-plugin-framework-timetypes"
+	netTypesModule  = "github.com/hashicorp/terraform-plugin-framework-nettypes"
 
 	legacyFrameworkVersion = "v1.0.0"
 	legacyMuxVersion       = "v0.8.0"
 	legacyPluginGoVersion  = "v0.14.2"
+	legacyTimeoutsVersion  = "v0.3.0"
+	legacyJSONTypesVersion = "v0.1.0"
+	legacyTimeTypesVersion = "v0.3.0"
+	legacyNetTypesVersion  = "v0.1.0"
 
 	modernFrameworkVersion = "v1.17.0"
 	modernMuxVersion       = "v0.21.0"
 	modernPluginGoVersion  = "v0.29.0"
+	modernTimeoutsVersion  = "v0.5.0"
+	modernJSONTypesVersion = "v0.2.0"
+	modernTimeTypesVersion = "v0.5.0"
+	modernNetTypesVersion  = "v0.2.0"
 
 	pluginSDKModule = "github.com/hashicorp/terraform-plugin-sdk/v2"
 	sdkModernCutoff = "v2.34.0"
 )
 
-func ensureModuleDeps(moduleRoot string) error {
-	modPath := filepath.Join(moduleRoot, "go.mod")
-	data, err := os.ReadFile(modPath)
-	if err != nil {
-		return err
+// baseModules are required by the muxed main.go and the framework provider.
+// Migrated resources may need more, e.g. timeoutsModule or the custom type
+// modules.
+var baseModules = []string{frameworkModule, muxModule, pluginGoModule}
+
+// ModChange describes a requirement that migration adds to or raises in go.mod.
+type ModChange struct {
+	Path string
+	From string
+	To   string
+}
+
+func (c ModChange) String() string {
+	if c.From == "" {
+		return fmt.Sprintf("go.mod: require %s %s", c.Path, c.To)
 	}
+	return fmt.Sprintf("go.mod: upgrade %s %s => %s", c.Path, c.From, c.To)
+}
 
-	file, err := modfile.Parse(modPath, data, nil)
+func planModuleDeps(moduleRoot string, modules []string, pinned Dependencies, noUpgrade bool) ([]ModChange, error) {
+	file, err := readModFile(moduleRoot)
 	if err != nil {
-		return err
+		return nil, err
 	}
 
-	deps := selectDeps(file)
+	return planRequires(file, modules, pinned, noUpgrade)
+}
 
-	changed := false
-	changed = addRequire(file, frameworkModule, deps.frameworkVersion) || changed
-	changed = addRequire(file, muxModule, deps.muxVersion) || changed
-	changed = addRequire(file, pluginGoModule, deps.pluginGoVersion) || changed
+func ensureModuleDeps(moduleRoot string, modules []string, pinned Dependencies, noUpgrade bool) ([]ModChange, error) {
+	file, err := readModFile(moduleRoot)
+	if err != nil {
+		return nil, err
+	}
 
-	if !changed {
-		return nil
+	changes, err := planRequires(file, modules, pinned, noUpgrade)
+	if err != nil {
+		return nil, err
+	}
+	if len(changes) == 0 {
+		return nil, nil
+	}
+
+	for _, change := range changes {
+		if err := file.AddRequire(change.Path, change.To); err != nil {
+			return nil, fmt.Errorf("require %s: %w", change.Path, err)
+		}
 	}
 
 	formatted, err := file.Format()
 	if err != nil {
-		return fmt.Errorf("format go.mod: %w", err)
+		return nil, fmt.Errorf("format go.mod: %w", err)
+	}
+	if err := os.WriteFile(filepath.Join(moduleRoot, "go.mod"), formatted, 0o644); err != nil {
+		return nil, err
 	}
-	return os.WriteFile(modPath, formatted, 0o644)
+	return changes, nil
 }
 
-func addRequire(file *modfile.File, path, version string) bool {
-	for _, req := range file.Require {
-		if req.Mod.Path == path {
-			return false
-		}
-	}
-	if err := file.AddRequire(path, version); err != nil {
-		return false
+func readModFile(moduleRoot string) (*modfile.File, error) {
+	modPath := filepath.Join(moduleRoot, "go.mod")
+	data, err := os.ReadFile(modPath)
+	if err != nil {
+		return nil, err
 	}
-	return true
+
+	return modfile.Parse(modPath, data, nil)
 }
 
-type depVersions struct {
-	frameworkVersion string
-	muxVersion       string
-	pluginGoVersion  string
+// planRequires compares the current requirements of modules against the
+// minimum versions the generated code needs, or the pinned versions where
+// set. Missing modules are added and older ones are raised, unless noUpgrade
+// is set, in which case an existing requirement below the minimum is an
+// error.
+func planRequires(file *modfile.File, modules []string, pinned Dependencies, noUpgrade bool) ([]ModChange, error) {
+	versions := selectDeps(file, pinned)
+
+	var changes []ModChange
+	for _, path := range modules {
+		version := versions[path]
+		current := requireVersion(file, path)
+		if current == "" {
+			changes = append(changes, ModChange{Path: path, To: version})
+			continue
+		}
+		if semver.IsValid(current) && semver.Compare(current, version) >= 0 {
+			continue
+		}
+		if noUpgrade {
+			return nil, fmt.Errorf("%s %s is below the required %s (upgrade disabled by --no-upgrade)", path, current, version)
+		}
+		changes = append(changes, ModChange{Path: path, From: current, To: version})
+	}
+
+	return changes, nil
 }
 
-func selectDeps(file *modfile.File) depVersions {
+// selectDeps returns the version of each module the generated code may
+// require, based on the SDK version in go.mod and the pinned versions.
+func selectDeps(file *modfile.File, pinned Dependencies) map[string]string {
+	versions := map[string]string{
+		frameworkModule: legacyFrameworkVersion,
+		muxModule:       legacyMuxVersion,
+		pluginGoModule:  legacyPluginGoVersion,
+		timeoutsModule:  legacyTimeoutsVersion,
+		jsonTypesModule: legacyJSONTypesVersion,
+		timeTypesModule: legacyTimeTypesVersion,
+		netTypesModule:  legacyNetTypesVersion,
+	}
+
 	sdkVersion := requireVersion(file, pluginSDKModule)
 	if semver.IsValid(sdkVersion) && semver.Compare(sdkVersion, sdkModernCutoff) >= 0 {
-		return depVersions{
-			frameworkVersion: modernFrameworkVersion,
-			muxVersion:       modernMuxVersion,
-			pluginGoVersion:  modernPluginGoVersion,
+		versions = map[string]string{
+			frameworkModule: modernFrameworkVersion,
+			muxModule:       modernMuxVersion,
+			pluginGoModule:  modernPluginGoVersion,
+			timeoutsModule:  modernTimeoutsVersion,
+			jsonTypesModule: modernJSONTypesVersion,
+			timeTypesModule: modernTimeTypesVersion,
+			netTypesModule:  modernNetTypesVersion,
 		}
 	}
 
-	return depVersions{
-		frameworkVersion: legacyFrameworkVersion,
-		muxVersion:       legacyMuxVersion,
-		pluginGoVersion:  legacyPluginGoVersion,
-	}
+	versions[frameworkModule] = versionOrFallback(pinned.Framework, versions[frameworkModule])
+	versions[muxModule] = versionOrFallback(pinned.Mux, versions[muxModule])
+	versions[pluginGoModule] = versionOrFallback(pinned.PluginGo, versions[pluginGoModule])
+	versions[timeoutsModule] = versionOrFallback(pinned.Timeouts, versions[timeoutsModule])
+	versions[jsonTypesModule] = versionOrFallback(pinned.JSONTypes, versions[jsonTypesModule])
+	versions[timeTypesModule] = versionOrFallback(pinned.TimeTypes, versions[timeTypesModule])
+	versions[netTypesModule] = versionOrFallback(pinned.NetTypes, versions[netTypesModule])
+	return versions
 }
 
 func requireVersion(file *modfile.File, path string) string {
@@ -101,26 +180,15 @@ func requireVersion(file *modfile.File, path string) string {
 	return ""
 }
 
-func ensureGoSum(moduleRoot string) error {
-	modPath := filepath.Join(moduleRoot, "go.mod")
-	data, err := os.ReadFile(modPath)
+func ensureGoSum(moduleRoot string, modules []string, pinned Dependencies) error {
+	file, err := readModFile(moduleRoot)
 	if err != nil {
 		return err
 	}
 
-	file, err := modfile.Parse(modPath, data, nil)
-	if err != nil {
-		return err
-	}
-
-	deps := selectDeps(file)
-	versions := map[string]string{
-		frameworkModule: versionOrFallback(requireVersion(file, frameworkModule), deps.frameworkVersion),
-		muxModule:       versionOrFallback(requireVersion(file, muxModule), deps.muxVersion),
-		pluginGoModule:  versionOrFallback(requireVersion(file, pluginGoModule), deps.pluginGoVersion),
-	}
-
-	for mod, version := range versions {
+	versions := selectDeps(file, pinned)
+	for _, mod := range modules {
+		version := versionOrFallback(requireVersion(file, mod), versions[mod])
 		if version == "" {
 			continue
 		}
@@ -2,10 +2,19 @@ package migrate
 
 import (
 	"fmt"
+	"go/parser"
+	"go/token"
 	"os"
 	"path/filepath"
+	"sort"
+	"strings"
+
+	"golang.org/x/mod/modfile"
 )
 
+// findModuleRoot walks upward from start and returns the first directory that
+// holds a go.mod. A go.work met on the way takes precedence: the provider
+// module is then selected among the modules the workspace uses.
 func findModuleRoot(start string) (string, error) {
 	path, err := filepath.Abs(start)
 	if err != nil {
@@ -13,7 +22,10 @@ func findModuleRoot(start string) (string, error) {
 	}
 
 	for {
-		if _, err := os.Stat(filepath.Join(path, "go.mod")); err == nil {
+		if fileExists(filepath.Join(path, "go.work")) {
+			return findWorkspaceModule(path)
+		}
+		if fileExists(filepath.Join(path, "go.mod")) {
 			return path, nil
 		}
 
@@ -27,6 +39,106 @@ func findModuleRoot(start string) (string, error) {
 	return "", fmt.Errorf("go.mod not found from %s", start)
 }
 
+// findWorkspaceModule picks the provider module out of the modules listed in
+// the go.work file in workspaceRoot. Exactly one of them must contain a main
+// package serving the SDK provider.
+func findWorkspaceModule(workspaceRoot string) (string, error) {
+	workPath := filepath.Join(workspaceRoot, "go.work")
+	data, err := os.ReadFile(workPath)
+	if err != nil {
+		return "", err
+	}
+
+	work, err := modfile.ParseWork(workPath, data, nil)
+	if err != nil {
+		return "", err
+	}
+
+	var candidates []string
+	for _, use := range work.Use {
+		dir := use.Path
+		if !filepath.IsAbs(dir) {
+			dir = filepath.Join(workspaceRoot, filepath.FromSlash(dir))
+		}
+		if !fileExists(filepath.Join(dir, "go.mod")) {
+			continue
+		}
+		if _, info, err := findMainInfo(dir); err == nil && info.ProviderImport != "" {
+			candidates = append(candidates, dir)
+		}
+	}
+
+	switch len(candidates) {
+	case 0:
+		return "", fmt.Errorf("no provider module found in %s", workPath)
+	case 1:
+		return candidates[0], nil
+	default:
+		sort.Strings(candidates)
+		return "", fmt.Errorf("multiple provider modules in %s (%s), supply --path to one of them", workPath, strings.Join(candidates, ", "))
+	}
+}
+
+// findWorkspaceRoot returns the directory of the go.work file governing
+// moduleRoot, or an empty string outside of a workspace.
+func findWorkspaceRoot(moduleRoot string) string {
+	path := moduleRoot
+	for {
+		if fileExists(filepath.Join(path, "go.work")) {
+			return path
+		}
+
+		parent := filepath.Dir(path)
+		if parent == path {
+			return ""
+		}
+		path = parent
+	}
+}
+
+func fileExists(path string) bool {
+	info, err := os.Stat(path)
+	return err == nil && !info.IsDir()
+}
+
+// checkOverwrites refuses to replace an existing file the tool did not
+// generate unless force is set. Generated files import the framework, so a
+// file without a terraform-plugin-framework import is hand-written or SDK
+// code. Files marked as edits are SDK files the tool changes on purpose.
+func checkOverwrites(files []generatedFile, force bool) error {
+	if force {
+		return nil
+	}
+	for _, file := range files {
+		if file.edit || !fileExists(file.path) {
+			continue
+		}
+		generated, err := importsFramework(file.path)
+		if err != nil {
+			return err
+		}
+		if !generated {
+			return fmt.Errorf("%s already exists and was not generated by tf-provider-migrate (use --force to overwrite)", file.path)
+		}
+	}
+	return nil
+}
+
+func importsFramework(path string) (bool, error) {
+	file, err := parser.ParseFile(token.NewFileSet(), path, nil, parser.ImportsOnly)
+	if err != nil {
+		// not Go code the tool generated
+		return false, nil
+	}
+	for _, spec := range file.Imports {
+		importPath := strings.Trim(spec.Path.Value, `"`)
+		if importPath == frameworkModule || strings.HasPrefix(importPath, frameworkModule+"/") {
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
 func writeFile(path string, data []byte) error {
 	dir := filepath.Dir(path)
 	if err := os.MkdirAll(dir, 0o755); err != nil {
@@ -0,0 +1,101 @@
+package migrate
+
+import (
+	"bytes"
+	"fmt"
+	"go/ast"
+	"go/types"
+	"os"
+	"strings"
+)
+
+const (
+	importerPassthrough = "passthrough"
+	importerCustom      = "custom"
+)
+
+// importStateFields lists the schema.ResourceImporter fields holding the
+// import function, in order of preference.
+var importStateFields = []string{"StateContext", "State"}
+
+// parseImporter reads the Importer field of a schema.Resource literal. The
+// SDK passthrough functions map to resource.ImportStatePassthroughID; any
+// other function is kept in info.importer so it can be shown next to the
+// generated ImportState stub.
+func parseImporter(lit *ast.CompositeLit, info *ResourceInfo, res resolver) {
+	value, ok := compositeValue(lit, "Importer")
+	if !ok {
+		return
+	}
+
+	info.Importer = importerCustom
+	info.importer = crudFunc{field: "Importer", name: types.ExprString(value)}
+	if unary, ok := value.(*ast.UnaryExpr); ok {
+		value = unary.X
+	}
+	importerLit, ok := value.(*ast.CompositeLit)
+	if !ok {
+		return
+	}
+
+	for _, field := range importStateFields {
+		fn, ok := compositeValue(importerLit, field)
+		if !ok {
+			continue
+		}
+		if sel, ok := fn.(*ast.SelectorExpr); ok && strings.HasPrefix(sel.Sel.Name, "ImportStatePassthrough") {
+			info.Importer = importerPassthrough
+			return
+		}
+		info.importer = resolveFunc("Importer."+field, fn, res)
+		return
+	}
+}
+
+// compositeValue returns the value of the field key in a keyed composite
+// literal.
+func compositeValue(lit *ast.CompositeLit, key string) (ast.Expr, bool) {
+	for _, elt := range lit.Elts {
+		kv, ok := elt.(*ast.KeyValueExpr)
+		if !ok {
+			continue
+		}
+		if ident, ok := kv.Key.(*ast.Ident); ok && ident.Name == key {
+			return kv.Value, true
+		}
+	}
+	return nil, false
+}
+
+// referenceStub returns a TODO naming the SDK function kind, e.g. "importer",
+// followed by the function as a commented reference, for methods that are
+// not translated.
+func referenceStub(kind string, fn crudFunc, res resolver) (string, error) {
+	where := fn.describe()
+	if fn.file == nil {
+		return fmt.Sprintf("// TODO(migrate): port the SDK %s %s, which was not found in the module.", kind, where), nil
+	}
+
+	start, end := res.fset.Position(fn.node.Pos()), res.fset.Position(fn.node.End())
+	data, err := os.ReadFile(start.Filename)
+	if err != nil {
+		return "", err
+	}
+	lines := strings.Split(string(data[start.Offset:end.Offset]), "\n")
+	// Function literals start mid-line; the indentation to strip is that of
+	// the line they start on.
+	lineStart := bytes.LastIndexByte(data[:start.Offset], '\n') + 1
+	indent := len(data[lineStart:start.Offset]) - len(bytes.TrimLeft(data[lineStart:start.Offset], "\t"))
+
+	var b strings.Builder
+	fmt.Fprintf(&b, "// TODO(migrate): port the SDK %s %s:\n//", kind, where)
+	for i, line := range lines {
+		if i > 0 {
+			for j := 0; j < indent && strings.HasPrefix(line, "\t"); j++ {
+				line = line[1:]
+			}
+		}
+		b.WriteString(strings.TrimRight("\n// "+line, " "))
+	}
+	return b.String(), nil
+}
@@ -0,0 +1,102 @@
+package migrate
+
+import (
+	"fmt"
+	"go/token"
+	"path"
+	"path/filepath"
+	"strings"
+)
+
+const (
+	defaultLayoutDir        = "framework"
+	resourcesPackage        = "resources"
+	dataSourcesPackage      = "datasources"
+	frameworkProviderGo     = "provider.go"
+	frameworkProviderTestGo = "provider_test.go"
+	resourcesRegistryGo     = "resources.go"
+	dataSourceRegistryGo    = "datasources.go"
+)
+
+// Layout controls where the framework provider package is generated.
+//
+// Dir is relative to the module root and defaults to "framework". Package
+// defaults to the last element of Dir. With Split, resources and data
+// sources live in their own "resources" and "datasources" subpackages
+// instead of next to provider.go.
+type Layout struct {
+	Dir     string
+	Package string
+	Split   bool
+}
+
+func (l Layout) resolve() (Layout, error) {
+	dir := strings.TrimSpace(filepath.ToSlash(l.Dir))
+	if dir == "" {
+		dir = defaultLayoutDir
+	}
+	dir = path.Clean(dir)
+	if path.IsAbs(dir) || dir == "." || dir == ".." || strings.HasPrefix(dir, "../") {
+		return Layout{}, fmt.Errorf("layout dir %q must be a subdirectory of the module", l.Dir)
+	}
+
+	pkg := l.Package
+	if pkg == "" {
+		pkg = strings.ReplaceAll(path.Base(dir), "-", "")
+	}
+	if !token.IsIdentifier(pkg) || pkg == "main" {
+		return Layout{}, fmt.Errorf("layout package %q is not a valid package name", pkg)
+	}
+
+	return Layout{Dir: dir, Package: pkg, Split: l.Split}, nil
+}
+
+func (l Layout) providerFile(moduleRoot string) string {
+	return filepath.Join(moduleRoot, filepath.FromSlash(l.Dir), frameworkProviderGo)
+}
+
+func (l Layout) providerTestFile(moduleRoot string) string {
+	return filepath.Join(moduleRoot, filepath.FromSlash(l.Dir), frameworkProviderTestGo)
+}
+
+func (l Layout) resourcesFile(moduleRoot string) string {
+	return filepath.Join(moduleRoot, filepath.FromSlash(l.Dir), resourcesPackage, resourcesRegistryGo)
+}
+
+func (l Layout) dataSourcesFile(moduleRoot string) string {
+	return filepath.Join(moduleRoot, filepath.FromSlash(l.Dir), dataSourcesPackage, dataSourceRegistryGo)
+}
+
+func (l Layout) importPath(modulePath string) string {
+	return path.Join(modulePath, l.Dir)
+}
+
+// frameworkAlias returns the name main.go refers to the framework package by,
+// renaming it when it clashes with the SDK provider import or one of the
+// packages the muxed main imports.
+func frameworkAlias(pkg, providerAlias string) string {
+	switch pkg {
+	case providerAlias, "context", "log", "providerserver", "schema",
+		"tfprotov5", "tf5server", "tf5muxserver", "tfprotov6", "tf6server", "tf6muxserver", "tf5to6server":
+		return "fw" + pkg
+	default:
+		return pkg
+	}
+}
+
+// resourceFile returns where the framework resource or data source for the
+// type name base is generated, e.g. resource_widget.go next to provider.go or
+// resources/widget.go with Split.
+func (l Layout) resourceFile(moduleRoot, base string, dataSource bool) string {
+	dir := filepath.Join(moduleRoot, filepath.FromSlash(l.Dir))
+	switch {
+	case l.Split && dataSource:
+		return filepath.Join(dir, dataSourcesPackage, base+".go")
+	case l.Split:
+		return filepath.Join(dir, resourcesPackage, base+".go")
+	case dataSource:
+		return filepath.Join(dir, "data_source_"+base+".go")
+	default:
+		return filepath.Join(dir, "resource_"+base+".go")
+	}
+}
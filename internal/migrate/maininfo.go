@@ -78,6 +78,15 @@ func parseMainFile(node *ast.File) MainInfo {
 		info.ProviderImport = importPathForAlias(node, providerAlias)
 	}
 
+	for _, imp := range node.Imports {
+		switch path, _ := strconvUnquote(imp.Path.Value); path {
+		case muxModule + "/tf5muxserver":
+			info.ProtocolVersion = 5
+		case muxModule + "/tf6muxserver":
+			info.ProtocolVersion = 6
+		}
+	}
+
 	return info
 }
 
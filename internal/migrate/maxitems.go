@@ -0,0 +1,175 @@
+package migrate
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+)
+
+const (
+	maxItemsOneList            = "list"
+	maxItemsOneSingleBlock     = "single-block"
+	maxItemsOneSingleAttribute = "single-attribute"
+)
+
+// checkBlockStrategyKeys reports max_items_one keys that do not name a
+// top-level block of a registered resource or data source, e.g.
+// "example_widget.settings".
+func checkBlockStrategyKeys(info ProviderInfo, strategies map[string]string) error {
+	scopes := map[string]bool{}
+	for _, ref := range info.Resources {
+		scopes[ref.Name] = true
+	}
+	for _, ref := range info.DataSources {
+		scopes[ref.Name] = true
+	}
+
+	keys := make([]string, 0, len(strategies))
+	for key := range strategies {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	for _, key := range keys {
+		scope, block, ok := strings.Cut(key, ".")
+		if !ok || !scopes[scope] {
+			return fmt.Errorf("max_items_one %q does not match a resource or data source", key)
+		}
+		if strings.Contains(block, ".") {
+			return fmt.Errorf("max_items_one %q: only top-level blocks can change strategy", key)
+		}
+	}
+	return nil
+}
+
+// applyBlockStrategies sets the Strategy of the top-level blocks of info
+// named in strategies. Only list blocks with MaxItems: 1 can be rendered as
+// a single object, and a single nested attribute cannot hold blocks.
+func applyBlockStrategies(info *ResourceInfo, strategies map[string]string) error {
+	for i := range info.Blocks {
+		block := &info.Blocks[i]
+		strategy, ok := strategies[info.Name+"."+block.Name]
+		if !ok || strategy == maxItemsOneList {
+			continue
+		}
+		if block.isAttribute() {
+			return fmt.Errorf("max_items_one %s.%s: %s is an attribute in the SDK schema (ConfigMode or Computed)", info.Name, block.Name, block.Name)
+		}
+		if block.Kind != "list" || block.MaxItems != 1 {
+			return fmt.Errorf("max_items_one %s.%s: %s needs a TypeList block with MaxItems: 1", info.Name, block.Name, strategy)
+		}
+		if strategy == maxItemsOneSingleAttribute && len(block.Blocks) > 0 {
+			return fmt.Errorf("max_items_one %s.%s: %s cannot hold the nested blocks of %s", info.Name, block.Name, strategy, block.Name)
+		}
+		block.Strategy = strategy
+		singlePlanModifiers(info, block)
+	}
+
+	for key := range strategies {
+		scope, name, _ := strings.Cut(key, ".")
+		if scope != info.Name {
+			continue
+		}
+		found := false
+		for _, block := range info.Blocks {
+			found = found || block.Name == name
+		}
+		if !found {
+			return fmt.Errorf("max_items_one %s: %s has no block %s", key, info.Name, name)
+		}
+	}
+	return nil
+}
+
+// singleBlocks returns the top-level blocks rendered as a single object.
+func singleBlocks(blocks []Block) []Block {
+	var single []Block
+	for _, block := range blocks {
+		if block.Strategy == maxItemsOneSingleBlock || block.Strategy == maxItemsOneSingleAttribute {
+			single = append(single, block)
+		}
+	}
+	return single
+}
+
+// blockStrategyNotes explains the strategy of each top-level list block with
+// MaxItems: 1. Rendering one as a single object changes the state from a
+// list to an object, which resources upgrade with a generated state
+// upgrader.
+func blockStrategyNotes(info ResourceInfo) []string {
+	kind := "resource"
+	if info.DataSource {
+		kind = "data source"
+	}
+
+	var notes []string
+	for _, block := range info.Blocks {
+		if block.Kind != "list" || block.MaxItems != 1 || block.isAttribute() {
+			continue
+		}
+		switch {
+		case block.Strategy != "" && block.Strategy != maxItemsOneList && info.DataSource:
+			notes = append(notes, fmt.Sprintf("%s %s: block %s (MaxItems: 1) is migrated as %s", kind, info.Name, block.Name, block.Strategy))
+		case block.Strategy != "" && block.Strategy != maxItemsOneList:
+			notes = append(notes, fmt.Sprintf("%s %s: block %s (MaxItems: 1) is migrated as %s, which changes its state from a list to an object; the schema version is raised to %d with a state upgrader from version %d", kind, info.Name, block.Name, block.Strategy, info.SchemaVersion+1, info.SchemaVersion))
+		case !info.DataSource:
+			notes = append(notes, fmt.Sprintf("%s %s: block %s (MaxItems: 1) is migrated as a list block; max_items_one single-block or single-attribute (protocol 6) would change its state from a list to an object and need a state upgrader", kind, info.Name, block.Name))
+		}
+	}
+	if len(singleBlocks(info.Blocks)) > 0 && !info.DataSource && len(info.StateUpgrades) > 0 {
+		notes = append(notes, fmt.Sprintf("%s %s: the state upgraders ported from the SDK must produce version %d state, with single blocks as objects", kind, info.Name, info.SchemaVersion+1))
+	}
+	return notes
+}
+
+// blockStrategyUpgrader returns the state upgrader from the current SDK
+// schema version, whose single blocks are lists, and the model of that
+// prior state. ok is false when no block changes shape.
+func blockStrategyUpgrader(info ResourceInfo, models []ModelStruct, timeoutsCode string) (upgrader map[string]interface{}, prior ModelStruct, ok bool) {
+	single := map[string]bool{}
+	for _, block := range singleBlocks(info.Blocks) {
+		single[block.Name] = true
+	}
+	if info.DataSource || len(single) == 0 {
+		return nil, ModelStruct{}, false
+	}
+
+	priorBlocks := make([]Block, len(info.Blocks))
+	for i, block := range info.Blocks {
+		if single[block.Name] {
+			// The prior schema only decodes state, so the object plan
+			// modifiers of a single block have no list equivalent to keep.
+			block.Strategy, block.PlanModifiers = "", nil
+		}
+		priorBlocks[i] = block
+	}
+
+	model := models[0]
+	prior = ModelStruct{Name: fmt.Sprintf("%sV%d", model.Name, info.SchemaVersion)}
+	var body strings.Builder
+	fmt.Fprintf(&body, "var prior %s\nresp.Diagnostics.Append(req.State.Get(ctx, &prior)...)\nif resp.Diagnostics.HasError() {\nreturn\n}\n\n", prior.Name)
+	fmt.Fprintf(&body, "state := %s{\n", model.Name)
+	var unwrap []string
+	for _, field := range model.Fields {
+		if single[field.Tag] {
+			prior.Fields = append(prior.Fields, ModelField{Name: field.Name, Type: "[]" + strings.TrimPrefix(field.Type, "*"), Tag: field.Tag})
+			unwrap = append(unwrap, fmt.Sprintf("if len(prior.%s) > 0 {\nstate.%s = &prior.%s[0]\n}\n", field.Name, field.Name, field.Name))
+			continue
+		}
+		prior.Fields = append(prior.Fields, field)
+		fmt.Fprintf(&body, "%s: prior.%s,\n", field.Name, field.Name)
+	}
+	body.WriteString("}\n")
+	body.WriteString(strings.Join(unwrap, ""))
+	body.WriteString("\nresp.Diagnostics.Append(resp.State.Set(ctx, &state)...)")
+
+	priorBlocks, priorNested := splitSchemaBlocks(sortedBlocks(priorBlocks))
+	upgrader = map[string]interface{}{
+		"Version":          info.SchemaVersion,
+		"Attributes":       sortedAttributes(info.Attributes),
+		"NestedAttributes": priorNested,
+		"Blocks":           priorBlocks,
+		"Timeouts":         timeoutsCode,
+		"Body":             body.String(),
+	}
+	return upgrader, prior, true
+}
@@ -0,0 +1,77 @@
+package migrate
+
+import (
+	"bytes"
+	"fmt"
+	"strings"
+)
+
+// renderMetaAttributeLiteral renders an attribute of the ProviderMetaSchema
+// as a framework metaschema attribute, which only supports Required,
+// Optional, Description, CustomType and ElementType.
+func renderMetaAttributeLiteral(attr Attribute) string {
+	var buf bytes.Buffer
+
+	fmt.Fprintf(&buf, "metaschema.%s{", frameworkAttributeType(attr.Type))
+	if attr.Description != "" {
+		fmt.Fprintf(&buf, "Description: %q,", attr.Description)
+	}
+	if attr.Required {
+		fmt.Fprintf(&buf, "Required: true,")
+	}
+	if attr.Optional {
+		fmt.Fprintf(&buf, "Optional: true,")
+	}
+	if attr.CustomType != "" {
+		fmt.Fprintf(&buf, "CustomType: %s,", attr.CustomType)
+	}
+	if attr.Type == "list" || attr.Type == "set" || attr.Type == "map" {
+		fmt.Fprintf(&buf, "ElementType: %s,", renderElementType(attr))
+	}
+	buf.WriteString("}")
+
+	return buf.String()
+}
+
+// checkMetaSchema refuses a ProviderMetaSchema the framework meta schema
+// cannot match. Mux requires both providers to serve the same provider_meta
+// schema, and framework meta schema attributes can be neither Computed nor
+// Sensitive and there are no meta schema blocks.
+func checkMetaSchema(info ProviderInfo) error {
+	var problems []string
+	for _, attr := range info.MetaAttributes {
+		if attr.Computed {
+			problems = append(problems, fmt.Sprintf("%s is Computed", attr.Name))
+		}
+		if attr.Sensitive {
+			problems = append(problems, fmt.Sprintf("%s is Sensitive", attr.Name))
+		}
+	}
+	for _, block := range info.MetaBlocks {
+		problems = append(problems, fmt.Sprintf("%s is a block", block.Name))
+	}
+	if len(problems) > 0 {
+		return fmt.Errorf("provider_meta: %s, which the framework meta schema cannot declare; mux rejects the differing provider_meta schemas", strings.Join(problems, ", "))
+	}
+	return nil
+}
+
+// metaSchemaNotes lists the SDK-only behavior of the ProviderMetaSchema that
+// the framework meta schema leaves out. Neither is part of the schema served
+// over the protocol, so the muxed schemas still match.
+func metaSchemaNotes(info ProviderInfo) []string {
+	var notes []string
+	for _, attr := range info.MetaAttributes {
+		var dropped []string
+		if attr.Default != "" {
+			dropped = append(dropped, "Default")
+		}
+		if attr.ValidateFunc != "" {
+			dropped = append(dropped, "ValidateFunc")
+		}
+		if len(dropped) > 0 {
+			notes = append(notes, fmt.Sprintf("provider_meta: %s: %s dropped, framework meta schema attributes only have Required, Optional and Description", attr.Name, strings.Join(dropped, ", ")))
+		}
+	}
+	return notes
+}
@@ -0,0 +1,72 @@
+package migrate
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestCheckMetaSchema(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name      string
+		info      ProviderInfo
+		wantErr   string
+		wantNotes []string
+	}{
+		{
+			name: "optional and required",
+			info: ProviderInfo{MetaAttributes: []Attribute{
+				{Name: "module_name", Type: "string", Optional: true},
+				{Name: "team", Type: "string", Required: true},
+			}},
+		},
+		{
+			name: "default and validation",
+			info: ProviderInfo{MetaAttributes: []Attribute{
+				{Name: "team", Type: "string", Optional: true, Default: `"platform"`, ValidateFunc: "validation.NoZeroValues"},
+			}},
+			wantNotes: []string{"provider_meta: team: Default, ValidateFunc dropped, framework meta schema attributes only have Required, Optional and Description"},
+		},
+		{
+			name: "sensitive",
+			info: ProviderInfo{MetaAttributes: []Attribute{
+				{Name: "team", Type: "string", Optional: true, Sensitive: true},
+			}},
+			wantErr: "provider_meta: team is Sensitive, which the framework meta schema cannot declare",
+		},
+		{
+			name: "computed",
+			info: ProviderInfo{MetaAttributes: []Attribute{
+				{Name: "team", Type: "string", Optional: true, Computed: true},
+			}},
+			wantErr: "provider_meta: team is Computed, which the framework meta schema cannot declare",
+		},
+		{
+			name:    "block",
+			info:    ProviderInfo{MetaBlocks: []Block{{Name: "owner", Kind: "list"}}},
+			wantErr: "provider_meta: owner is a block, which the framework meta schema cannot declare",
+		},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			err := checkMetaSchema(tt.info)
+			if tt.wantErr != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+					t.Fatalf("expected an error containing %q, got %v", tt.wantErr, err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("check meta schema: %v", err)
+			}
+			if notes := metaSchemaNotes(tt.info); !reflect.DeepEqual(notes, tt.wantNotes) {
+				t.Fatalf("unexpected notes %v, want %v", notes, tt.wantNotes)
+			}
+		})
+	}
+}
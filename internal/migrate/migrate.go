@@ -3,117 +3,426 @@ package migrate
 import (
 	"errors"
 	"fmt"
+	"os"
 	"path/filepath"
 )
 
 var ErrDryRun = errors.New("dry run")
 
+// migration holds everything Check and Migrate learn about a provider module
+// before generating code.
+type migration struct {
+	opts         Options
+	layout       Layout
+	templates    templateSet
+	moduleRoot   string
+	modulePath   string
+	providerInfo ProviderInfo
+	mainFile     string
+	mainInfo     MainInfo
+	// configure is the framework Configure body ported from the SDK
+	// configure function with --configure independent.
+	configure crudTranslation
+	report    Report
+}
+
 func Check(opts Options) (Report, error) {
-	moduleRoot, err := findModuleRoot(opts.Path)
+	m, err := prepare(opts, false)
 	if err != nil {
 		return Report{}, err
 	}
 
-	providerInfo, err := findProviderInfo(moduleRoot)
+	return m.report, nil
+}
+
+func Migrate(opts Options) (Report, error) {
+	m, err := prepare(opts, true)
 	if err != nil {
 		return Report{}, err
 	}
+	report := m.report
 
-	mainFile, mainInfo, err := findMainInfo(moduleRoot)
+	frameworkSource, err := renderFrameworkProvider(m.templates, m.providerInfo, report.ProviderName, m.layout, m.modulePath, m.opts.Configure, m.configure)
 	if err != nil {
 		return Report{}, err
 	}
+	files := []generatedFile{{path: report.FrameworkFile, source: frameworkSource}}
 
-	providerName, registryAddress, notes, err := deriveNames(opts, moduleRoot, false)
+	// The test covers the SDK meta value handed out in shared mode.
+	testFile := m.layout.providerTestFile(m.moduleRoot)
+	if m.opts.Configure == configureShared {
+		testSource, err := renderFrameworkProviderTest(m.templates, m.mainInfo, m.layout, report.ProtocolVersion)
+		if err != nil {
+			return Report{}, err
+		}
+		files = append(files, generatedFile{path: testFile, source: testSource})
+	} else if fileExists(testFile) {
+		report.Notes = append(report.Notes, fmt.Sprintf("%s tests the shared configure mode and fails with --configure %s; remove it", testFile, configureIndependent))
+	}
+
+	if m.layout.Split {
+		resourcesSource, err := renderRegistry(m.templates, resourcesPackage, m.providerInfo.MetaType)
+		if err != nil {
+			return Report{}, err
+		}
+		dataSourcesSource, err := renderRegistry(m.templates, dataSourcesPackage, m.providerInfo.MetaType)
+		if err != nil {
+			return Report{}, err
+		}
+		files = append(files,
+			generatedFile{path: m.layout.resourcesFile(m.moduleRoot), source: resourcesSource},
+			generatedFile{path: m.layout.dataSourcesFile(m.moduleRoot), source: dataSourcesSource},
+		)
+	}
+
+	mainSource, err := renderMuxedMain(m.templates, m.mainInfo, report.RegistryAddress, report.ProtocolVersion, m.layout, m.modulePath)
 	if err != nil {
 		return Report{}, err
 	}
 
-	if mainInfo.ProviderImport == "" {
-		return Report{}, fmt.Errorf("main package does not reference provider.Provider()")
+	if err := checkOverwrites(files, m.opts.Force); err != nil {
+		return Report{}, err
 	}
 
-	report := Report{
-		ModuleRoot:      moduleRoot,
-		MainFile:        mainFile,
-		FrameworkFile:   filepath.Join(moduleRoot, "framework", "provider.go"),
-		ProviderName:    providerName,
-		RegistryAddress: registryAddress,
-		Attributes:      len(providerInfo.Attributes),
-		Notes:           notes,
+	if m.opts.DryRun {
+		report.Notes = append(report.Notes, "dry-run (no files written)")
+		return report, ErrDryRun
 	}
 
-	return report, nil
-}
+	for _, file := range files {
+		if err := writeFile(file.path, file.source); err != nil {
+			return Report{}, err
+		}
+	}
 
-func Migrate(opts Options) (Report, error) {
-	moduleRoot, err := findModuleRoot(opts.Path)
-	if err != nil {
+	if err := writeFile(m.mainFile, mainSource); err != nil {
 		return Report{}, err
 	}
 
-	providerInfo, err := findProviderInfo(moduleRoot)
+	modChanges, err := ensureModuleDeps(m.moduleRoot, baseModules, m.opts.Dependencies, m.opts.NoUpgrade)
 	if err != nil {
 		return Report{}, err
 	}
+	report.ModChanges = modChanges
 
-	mainFile, mainInfo, err := findMainInfo(moduleRoot)
-	if err != nil {
+	if err := ensureGoSum(m.moduleRoot, baseModules, m.opts.Dependencies); err != nil {
 		return Report{}, err
 	}
 
-	providerName, registryAddress, notes, err := deriveNames(opts, moduleRoot, true)
+	return report, nil
+}
+
+// MigrateResources generates framework resources and data sources for the
+// selected SDK registry entries and removes those entries from the SDK
+// provider. Migrate must have run first.
+func MigrateResources(opts Options) (Report, error) {
+	m, err := prepare(opts, true)
 	if err != nil {
 		return Report{}, err
 	}
+	report := m.report
 
-	if mainInfo.ProviderImport == "" {
-		return Report{}, fmt.Errorf("main package does not reference provider.Provider()")
+	if !fileExists(report.FrameworkFile) {
+		return Report{}, fmt.Errorf("%s not found: run migrate before migrate-resources", report.FrameworkFile)
 	}
-
-	report := Report{
-		ModuleRoot:      moduleRoot,
-		MainFile:        mainFile,
-		FrameworkFile:   filepath.Join(moduleRoot, "framework", "provider.go"),
-		ProviderName:    providerName,
-		RegistryAddress: registryAddress,
-		Attributes:      len(providerInfo.Attributes),
-		Notes:           notes,
+	// nested attributes, timeouts attributes and single attributes depend on
+	// the protocol main.go serves
+	switch served := m.mainInfo.ProtocolVersion; {
+	case served == 0:
+		return Report{}, fmt.Errorf("%s does not serve the muxed provider: run migrate before migrate-resources", m.mainFile)
+	case served != report.ProtocolVersion:
+		return Report{}, fmt.Errorf("protocol_version %d does not match %s, which serves protocol %d; rerun migrate with --protocol-version %d first", report.ProtocolVersion, m.mainFile, served, report.ProtocolVersion)
 	}
 
-	frameworkPath := filepath.Join(moduleRoot, "framework", "provider.go")
-	frameworkSource, err := renderFrameworkProvider(providerInfo, report.ProviderName)
+	resources, err := selectResources("resource", m.providerInfo.Resources, m.opts.Resources, m.opts.SkipResources, m.opts.AllResources)
 	if err != nil {
 		return Report{}, err
 	}
-
-	mainSource, err := renderMuxedMain(mainInfo, report.RegistryAddress)
+	dataSources, err := selectResources("data source", m.providerInfo.DataSources, m.opts.DataSources, m.opts.SkipDataSources, m.opts.AllResources)
 	if err != nil {
 		return Report{}, err
 	}
-
-	if opts.DryRun {
-		report.Notes = append(report.Notes, "dry-run (no files written)")
-		return report, ErrDryRun
+	if len(resources) == 0 && len(dataSources) == 0 {
+		return Report{}, fmt.Errorf("no resources or data sources selected")
 	}
 
-	if err := writeFile(frameworkPath, frameworkSource); err != nil {
+	var files []generatedFile
+	var modules []string
+	required := map[string]bool{}
+	migrated, overridden := map[string]bool{}, map[string]bool{}
+	generate := func(ref ResourceRef, dataSource bool) error {
+		info, err := parseResource(ref, dataSource, m.providerInfo.res)
+		if err != nil {
+			return fmt.Errorf("%s: %w", ref.Name, err)
+		}
+		migrated[ref.Name] = true
+		applyResourceOverrides(&info, m.opts.AttributeOverrides, overridden)
+		if err := applyBlockStrategies(&info, m.opts.MaxItemsOne); err != nil {
+			return fmt.Errorf("%s: %w", ref.Name, err)
+		}
+		applyConfigModes(&info, m.opts.ProtocolVersion)
+		report.Notes = append(report.Notes, blockStrategyNotes(info)...)
+		for _, mapping := range info.Mappings {
+			report.Notes = append(report.Notes, fmt.Sprintf("%s.%s", ref.Name, mapping.relativeTo(m.moduleRoot)))
+		}
+		bodies, notes := translateResource(info, m.providerInfo.res)
+		report.Notes = append(report.Notes, notes...)
+		needed := customTypeModules(info.Attributes, info.Blocks)
+		if info.Timeouts != nil {
+			needed = append(needed, timeoutsModule)
+		}
+		for _, module := range needed {
+			if !required[module] {
+				required[module] = true
+				modules = append(modules, module)
+			}
+		}
+		source, err := renderResource(m.templates, info, bodies, report.ProviderName, m.layout, m.opts.Timeouts, m.providerInfo.MetaType)
+		if err != nil {
+			return fmt.Errorf("%s: %w", ref.Name, err)
+		}
+		path := m.layout.resourceFile(m.moduleRoot, resourceTypeBase(ref.Name, report.ProviderName), dataSource)
+		files = append(files, generatedFile{path: path, source: source})
+		report.Notes = append(report.Notes, fmt.Sprintf("%s: generated %s", ref.Name, path))
+		return nil
+	}
+	for _, ref := range resources {
+		if err := generate(ref, false); err != nil {
+			return Report{}, err
+		}
+	}
+	for _, ref := range dataSources {
+		if err := generate(ref, true); err != nil {
+			return Report{}, err
+		}
+	}
+	if err := checkResourceOverrides(m.opts.AttributeOverrides, migrated, overridden); err != nil {
 		return Report{}, err
 	}
 
-	if err := writeFile(mainFile, mainSource); err != nil {
+	edited, err := removeRegistryEntries(m.providerInfo.res.fset, append(append([]ResourceRef{}, resources...), dataSources...))
+	if err != nil {
 		return Report{}, err
 	}
+	for path, source := range edited {
+		files = append(files, generatedFile{path: path, source: source, edit: true})
+	}
 
-	if err := ensureModuleDeps(moduleRoot); err != nil {
+	// the base modules were required by migrate
+	modChanges, err := planModuleDeps(m.moduleRoot, modules, m.opts.Dependencies, m.opts.NoUpgrade)
+	if err != nil {
 		return Report{}, err
 	}
+	report.ModChanges = modChanges
 
-	if err := ensureGoSum(moduleRoot); err != nil {
+	if err := checkOverwrites(files, m.opts.Force); err != nil {
 		return Report{}, err
 	}
 
+	if m.opts.DryRun {
+		report.Notes = append(report.Notes, "dry-run (no files written)")
+		return report, ErrDryRun
+	}
+
+	for _, file := range files {
+		if err := writeFile(file.path, file.source); err != nil {
+			return Report{}, err
+		}
+	}
+
+	if len(modules) > 0 {
+		if _, err := ensureModuleDeps(m.moduleRoot, modules, m.opts.Dependencies, m.opts.NoUpgrade); err != nil {
+			return Report{}, err
+		}
+		if err := ensureGoSum(m.moduleRoot, modules, m.opts.Dependencies); err != nil {
+			return Report{}, err
+		}
+	}
+
 	return report, nil
 }
 
+// prepare locates and parses the provider module. With strict set, names
+// that cannot be derived are errors instead of notes.
+func prepare(opts Options, strict bool) (migration, error) {
+	moduleRoot, err := findModuleRoot(opts.Path)
+	if err != nil {
+		return migration{}, err
+	}
+
+	mainFile, mainInfo, err := findMainInfo(moduleRoot)
+	if err != nil {
+		return migration{}, err
+	}
+
+	opts, settings, err := resolveOptions(opts, moduleRoot, mainInfo.ProtocolVersion, os.LookupEnv)
+	if err != nil {
+		return migration{}, err
+	}
+
+	layout, err := opts.Layout.resolve()
+	if err != nil {
+		return migration{}, err
+	}
+
+	tmpls, err := loadTemplates(opts.TemplatesDir)
+	if err != nil {
+		return migration{}, err
+	}
+
+	providerInfo, err := findProviderInfo(moduleRoot)
+	if err != nil {
+		return migration{}, err
+	}
+
+	if err := applyAttributeOverrides(&providerInfo, opts.AttributeOverrides); err != nil {
+		return migration{}, err
+	}
+	if err := checkBlockStrategyKeys(providerInfo, opts.MaxItemsOne); err != nil {
+		return migration{}, err
+	}
+	if err := checkMetaSchema(providerInfo); err != nil {
+		return migration{}, err
+	}
+	providerInfo.Blocks = configModeBlocks("", providerInfo.Blocks, opts.ProtocolVersion, "", nil)
+
+	providerName, registryAddress, notes, err := deriveNames(opts, moduleRoot, strict)
+	if err != nil {
+		return migration{}, err
+	}
+	if opts.ProviderName == "" && providerName != "" {
+		settings = append(settings, Setting{Name: "provider_name", Value: providerName, Source: SourceDerived})
+	}
+	if opts.RegistryAddress == "" && registryAddress != "" {
+		settings = append(settings, Setting{Name: "registry_address", Value: registryAddress, Source: SourceDerived})
+	}
+	if workspace := findWorkspaceRoot(moduleRoot); workspace != "" {
+		notes = append(notes, fmt.Sprintf("module is part of the go.work workspace at %s", workspace))
+	}
+	notes = append(notes, skipListNotes("resources.skip", opts.SkipResources, providerInfo.Resources)...)
+	notes = append(notes, skipListNotes("data_sources.skip", opts.SkipDataSources, providerInfo.DataSources)...)
+	notes = append(notes, registryNotes(providerInfo, moduleRoot)...)
+	notes = append(notes, metaTypeNotes(providerInfo)...)
+	notes = append(notes, metaSchemaNotes(providerInfo)...)
+	var configure crudTranslation
+	if opts.Configure == configureIndependent {
+		translation, configureNotes, err := translateConfigure(providerInfo, providerName)
+		if err != nil {
+			return migration{}, err
+		}
+		configure = translation
+		notes = append(notes, configureNotes...)
+	}
+	notes = append(notes, sensitiveBlockNotes("provider", providerInfo.Blocks)...)
+	notes = append(notes, unevaluatedDescriptionNotes("provider", providerInfo.Attributes, providerInfo.Blocks)...)
+	notes = append(notes, conditionalSchemaNotes("provider", providerInfo.Attributes, providerInfo.Blocks)...)
+	notes = append(notes, resourceNotes(providerInfo, opts.MaxItemsOne)...)
+
+	if mainInfo.ProviderImport == "" {
+		return migration{}, fmt.Errorf("main package does not reference provider.Provider()")
+	}
+
+	modulePath, err := modulePathFromGoMod(filepath.Join(moduleRoot, "go.mod"))
+	if err != nil {
+		return migration{}, err
+	}
+	if layout.importPath(modulePath) == mainInfo.ProviderImport {
+		return migration{}, fmt.Errorf("layout dir %q is the SDK provider package %s; the framework provider needs a directory of its own", layout.Dir, mainInfo.ProviderImport)
+	}
+
+	modChanges, err := planModuleDeps(moduleRoot, baseModules, opts.Dependencies, opts.NoUpgrade)
+	if err != nil {
+		return migration{}, err
+	}
+
+	report := Report{
+		ModuleRoot:      moduleRoot,
+		MainFile:        mainFile,
+		FrameworkFile:   layout.providerFile(moduleRoot),
+		ProviderName:    providerName,
+		RegistryAddress: registryAddress,
+		ProtocolVersion: opts.ProtocolVersion,
+		Attributes:      len(providerInfo.Attributes),
+		Resources:       len(providerInfo.Resources),
+		DataSources:     len(providerInfo.DataSources),
+		Settings:        settings,
+		ModChanges:      modChanges,
+		Notes:           notes,
+	}
+
+	return migration{
+		opts:         opts,
+		layout:       layout,
+		templates:    tmpls,
+		moduleRoot:   moduleRoot,
+		modulePath:   modulePath,
+		providerInfo: providerInfo,
+		mainFile:     mainFile,
+		mainInfo:     mainInfo,
+		configure:    configure,
+		report:       report,
+	}, nil
+}
+
+func skipListNotes(setting string, skip []string, registered []ResourceRef) []string {
+	known := make(map[string]bool, len(registered))
+	for _, ref := range registered {
+		known[ref.Name] = true
+	}
+
+	var notes []string
+	for _, name := range skip {
+		if !known[name] {
+			notes = append(notes, fmt.Sprintf("%s: %s is not registered by the provider", setting, name))
+		}
+	}
+	return notes
+}
+
+// resourceNotes reports registry entries whose schema cannot be parsed, and
+// that migrate-resources would therefore refuse, and resources that need
+// attention when they are migrated.
+func resourceNotes(info ProviderInfo, strategies map[string]string) []string {
+	var notes []string
+	for _, ref := range info.Resources {
+		resource, err := parseResource(ref, false, info.res)
+		if err == nil {
+			err = applyBlockStrategies(&resource, strategies)
+		}
+		if err != nil {
+			notes = append(notes, fmt.Sprintf("resource %s: %v", ref.Name, err))
+			continue
+		}
+		notes = append(notes, idFormatNotes(resource)...)
+		notes = append(notes, blockStrategyNotes(resource)...)
+		notes = append(notes, sensitiveBlockNotes("resource "+ref.Name, resource.Blocks)...)
+		notes = append(notes, unevaluatedDescriptionNotes("resource "+ref.Name, resource.Attributes, resource.Blocks)...)
+		notes = append(notes, conditionalSchemaNotes("resource "+ref.Name, resource.Attributes, resource.Blocks)...)
+	}
+	for _, ref := range info.DataSources {
+		dataSource, err := parseResource(ref, true, info.res)
+		if err == nil {
+			err = applyBlockStrategies(&dataSource, strategies)
+		}
+		if err != nil {
+			notes = append(notes, fmt.Sprintf("data source %s: %v", ref.Name, err))
+			continue
+		}
+		notes = append(notes, idFormatNotes(dataSource)...)
+		notes = append(notes, blockStrategyNotes(dataSource)...)
+		notes = append(notes, sensitiveBlockNotes("data source "+ref.Name, dataSource.Blocks)...)
+		notes = append(notes, unevaluatedDescriptionNotes("data source "+ref.Name, dataSource.Attributes, dataSource.Blocks)...)
+		notes = append(notes, conditionalSchemaNotes("data source "+ref.Name, dataSource.Attributes, dataSource.Blocks)...)
+	}
+	return notes
+}
+
+type generatedFile struct {
+	path   string
+	source []byte
+	// edit marks a file of the SDK provider that is rewritten on purpose,
+	// as opposed to a generated one.
+	edit bool
+}
+
 // vendoring is intentionally not performed by this tool
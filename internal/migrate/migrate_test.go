@@ -1,9 +1,13 @@
 package migrate
 
 import (
+	"fmt"
 	"os"
 	"os/exec"
 	"path/filepath"
+	"reflect"
+	"sort"
+	"strings"
 	"testing"
 
 	"golang.org/x/mod/modfile"
@@ -34,6 +38,757 @@ func TestMigrateFixtures(t *testing.T) {
 	}
 }
 
+func TestMigrateWorkspace(t *testing.T) {
+	t.Parallel()
+
+	target := prepareFixture(t, "workspace")
+	opts := Options{Path: target}
+
+	report, err := Check(opts)
+	if err != nil {
+		t.Fatalf("check failed: %v", err)
+	}
+
+	moduleRoot := filepath.Join(target, "terraform-provider-ws")
+	if report.ModuleRoot != moduleRoot {
+		t.Fatalf("expected module root %s, got %s", moduleRoot, report.ModuleRoot)
+	}
+	if report.MainFile != filepath.Join(moduleRoot, "main.go") {
+		t.Fatalf("expected provider main.go, got %s", report.MainFile)
+	}
+
+	if _, err := Migrate(opts); err != nil {
+		t.Fatalf("migrate failed: %v", err)
+	}
+
+	runGoTest(t, moduleRoot)
+}
+
+func TestMigrateLayout(t *testing.T) {
+	t.Parallel()
+
+	target := prepareFixture(t, "mock")
+	opts := Options{
+		Path: target,
+		Layout: Layout{
+			Dir:   "internal/framework/provider",
+			Split: true,
+		},
+	}
+
+	report, err := Migrate(opts)
+	if err != nil {
+		t.Fatalf("migrate failed: %v", err)
+	}
+
+	want := filepath.Join(target, "internal", "framework", "provider", "provider.go")
+	if report.FrameworkFile != want {
+		t.Fatalf("expected framework file %s, got %s", want, report.FrameworkFile)
+	}
+	for _, sub := range []string{"resources", "datasources"} {
+		if _, err := os.Stat(filepath.Join(target, "internal", "framework", "provider", sub, sub+".go")); err != nil {
+			t.Fatalf("expected %s registry: %v", sub, err)
+		}
+	}
+
+	runGoTest(t, target)
+}
+
+func TestMigrateOverwrite(t *testing.T) {
+	t.Parallel()
+
+	target := prepareFixture(t, "mock")
+	if _, err := Check(Options{Path: target, Layout: Layout{Dir: "provider"}}); err == nil || !strings.Contains(err.Error(), "is the SDK provider package") {
+		t.Fatalf("expected the SDK provider package to be rejected as layout dir, got %v", err)
+	}
+
+	handWritten := filepath.Join(target, defaultLayoutDir, frameworkProviderGo)
+	if err := writeFile(handWritten, []byte("package framework\n")); err != nil {
+		t.Fatalf("write file: %v", err)
+	}
+	if _, err := Migrate(Options{Path: target}); err == nil || !strings.Contains(err.Error(), "was not generated by tf-provider-migrate") {
+		t.Fatalf("expected a hand-written provider.go not to be overwritten, got %v", err)
+	}
+	if _, err := Migrate(Options{Path: target, Force: true}); err != nil {
+		t.Fatalf("migrate with force failed: %v", err)
+	}
+	// a generated provider.go is replaced without force
+	if _, err := Migrate(Options{Path: target}); err != nil {
+		t.Fatalf("migrate over generated files failed: %v", err)
+	}
+
+	runGoTest(t, target)
+}
+
+func TestMigrateCustomTemplates(t *testing.T) {
+	t.Parallel()
+
+	target := prepareFixture(t, "mock")
+	templatesDir := t.TempDir()
+	if _, err := ExportTemplates(templatesDir, false); err != nil {
+		t.Fatalf("export templates: %v", err)
+	}
+
+	header := "// Copyright (c) Acme Corp.\n// Code generated by tf-provider-migrate; DO NOT EDIT.\n\n"
+	frameworkTmpl := filepath.Join(templatesDir, frameworkTemplateFile)
+	data, err := os.ReadFile(frameworkTmpl)
+	if err != nil {
+		t.Fatalf("read template: %v", err)
+	}
+	if err := os.WriteFile(frameworkTmpl, append([]byte(header), data...), 0o644); err != nil {
+		t.Fatalf("write template: %v", err)
+	}
+
+	report, err := Migrate(Options{Path: target, TemplatesDir: templatesDir})
+	if err != nil {
+		t.Fatalf("migrate failed: %v", err)
+	}
+
+	generated, err := os.ReadFile(report.FrameworkFile)
+	if err != nil {
+		t.Fatalf("read generated provider: %v", err)
+	}
+	if !strings.HasPrefix(string(generated), header) {
+		t.Fatalf("expected generated provider to start with template header, got:\n%s", generated)
+	}
+
+	runGoTest(t, target)
+
+	if err := os.WriteFile(frameworkTmpl, []byte("package {{ .Package }}\n\nfunc {{ .Missing }}() {}\n"), 0o644); err != nil {
+		t.Fatalf("write template: %v", err)
+	}
+	if _, err := Check(Options{Path: target, TemplatesDir: templatesDir}); err == nil {
+		t.Fatalf("expected invalid template to be rejected")
+	}
+}
+
+func TestMigrateConfigFile(t *testing.T) {
+	t.Parallel()
+
+	target := prepareFixture(t, "configured")
+	report, err := Migrate(Options{Path: target, ProviderName: "flagged"})
+	if err != nil {
+		t.Fatalf("migrate failed: %v", err)
+	}
+
+	if report.ProviderName != "flagged" {
+		t.Errorf("expected flag to win over config, got provider name %q", report.ProviderName)
+	}
+	if report.RegistryAddress != "registry.terraform.io/acme-corp/configured" {
+		t.Errorf("expected registry address from config, got %q", report.RegistryAddress)
+	}
+	if report.ProtocolVersion != 6 {
+		t.Errorf("expected protocol 6 from config, got %d", report.ProtocolVersion)
+	}
+	if want := filepath.Join(target, "internal", "fwprovider", "provider.go"); report.FrameworkFile != want {
+		t.Errorf("expected framework file %s, got %s", want, report.FrameworkFile)
+	}
+
+	sources := map[string]string{}
+	for _, setting := range report.Settings {
+		sources[setting.Name] = setting.Source
+	}
+	for name, source := range map[string]string{
+		"provider_name":    SourceFlag,
+		"registry_address": SourceConfig,
+		"protocol_version": SourceConfig,
+		"layout.dir":       SourceConfig,
+		"resources.skip":   SourceConfig,
+	} {
+		if sources[name] != source {
+			t.Errorf("expected %s from %s, got %q", name, source, sources[name])
+		}
+	}
+
+	generated, err := os.ReadFile(report.FrameworkFile)
+	if err != nil {
+		t.Fatalf("read generated provider: %v", err)
+	}
+	if strings.Contains(string(generated), "legacy_mode") {
+		t.Errorf("expected skipped attribute to be omitted:\n%s", generated)
+	}
+	if !strings.Contains(string(generated), `Description: "API key used to authenticate", Optional: true, Sensitive: true`) {
+		t.Errorf("expected api_key override to be applied:\n%s", generated)
+	}
+
+	runGoTest(t, target)
+}
+
+func TestMigrateResources(t *testing.T) {
+	t.Parallel()
+
+	for _, split := range []bool{false, true} {
+		split := split
+		t.Run(fmt.Sprintf("split=%t", split), func(t *testing.T) {
+			t.Parallel()
+
+			target := prepareFixture(t, "resources")
+			// Nested attributes need protocol 6; the split layout covers them.
+			protocol := 5
+			if split {
+				protocol = 6
+			}
+			opts := Options{Path: target, Layout: Layout{Split: split}, ProtocolVersion: protocol}
+
+			checkReport, err := Check(opts)
+			if err != nil {
+				t.Fatalf("check failed: %v", err)
+			}
+
+			t.Run("check", func(t *testing.T) {
+				notes := strings.Join(checkReport.Notes, "\n")
+				idNotes := 0
+				for _, note := range checkReport.Notes {
+					if strings.HasPrefix(note, "resource resources_counter:") && strings.Contains(note, "strconv.") {
+						idNotes++
+					}
+				}
+				if idNotes != 2 {
+					t.Errorf("expected two numeric ID warnings for resources_counter, got %v", checkReport.Notes)
+				}
+				if checkReport.Resources != 5 {
+					t.Errorf("expected the five resources of the merged ResourcesMap, got %d", checkReport.Resources)
+				}
+				for _, tt := range []struct {
+					name string
+					want string
+				}{
+					{"list of lists of objects", `data source resources_matrix: schema attribute "cells": Elem schema of type list with a schema.Resource Elem has no framework element type`},
+					{"sensitive block", "resource resources_gadget: block credentials is Sensitive, which framework blocks do not support; every attribute inside it is marked Sensitive instead"},
+					{"MaxItems 1 block", "resource resources_gadget: block dimensions (MaxItems: 1) is migrated as a list block; max_items_one single-block"},
+					{"unfollowed registry call", "provider ResourcesMap: registerPlugins(resources) (provider/provider.go:21:2) -> not followed"},
+					{"non-constant description", `resource resources_extra: Description of zone is not a constant expression ("Zone in one of " + strings.Join(regions, ", ")) and is left out of the framework schema`},
+					{"loop attribute alpha", "data source resources_features: alpha is set or deleted inside an if, switch or loop"},
+					{"loop attribute gamma", "data source resources_features: gamma is set or deleted inside an if, switch or loop"},
+					{"conditional attribute", "resource resources_extra: beta is set or deleted inside an if, switch or loop, a non-deterministic schema that mux rejects"},
+					{"meta type", "provider: the SDK provider configures a *sdkprovider.Client"},
+					{"provider_meta default", "provider_meta: team: Default dropped, framework meta schema attributes only have Required, Optional and Description"},
+					{"unresolved state upgrader", "resource resources_legacy: state upgrader 0: Type resourceLegacyTypeV0()"},
+				} {
+					if !strings.Contains(notes, tt.want) {
+						t.Errorf("%s: expected %q in the check report, got %v", tt.name, tt.want, checkReport.Notes)
+					}
+				}
+			})
+
+			if _, err := MigrateResources(Options{Path: target, AllResources: true}); err == nil {
+				t.Fatalf("expected migrate-resources to require the framework provider")
+			}
+			if _, err := Migrate(opts); err != nil {
+				t.Fatalf("migrate failed: %v", err)
+			}
+
+			t.Run("refused", func(t *testing.T) {
+				for _, tt := range []struct {
+					name    string
+					opts    Options
+					wantErr string
+				}{
+					{
+						name:    "unresolved state upgrader",
+						opts:    Options{Path: target, Resources: []string{"resources_legacy"}, Layout: opts.Layout, ProtocolVersion: protocol},
+						wantErr: "resources_legacy",
+					},
+					{
+						name:    "element type without framework equivalent",
+						opts:    Options{Path: target, DataSources: []string{"resources_matrix"}, Layout: opts.Layout, ProtocolVersion: protocol},
+						wantErr: "resources_matrix",
+					},
+					{
+						name:    "protocol main.go does not serve",
+						opts:    Options{Path: target, AllResources: true, Layout: opts.Layout, ProtocolVersion: 11 - protocol},
+						wantErr: fmt.Sprintf("which serves protocol %d", protocol),
+					},
+					{
+						name: "override of an unknown attribute",
+						opts: Options{
+							Path:               target,
+							Layout:             opts.Layout,
+							AllResources:       true,
+							SkipResources:      []string{"resources_legacy"},
+							SkipDataSources:    []string{"resources_matrix"},
+							AttributeOverrides: map[string]AttributeOverride{"resources_gadget.nope": {Skip: true}},
+						},
+						wantErr: `"resources_gadget.nope" does not match an attribute of resources_gadget`,
+					},
+				} {
+					if _, err := MigrateResources(tt.opts); err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+						t.Errorf("%s: expected an error containing %q, got %v", tt.name, tt.wantErr, err)
+					}
+				}
+			})
+
+			// the protocol version is taken from the muxed main.go
+			opts.ProtocolVersion = 0
+			opts.AllResources = true
+			opts.SkipResources = []string{"resources_legacy"}
+			opts.SkipDataSources = []string{"resources_matrix"}
+			opts.MaxItemsOne = map[string]string{"resources_gadget.dimensions": maxItemsOneSingleBlock}
+			description, sensitive := "Whether the widget is enabled", true
+			opts.AttributeOverrides = map[string]AttributeOverride{
+				"resources_widget.enabled":              {Description: &description},
+				"resources_widget.rule.port_range.from": {Sensitive: &sensitive},
+				"resources_gadget.model":                {Skip: true},
+			}
+			report, err := MigrateResources(opts)
+			if err != nil {
+				t.Fatalf("migrate-resources failed: %v", err)
+			}
+
+			t.Run("report", func(t *testing.T) {
+				if report.ProtocolVersion != protocol {
+					t.Errorf("expected protocol %d from main.go, got %d", protocol, report.ProtocolVersion)
+				}
+				notes := strings.Join(report.Notes, "\n")
+				for _, want := range []string{
+					"resources_widget.name: ForceNew (provider/resource_widget.go:",
+					"-> stringplanmodifier.RequiresReplace()",
+					"resources_widget.rule.cidr: ForceNew",
+					"resources_widget.rule.port_range: ForceNew (provider/resource_widget.go:",
+					"-> listplanmodifier.RequiresReplace()",
+					"resources_gadget.dimensions: ForceNew (provider/resource_gadget.go:",
+					"-> objectplanmodifier.RequiresReplace()",
+					"resources_widget.size: Default (provider/resource_widget.go:",
+					"-> int64default.StaticInt64(3) (framework defaults require Computed)",
+					"resources_widget.labels: Optional+Computed",
+					"resources_gadget.weight: Default (provider/resource_gadget.go:",
+					"-> float64default.StaticFloat64(1.5) (framework defaults require Computed)",
+					"resources_extra.zone: Default (provider/extra/extra.go:",
+					"not translated: Default is not a constant",
+					"resources_extra.zone: Description (provider/extra/extra.go:",
+					`-> not evaluated: "Zone in one of " + strings.Join(regions, ", ") is not a constant expression`,
+					"resources_extra.beta: schema map (provider/extra/extra.go:",
+					"-> non-deterministic schema: set or deleted inside an if, switch or loop",
+					"resources_widget.timeouts.create: Timeouts.Create (provider/resource_widget.go:",
+					"-> 10 * time.Minute",
+					"resources_widget.timeouts.default: Timeouts.Default",
+					"-> 5 * time.Minute for read, update, delete (framework timeouts have no default key)",
+					"resources_gadget.timeouts.create: Timeouts.Create",
+					"not translated: createTimeout is not a literal duration, defaulting to 20 * time.Minute",
+					"resources_widget.size: CustomizeDiff customdiff.ForceNewIfChange (provider/resource_widget.go:",
+					"-> int64planmodifier.RequiresReplaceIf(resourcesWidgetSizeRequiresReplaceIf)",
+					"resources_widget.labels: CustomizeDiff customdiff.ComputedIf",
+					"resources_widget.CustomizeDiff: resourceWidgetCustomizeDiff",
+					"resources_widget modify plan: 2 CustomizeDiff rules left as TODO(migrate)",
+					"resources_gadget.manifest: DiffSuppressFunc structure.SuppressJsonDiff (provider/resource_gadget.go:",
+					"resources_gadget.manifest: ValidateFunc validation.StringIsJSON",
+					"-> jsontypes.NormalizedType{}",
+					"resources_gadget.expires_at: ValidateDiagFunc validation.IsRFC3339Time",
+					"-> timetypes.RFC3339Type{}",
+					"resources_widget.rule.cidr: ValidateFunc validation.IsCIDR",
+					"-> resourcesWidgetResourceCIDRValidator{} (generated: validation.IsCIDR accepts IPv4 and IPv6 prefixes",
+					"resources_widget.gateway: ValidateFunc validation.IsIPv4Address",
+					"-> iptypes.IPv4AddressType{}",
+					"resources_gadget.ports: ConfigMode: SchemaConfigModeAttr (provider/resource_gadget.go:",
+					"resources_widget.status: Computed (provider/resource_widget.go:",
+					"resources_gadget.credentials: Sensitive (provider/resource_gadget.go:",
+					"-> Sensitive on every nested attribute (framework blocks cannot be sensitive)",
+					"resource resources_gadget: block dimensions (MaxItems: 1) is migrated as single-block, which changes its state from a list to an object; the schema version is raised to 1 with a state upgrader from version 0",
+				} {
+					if !strings.Contains(notes, want) {
+						t.Errorf("expected schema mapping %q in the report: %v", want, report.Notes)
+					}
+				}
+				if strings.Contains(notes, "resources_gadget.model:") {
+					t.Errorf("expected no schema mapping of the skipped attribute resources_gadget.model: %v", report.Notes)
+				}
+
+				var required []string
+				for _, change := range report.ModChanges {
+					required = append(required, change.Path)
+				}
+				sort.Strings(required)
+				if want := []string{jsonTypesModule, netTypesModule, timeoutsModule, timeTypesModule}; !reflect.DeepEqual(required, want) {
+					t.Errorf("expected the timeouts and custom type modules to be required, got %v", report.ModChanges)
+				}
+			})
+
+			layout, err := opts.Layout.resolve()
+			if err != nil {
+				t.Fatalf("resolve layout: %v", err)
+			}
+			// Split protocol 6 renders nested attributes where protocol 5
+			// falls back to object types.
+			status := `"status": schema.ListAttribute{Computed: true, ElementType: types.ObjectType{AttrTypes: map[string]attr.Type{"state": types.StringType, "events": types.ListType{ElemType: types.ObjectType{AttrTypes: map[string]attr.Type{"message": types.StringType}}}}}}`
+			ports := `"ports": schema.SetAttribute{Optional: true, ElementType: types.ObjectType{AttrTypes: map[string]attr.Type{"number": types.Int64Type, "protocol": types.StringType}}}`
+			helperFiles := []string{layout.providerFile(target)}
+			if split {
+				status = `"status": schema.ListNestedAttribute{Computed: true, NestedObject: schema.NestedAttributeObject{Attributes: map[string]schema.Attribute{"state": schema.StringAttribute{Computed: true}, "events": schema.ListNestedAttribute{Computed: true, NestedObject: schema.NestedAttributeObject{Attributes: map[string]schema.Attribute{"message": schema.StringAttribute{Computed: true}}}}}}}`
+				ports = `"ports": schema.SetNestedAttribute{Optional: true, NestedObject: schema.NestedAttributeObject{Attributes: map[string]schema.Attribute{"number": schema.Int64Attribute{Required: true}, "protocol": schema.StringAttribute{Optional: true}}}}`
+				helperFiles = []string{layout.resourcesFile(target), layout.dataSourcesFile(target)}
+			}
+
+			type fileCase struct {
+				name string
+				file string
+				// want and absent are matched against the file with
+				// whitespace runs collapsed to a single space.
+				want   []string
+				absent []string
+			}
+			cases := []fileCase{
+				{
+					name: "widget model and CRUD",
+					file: layout.resourceFile(target, "widget", false),
+					want: []string{
+						"type widgetResourceModel struct",
+						"Labels types.List `tfsdk:\"labels\"`",
+						"Rule []widgetResourceRuleModel `tfsdk:\"rule\"`",
+						"PortRange []widgetResourceRulePortRangeModel `tfsdk:\"port_range\"`",
+						"From types.Int64 `tfsdk:\"from\"`",
+						"name := plan.Name.ValueString()",
+						"size := int(plan.Size.ValueInt64())",
+						`resp.Diagnostics.AddError("Unable to create resources_widget", fmt.Sprintf("size of widget %s must not be zero", name))`,
+						"// TODO(migrate): uses the provider meta value // client := meta.(*Client)",
+						"// TODO(migrate): depends on client, which was not translated",
+						"name := strings.TrimSpace(plan.Name.ValueString()) plan.Name = types.StringValue(name)",
+						"if !plan.Enabled.ValueBool() {",
+					},
+				},
+				{
+					name: "widget schema",
+					file: layout.resourceFile(target, "widget", false),
+					want: []string{
+						`"id": schema.StringAttribute{Computed: true, PlanModifiers: []planmodifier.String{stringplanmodifier.UseStateForUnknown()}}`,
+						`"name": schema.StringAttribute{Description: "Widget name", Required: true, PlanModifiers: []planmodifier.String{stringplanmodifier.RequiresReplace()}}`,
+						`"labels": schema.ListAttribute{Optional: true, Computed: true, ElementType: types.StringType, PlanModifiers: []planmodifier.List{listplanmodifier.UseStateForUnknown()}}`,
+						`"enabled": schema.BoolAttribute{Description: "Whether the widget is enabled", Optional: true}`,
+						`"from": schema.Int64Attribute{Required: true, Sensitive: true}`,
+						`"port_range": schema.ListNestedBlock{PlanModifiers: []planmodifier.List{listplanmodifier.RequiresReplace()}, NestedObject: schema.NestedBlockObject{`,
+					},
+				},
+				{
+					name: "widget validators and custom types",
+					file: layout.resourceFile(target, "widget", false),
+					want: []string{
+						`"cidr": schema.StringAttribute{Required: true, Validators: []validator.String{resourcesWidgetResourceCIDRValidator{}}, PlanModifiers: []planmodifier.String{stringplanmodifier.RequiresReplace()}}`,
+						"CIDR types.String `tfsdk:\"cidr\"`",
+						"func (v resourcesWidgetResourceCIDRValidator) ValidateString(ctx context.Context, req validator.StringRequest, resp *validator.StringResponse) {",
+						"if _, _, err := net.ParseCIDR(req.ConfigValue.ValueString()); err != nil {",
+						`"gateway": schema.StringAttribute{Optional: true, CustomType: iptypes.IPv4AddressType{}}`,
+						"Gateway iptypes.IPv4Address `tfsdk:\"gateway\"`",
+					},
+				},
+				{
+					name: "widget timeouts",
+					file: layout.resourceFile(target, "widget", false),
+					want: []string{
+						`"timeouts": timeouts.Block(ctx, timeouts.Opts{Create: true, Read: true, Update: true, Delete: true})`,
+						"Timeouts timeouts.Value `tfsdk:\"timeouts\"`",
+						"createTimeout, diags := plan.Timeouts.Create(ctx, 10*time.Minute)",
+						"ctx, cancel := context.WithTimeout(ctx, createTimeout) defer cancel()",
+						"deleteTimeout, diags := state.Timeouts.Delete(ctx, 5*time.Minute)",
+					},
+				},
+				{
+					name: "widget import and state upgrade",
+					file: layout.resourceFile(target, "widget", false),
+					want: []string{
+						"var _ resource.ResourceWithImportState = (*widgetResource)(nil)",
+						`resource.ImportStatePassthroughID(ctx, path.Root("id"), req, resp)`,
+						"Version: 1,",
+						"var _ resource.ResourceWithUpgradeState = (*widgetResource)(nil)",
+						`0: { PriorSchema: &schema.Schema{ Attributes: map[string]schema.Attribute{ "id": schema.StringAttribute{Computed: true}, "name": schema.StringAttribute{Required: true}, "size": schema.Int64Attribute{Optional: true}, "tags": schema.StringAttribute{Optional: true}, },`,
+						"StateUpgrader: func(ctx context.Context, req resource.UpgradeStateRequest, resp *resource.UpgradeStateResponse) { // TODO(migrate): port the SDK state upgrader resourceWidgetStateUpgradeV0: //",
+						`// rawState["labels"] = strings.Split(tags, ",")`,
+					},
+				},
+				{
+					name: "widget CustomizeDiff",
+					file: layout.resourceFile(target, "widget", false),
+					want: []string{
+						`"size": schema.Int64Attribute{Optional: true, Computed: true, PlanModifiers: []planmodifier.Int64{int64planmodifier.RequiresReplaceIf(resourcesWidgetSizeRequiresReplaceIf, "Ported from the SDK customdiff.ForceNewIfChange.", "Ported from the SDK customdiff.ForceNewIfChange.")}, Default: int64default.StaticInt64(3)}`,
+						"var _ resource.ResourceWithModifyPlan = (*widgetResource)(nil)",
+						"if req.Plan.Raw.IsNull() {",
+						`// TODO(migrate): port the SDK condition customdiff.ComputedIf("labels"):`,
+						`if labelsComputed() { resp.Diagnostics.Append(resp.Plan.SetAttribute(ctx, path.Root("labels"), types.ListUnknown(types.StringType))...) }`,
+						"// TODO(migrate): port the SDK CustomizeDiff rule resourceWidgetCustomizeDiff:",
+						"func resourcesWidgetSizeRequiresReplaceIf(ctx context.Context, req planmodifier.Int64Request, resp *int64planmodifier.RequiresReplaceIfFuncResponse) {",
+						"resp.RequiresReplace = int(req.PlanValue.ValueInt64()) < int(req.StateValue.ValueInt64())",
+					},
+				},
+				{
+					name: "widget data source",
+					file: layout.resourceFile(target, "widget", true),
+					want: []string{
+						"data.Size = types.Int64Value(int64(len(name)))",
+						status,
+						`"id": schema.StringAttribute{Computed: true}`,
+						"readTimeout, diags := data.Timeouts.Read(ctx, 2*time.Minute)",
+					},
+				},
+				{
+					name: "features data source with unrolled loop",
+					file: layout.resourceFile(target, "features", true),
+					want: []string{
+						`"alpha": schema.BoolAttribute{Computed: true}`,
+						`"gamma": schema.BoolAttribute{Computed: true}`,
+					},
+				},
+				{
+					name: "counter ID access and importer",
+					file: layout.resourceFile(target, "counter", false),
+					want: []string{
+						"plan.ID = types.StringValue(strconv.Itoa(int(plan.Value.ValueInt64())))",
+						"value, err := strconv.Atoi(state.ID.ValueString())",
+						"// TODO(migrate): port the SDK importer resourceCounterImport:",
+						`// d.SetId(strings.TrimPrefix(d.Id(), "counter-"))`,
+					},
+				},
+				{
+					name: "gadget custom types and single block",
+					file: layout.resourceFile(target, "gadget", false),
+					want: []string{
+						`"manifest": schema.StringAttribute{Optional: true, Computed: true, CustomType: jsontypes.NormalizedType{}, PlanModifiers: []planmodifier.String{stringplanmodifier.UseStateForUnknown()}}`,
+						`"expires_at": schema.StringAttribute{Computed: true, CustomType: timetypes.RFC3339Type{}}`,
+						"Manifest jsontypes.Normalized `tfsdk:\"manifest\"`",
+						"ExpiresAt timetypes.RFC3339 `tfsdk:\"expires_at\"`",
+						`plan.Manifest = jsontypes.NewNormalizedValue("{}")`,
+						`// TODO(migrate): d.Set of timetypes.RFC3339 attribute "expires_at", whose constructor returns diagnostics`,
+						`"dimensions": schema.SingleNestedBlock{PlanModifiers: []planmodifier.Object{objectplanmodifier.RequiresReplace()}, Attributes: map[string]schema.Attribute{`,
+						"Ports []gadgetResourcePortsModel `tfsdk:\"ports\"`",
+						ports,
+						`"password": schema.StringAttribute{Required: true, Sensitive: true}`,
+						`"username": schema.StringAttribute{Required: true, Sensitive: true}`,
+						`"value": schema.StringAttribute{Optional: true, Sensitive: true}`,
+						`"limits": schema.MapAttribute{Optional: true, ElementType: types.ListType{ElemType: types.Int64Type}}`,
+						`"schedule": schema.ListAttribute{Optional: true, ElementType: types.MapType{ElemType: types.StringType}}`,
+						"Dimensions *gadgetResourceDimensionsModel `tfsdk:\"dimensions\"`",
+						"Version: 1,",
+						"type gadgetResourceModelV0 struct",
+						"Dimensions []gadgetResourceDimensionsModel `tfsdk:\"dimensions\"`",
+						`"dimensions": schema.ListNestedBlock{NestedObject: schema.NestedBlockObject{`,
+						"var prior gadgetResourceModelV0 resp.Diagnostics.Append(req.State.Get(ctx, &prior)...)",
+						"if len(prior.Dimensions) > 0 { state.Dimensions = &prior.Dimensions[0] }",
+					},
+					absent: []string{"ImportState", `"model"`},
+				},
+				{
+					name: "extra schema and typed provider data",
+					file: layout.resourceFile(target, "extra", false),
+					want: []string{
+						`schema.StringAttribute{Description: "Label of the extra, at most 64 characters", Required: true}`,
+						`schema.StringAttribute{Description: "One of eu, us", Optional: true}`,
+						`"zone": schema.StringAttribute{Optional: true}`,
+						`"tags": schema.MapAttribute{Optional: true, ElementType: types.StringType}`,
+						`"beta": schema.BoolAttribute{Optional: true}`,
+						"var _ resource.ResourceWithConfigure = (*extraResource)(nil)",
+						"meta *sdkprovider.Client",
+						"meta, ok := providerMeta(req.ProviderData, &resp.Diagnostics)",
+					},
+				},
+				{
+					name:   "SDK provider entries removed",
+					file:   filepath.Join(target, "provider", "provider.go"),
+					absent: []string{`"resources_widget"`, `resources[extra.ServicePrefix+"counter"]`},
+				},
+				{
+					name:   "entry merged with maps.Copy removed",
+					file:   filepath.Join(target, "provider", "extra", "extra.go"),
+					absent: []string{`ServicePrefix + "extra"`},
+				},
+				{
+					name: "framework provider meta accessor and meta schema",
+					file: layout.providerFile(target),
+					want: []string{
+						"response.ResourceData = p.meta",
+						"var _ provider.ProviderWithMetaSchema = (*fwprovider)(nil)",
+						`"labels": metaschema.MapAttribute{Optional: true, ElementType: types.StringType}`,
+						`"module_name": metaschema.StringAttribute{Description: "Name of the module using the provider", Optional: true}`,
+						`"team": metaschema.StringAttribute{Optional: true}`,
+					},
+				},
+				{
+					// the generated test runs with runGoTest below
+					name: "configure ordering test",
+					file: layout.providerTestFile(target),
+					want: []string{
+						"func TestConfigureBeforeSDK(t *testing.T) {",
+						"primary := sdkprovider.Provider()",
+						// the framework server is listed, and configured, first
+						fmt.Sprintf("tf%dmuxserver.NewMuxServer(ctx, providerserver.NewProtocol%d(p),", protocol, protocol),
+						"server := muxServer.ProviderServer()",
+						fmt.Sprintf("resp, err := server.ConfigureProvider(ctx, &tfprotov%d.ConfigureProviderRequest{Config: &config})", protocol),
+					},
+					absent: []string{"sync.WaitGroup", "go func()"},
+				},
+			}
+			for _, file := range helperFiles {
+				cases = append(cases, fileCase{
+					name: "provider data helper in " + filepath.Base(file),
+					file: file,
+					want: []string{
+						`sdkprovider "github.com/acme/terraform-provider-resources/provider"`,
+						"func providerMeta(data interface{}, diags *diag.Diagnostics) (meta *sdkprovider.Client, ok bool) {",
+						`fmt.Sprintf("Expected *sdkprovider.Client, got: %T. Please report this issue to the provider developers.", data)`,
+						"if source, isSource := data.(interface{ Meta() interface{} }); isSource {",
+					},
+				})
+			}
+			for _, tt := range cases {
+				tt := tt
+				t.Run(tt.name, func(t *testing.T) {
+					data, err := os.ReadFile(tt.file)
+					if err != nil {
+						t.Fatalf("read generated file: %v", err)
+					}
+					fields := strings.Join(strings.Fields(string(data)), " ")
+					for _, want := range tt.want {
+						if !strings.Contains(fields, want) {
+							t.Errorf("expected %q in %s:\n%s", want, tt.file, data)
+						}
+					}
+					for _, absent := range tt.absent {
+						if strings.Contains(fields, absent) {
+							t.Errorf("expected no %q in %s:\n%s", absent, tt.file, data)
+						}
+					}
+				})
+			}
+
+			runGoTest(t, target)
+		})
+	}
+}
+
+func TestMigrateIndependentConfigure(t *testing.T) {
+	t.Parallel()
+
+	target := prepareFixture(t, "resources")
+	opts := Options{Path: target, Configure: configureIndependent}
+	report, err := Migrate(opts)
+	if err != nil {
+		t.Fatalf("migrate failed: %v", err)
+	}
+	if !strings.Contains(strings.Join(report.Notes, "\n"), "provider configure: 1 statements of ConfigureContextFunc left as TODO(migrate)") {
+		t.Errorf("expected the untranslated logConfigure call to be reported, got %v", report.Notes)
+	}
+
+	layout := Layout{Dir: defaultLayoutDir, Package: defaultLayoutDir}
+	generated, err := os.ReadFile(layout.providerFile(target))
+	if err != nil {
+		t.Fatalf("read framework provider: %v", err)
+	}
+	for _, want := range []string{
+		"Endpoint types.String `tfsdk:\"endpoint\"`",
+		"resp.Diagnostics.Append(req.Config.Get(ctx, &config)...)",
+		"endpoint, ok := config.Endpoint.ValueString(), true",
+		`resp.Diagnostics.AddError("Unable to configure resources", "endpoint is not a string")`,
+		"endpoint = sdkprovider.DefaultEndpoint",
+		"// TODO(migrate): uses logConfigure, which is unexported in package github.com/acme/terraform-provider-resources/provider\n\t// logConfigure(d)",
+		"client := sdkprovider.NewClient(endpoint)",
+		"resp.DataSourceData = client\n\tresp.ResourceData = resp.DataSourceData",
+	} {
+		if !strings.Contains(string(generated), want) {
+			t.Errorf("expected %s in the independent Configure:\n%s", want, generated)
+		}
+	}
+	if strings.Contains(string(generated), "sdkMeta") {
+		t.Errorf("expected no SDK meta accessor with --configure independent:\n%s", generated)
+	}
+	if fileExists(layout.providerTestFile(target)) {
+		t.Errorf("expected no shared configure test with --configure independent")
+	}
+
+	runGoTest(t, target)
+}
+
+func TestResolveOptionsPrecedence(t *testing.T) {
+	t.Parallel()
+
+	dir := t.TempDir()
+	config := "provider_name: from-config\nregistry_address: registry.terraform.io/config/config\nno_upgrade: true\nlayout:\n  dir: from/config\n"
+	if err := os.WriteFile(filepath.Join(dir, configFileName), []byte(config), 0o644); err != nil {
+		t.Fatalf("write config: %v", err)
+	}
+
+	env := map[string]string{
+		"TF_PROVIDER_MIGRATE_PROVIDER_NAME":    "from-env",
+		"TF_PROVIDER_MIGRATE_REGISTRY_ADDRESS": "registry.terraform.io/env/env",
+	}
+	lookupEnv := func(key string) (string, bool) {
+		val, ok := env[key]
+		return val, ok
+	}
+
+	opts, _, err := resolveOptions(Options{ProviderName: "from-flag"}, dir, 0, lookupEnv)
+	if err != nil {
+		t.Fatalf("resolve options: %v", err)
+	}
+	if opts.ProviderName != "from-flag" {
+		t.Errorf("expected flag to win, got %q", opts.ProviderName)
+	}
+	if opts.RegistryAddress != "registry.terraform.io/env/env" {
+		t.Errorf("expected env to win over config, got %q", opts.RegistryAddress)
+	}
+	if opts.Layout.Dir != "from/config" {
+		t.Errorf("expected config layout dir, got %q", opts.Layout.Dir)
+	}
+	if !opts.NoUpgrade {
+		t.Errorf("expected no_upgrade from config")
+	}
+	if opts.ProtocolVersion != 5 {
+		t.Errorf("expected default protocol 5, got %d", opts.ProtocolVersion)
+	}
+	if opts.Timeouts != timeoutsBlock {
+		t.Errorf("expected default timeouts %q, got %q", timeoutsBlock, opts.Timeouts)
+	}
+	if _, _, err := resolveOptions(Options{Timeouts: timeoutsAttributes}, dir, 0, lookupEnv); err == nil {
+		t.Errorf("expected timeouts attributes to require protocol version 6")
+	}
+	if _, _, err := resolveOptions(Options{Timeouts: timeoutsAttributes, ProtocolVersion: 6}, dir, 0, lookupEnv); err != nil {
+		t.Errorf("expected timeouts attributes with protocol version 6: %v", err)
+	}
+
+	if err := os.WriteFile(filepath.Join(dir, configFileName), []byte("provider_nmae: typo\n"), 0o644); err != nil {
+		t.Fatalf("write config: %v", err)
+	}
+	if _, _, err := resolveOptions(Options{}, dir, 0, lookupEnv); err == nil {
+		t.Fatalf("expected unknown config key to be rejected")
+	}
+}
+
+func TestPlanRequiresUpgradesOldVersions(t *testing.T) {
+	t.Parallel()
+
+	data := []byte(`module github.com/acme/terraform-provider-pinned
+
+go 1.22.0
+
+require (
+	github.com/hashicorp/terraform-plugin-go v0.10.0
+	github.com/hashicorp/terraform-plugin-mux v0.9.0
+	github.com/hashicorp/terraform-plugin-sdk/v2 v2.0.0
+)
+`)
+	file, err := modfile.Parse("go.mod", data, nil)
+	if err != nil {
+		t.Fatalf("parse go.mod: %v", err)
+	}
+
+	changes, err := planRequires(file, baseModules, Dependencies{}, false)
+	if err != nil {
+		t.Fatalf("plan requires: %v", err)
+	}
+
+	want := []ModChange{
+		{Path: frameworkModule, To: legacyFrameworkVersion},
+		{Path: pluginGoModule, From: "v0.10.0", To: legacyPluginGoVersion},
+	}
+	if len(changes) != len(want) {
+		t.Fatalf("expected %d changes, got %v", len(want), changes)
+	}
+	for i := range want {
+		if changes[i] != want[i] {
+			t.Errorf("change %d: expected %v, got %v", i, want[i], changes[i])
+		}
+	}
+
+	if _, err := planRequires(file, baseModules, Dependencies{}, true); err == nil {
+		t.Fatalf("expected --no-upgrade to reject %s v0.10.0", pluginGoModule)
+	}
+}
+
 func prepareFixture(t *testing.T, name string) string {
 	t.Helper()
 
@@ -53,9 +808,19 @@ func prepareFixture(t *testing.T, name string) string {
 		frameworkModule: filepath.Join(root, "internal", "stubs", "terraform-plugin-framework"),
 		muxModule:       filepath.Join(root, "internal", "stubs", "terraform-plugin-mux"),
 		pluginGoModule:  filepath.Join(root, "internal", "stubs", "terraform-plugin-go"),
+		timeoutsModule:  filepath.Join(root, "internal", "stubs", "terraform-plugin-framework-timeouts"),
+		jsonTypesModule: filepath.Join(root, "internal", "stubs", "terraform-plugin-framework-jsontypes"),
+		timeTypesModule: filepath.Join(root, "internal", "stubs", "terraform-plugin-framework-timetypes"),
+		netTypesModule:  filepath.Join(root, "internal", "stubs", "terraform-plugin-framework-nettypes"),
 		"github.com/hashicorp/terraform-plugin-sdk/v2": filepath.Join(root, "internal", "stubs", "terraform-plugin-sdk-v2"),
 	}
-	if err := addReplaceDirectives(filepath.Join(dst, "go.mod"), stubs); err != nil {
+	err = filepath.WalkDir(dst, func(path string, d os.DirEntry, err error) error {
+		if err != nil || d.IsDir() || d.Name() != "go.mod" {
+			return err
+		}
+		return addReplaceDirectives(path, stubs)
+	})
+	if err != nil {
 		t.Fatalf("add replaces: %v", err)
 	}
 	return dst
@@ -66,6 +831,8 @@ func runGoTest(t *testing.T, dir string) {
 
 	cmd := exec.Command("go", "test", "./...")
 	cmd.Dir = dir
+	// -mod flags from the environment are rejected in workspace mode
+	cmd.Env = append(os.Environ(), "GOFLAGS=")
 	cmd.Stdout = os.Stdout
 	cmd.Stderr = os.Stderr
 	if err := cmd.Run(); err != nil {
@@ -103,6 +870,9 @@ func addReplaceDirectives(modPath string, replaces map[string]string) error {
 	if err != nil {
 		return err
 	}
+	if requireVersion(file, pluginSDKModule) == "" {
+		return nil
+	}
 	changed := false
 	for mod, local := range replaces {
 		if err := file.AddReplace(mod, "", local, ""); err == nil {
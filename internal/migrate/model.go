@@ -0,0 +1,72 @@
+package migrate
+
+import "strings"
+
+// ModelStruct is a Go struct generated for a resource, data source or nested
+// block, with one tfsdk-tagged field per attribute and block.
+type ModelStruct struct {
+	Name   string
+	Fields []ModelField
+}
+
+type ModelField struct {
+	Name string
+	Type string
+	Tag  string
+}
+
+// buildModels returns the model struct called name for the given schema,
+// followed by the structs of its nested blocks.
+func buildModels(name string, attrs []Attribute, blocks []Block) []ModelStruct {
+	model := ModelStruct{Name: name}
+	var nested []ModelStruct
+
+	for _, attr := range sortedAttributes(attrs) {
+		model.Fields = append(model.Fields, ModelField{
+			Name: goName(attr.Name),
+			Type: modelFieldType(attr),
+			Tag:  attr.Name,
+		})
+	}
+
+	for _, block := range sortedBlocks(blocks) {
+		blockModel := strings.TrimSuffix(name, "Model") + goName(block.Name) + "Model"
+		fieldType := "[]" + blockModel
+		if block.Strategy == maxItemsOneSingleBlock || block.Strategy == maxItemsOneSingleAttribute {
+			// nil when the block is absent
+			fieldType = "*" + blockModel
+		}
+		model.Fields = append(model.Fields, ModelField{
+			Name: goName(block.Name),
+			Type: fieldType,
+			Tag:  block.Name,
+		})
+		nested = append(nested, buildModels(blockModel, block.Attributes, block.Blocks)...)
+	}
+
+	return append([]ModelStruct{model}, nested...)
+}
+
+func modelFieldType(attr Attribute) string {
+	if attr.CustomType != "" {
+		return customTypeValue(attr.CustomType)
+	}
+	switch attr.Type {
+	case "string":
+		return "types.String"
+	case "bool":
+		return "types.Bool"
+	case "int":
+		return "types.Int64"
+	case "float":
+		return "types.Float64"
+	case "list":
+		return "types.List"
+	case "set":
+		return "types.Set"
+	case "map":
+		return "types.Map"
+	default:
+		return "types.String"
+	}
+}
@@ -26,8 +26,6 @@ func deriveNames(opts Options, moduleRoot string, strict bool) (string, string,
 				return "", "", nil, fmt.Errorf("unable to derive provider name, supply --provider-name")
 			}
 			notes = append(notes, "provider name not derived; supply --provider-name for migration")
-		} else {
-			notes = append(notes, "provider name derived from module path")
 		}
 	}
 
@@ -39,8 +37,6 @@ func deriveNames(opts Options, moduleRoot string, strict bool) (string, string,
 				return "", "", nil, fmt.Errorf("unable to derive registry address, supply --registry-address")
 			}
 			notes = append(notes, "registry address not derived; supply --registry-address for migration")
-		} else {
-			notes = append(notes, "registry address derived from module path")
 		}
 	}
 
@@ -5,28 +5,99 @@ import (
 	"go/ast"
 	"go/parser"
 	"go/token"
+	"go/types"
 	"io/fs"
 	"path/filepath"
-	"strconv"
 	"strings"
 )
 
 type ProviderInfo struct {
-	Attributes []Attribute
-	Blocks     []Block
+	Attributes  []Attribute
+	Blocks      []Block
+	Resources   []ResourceRef
+	DataSources []ResourceRef
+	// Unfollowed lists the code building ResourcesMap or DataSourcesMap
+	// that the parser could not follow, so its entries are missing, and a
+	// ConfigureContextFunc whose meta type could not be determined.
+	Unfollowed []SchemaMapping
+	// MetaType is the type of the meta value the SDK provider configures,
+	// nil when it is unknown.
+	MetaType *MetaType
+	// MetaAttributes and MetaBlocks are the ProviderMetaSchema, the schema of
+	// provider_meta blocks in modules using the provider.
+	MetaAttributes []Attribute
+	MetaBlocks     []Block
+
+	// configure is the ConfigureContextFunc or ConfigureFunc the framework
+	// Configure is ported from with --configure independent.
+	configure crudFunc
+	res       resolver
+}
+
+// ResourceRef is an entry of the SDK ResourcesMap or DataSourcesMap. The
+// resource itself is only parsed when it is migrated.
+type ResourceRef struct {
+	Name string
+
+	// value is the resource expression; node is the map literal entry or
+	// index assignment removed once the resource is migrated.
+	value ast.Expr
+	node  ast.Node
 }
 
 type Attribute struct {
 	Name        string
 	Type        string
-	ElemType    string
+	ElemType    *ElemType
 	MinItems    *int
 	MaxItems    *int
 	Optional    bool
 	Required    bool
 	Computed    bool
 	Sensitive   bool
+	ForceNew    bool
 	Description string
+	// DescriptionExpr is the source of a Description that is not a constant
+	// expression, which the framework schema is generated without.
+	DescriptionExpr string
+	// Conditional is where the SDK function building the schema map sets or
+	// deletes the attribute inside an if, switch or loop.
+	Conditional token.Position
+	// Default is the Go literal of the SDK Default, DefaultExpr the framework
+	// default it is translated to for resources, e.g.
+	// stringdefault.StaticString("x").
+	Default     string
+	DefaultExpr string
+	// Fields records where each schema.Schema field was set in the SDK
+	// source, keyed by field name.
+	Fields map[string]token.Position
+	// PlanModifiers are Go expressions of framework plan modifiers, e.g.
+	// stringplanmodifier.UseStateForUnknown(). They are only rendered for
+	// resources.
+	PlanModifiers []string
+	// Literal replaces the rendered framework attribute when set.
+	Literal string
+	// ValidateFunc and DiffSuppressFunc name the SDK functions of the
+	// attribute, qualified by import path when they are package functions,
+	// e.g. github.com/hashicorp/terraform-plugin-sdk/v2/helper/validation.IsCIDR.
+	// ValidateFunc also holds the function wrapped by a ValidateDiagFunc.
+	ValidateFunc     string
+	DiffSuppressFunc string
+	// CustomType is the framework custom type of the attribute, e.g.
+	// jsontypes.NormalizedType{}, recognised from ValidateFunc or
+	// DiffSuppressFunc.
+	CustomType string
+	// Validators are Go expressions of framework validators, e.g. the
+	// generated validator of validation.IsCIDR.
+	Validators []string
+}
+
+// ElemType is the element type of a list, set or map attribute. Elem is
+// the element type of a nested list, set or map, e.g. the strings of a map
+// of lists of strings.
+type ElemType struct {
+	Type string
+	Elem *ElemType
 }
 
 type MainInfo struct {
@@ -34,18 +105,73 @@ type MainInfo struct {
 	ProviderAlias  string
 	BuildTags      []string
 	GoGenerate     []string
+	// ProtocolVersion is the protocol served by a main.go that migrate
+	// already muxed, 0 for the SDK main.
+	ProtocolVersion int
 }
 
 type Block struct {
-	Name        string
-	Kind        string
-	Description string
+	Name            string
+	Kind            string
+	Description     string
+	DescriptionExpr string
+	// Conditional is where the SDK function building the schema map sets or
+	// deletes the block inside an if, switch or loop.
+	Conditional token.Position
+	MinItems    int
+	MaxItems    int
 	Attributes  []Attribute
+	Blocks      []Block
+	// Strategy is how a list block with MaxItems: 1 is rendered:
+	// maxItemsOneList (or empty), maxItemsOneSingleBlock or
+	// maxItemsOneSingleAttribute.
+	Strategy string
+	// ConfigMode is the SDK ConfigMode, "attr", "block" or empty for
+	// SchemaConfigModeAuto. Optional, Required and Computed are the flags of
+	// the SDK schema, which only matter for blocks used as attributes.
+	ConfigMode string
+	Optional   bool
+	Required   bool
+	Computed   bool
+	// Sensitive is the SDK flag of the whole block. Framework blocks cannot
+	// be sensitive, so the parser marks every attribute inside it instead.
+	Sensitive bool
+	ForceNew  bool
+	// PlanModifiers are Go expressions of framework plan modifiers of the
+	// list, set or single object, e.g. listplanmodifier.RequiresReplace().
+	PlanModifiers []string
+	// AttributeMode is set when the block is rendered as an attribute:
+	// attributeModeNested or attributeModeObject.
+	AttributeMode string
+	// Fields records where each schema.Schema field was set in the SDK
+	// source, keyed by field name.
+	Fields map[string]token.Position
 }
 
 type resolver struct {
+	fset    *token.FileSet
 	varMaps map[string]*ast.CompositeLit
-	funcs   map[string]*ast.FuncDecl
+	// varSlices holds the package level variables initialized with a slice
+	// or array literal, like varMaps for map literals.
+	varSlices map[string]*ast.CompositeLit
+	funcs     map[string]*ast.FuncDecl
+	// files maps file names to their syntax tree, decls holds every
+	// package level identifier declared in the module.
+	files map[string]*ast.File
+	decls map[string]bool
+	// pkgFuncs holds the package level functions by import path and name,
+	// e.g. github.com/example/terraform-provider-example/internal/svc.Resources.
+	pkgFuncs map[string]*ast.FuncDecl
+	// consts and pkgConsts hold the declared value of each package level
+	// constant, by name and by import path and name like pkgFuncs.
+	// typeNames holds the types declared in the module.
+	consts    map[string]ast.Expr
+	pkgConsts map[string]ast.Expr
+	typeNames map[string]bool
+	// packages maps file names to the import path of their package.
+	packages map[string]string
+	// checker holds the types of the module's expressions.
+	checker *typeChecker
 }
 
 func findProviderInfo(moduleRoot string) (ProviderInfo, error) {
@@ -66,7 +192,10 @@ func findProviderInfo(moduleRoot string) (ProviderInfo, error) {
 		paths = append(paths, file)
 	}
 
-	res := buildResolver(parsed)
+	res := buildResolver(fset, parsed)
+	if modulePath, err := modulePathFromGoMod(filepath.Join(moduleRoot, "go.mod")); err == nil {
+		res.indexPackageDecls(moduleRoot, modulePath)
+	}
 	for i, node := range parsed {
 		for _, decl := range node.Decls {
 			fn, ok := decl.(*ast.FuncDecl)
@@ -83,6 +212,7 @@ func findProviderInfo(moduleRoot string) (ProviderInfo, error) {
 				return ProviderInfo{}, fmt.Errorf("%s: %w", paths[i], err)
 			}
 			if ok {
+				info.res = res
 				return info, nil
 			}
 		}
@@ -116,11 +246,11 @@ func parseProviderFunction(fn *ast.FuncDecl, res resolver) (ProviderInfo, bool,
 	})
 
 	if providerLit != nil {
-		attrs, blocks, err := parseProviderComposite(providerLit, res)
+		info, err := parseProviderComposite(providerLit, fn.Body, res)
 		if err != nil {
 			return ProviderInfo{}, false, err
 		}
-		return ProviderInfo{Attributes: attrs, Blocks: blocks}, true, nil
+		return info, true, nil
 	}
 
 	for _, stmt := range fn.Body.List {
@@ -132,21 +262,21 @@ func parseProviderFunction(fn *ast.FuncDecl, res resolver) (ProviderInfo, bool,
 		comp, ok := ret.Results[0].(*ast.UnaryExpr)
 		if ok && comp.Op == token.AND {
 			if lit, ok := comp.X.(*ast.CompositeLit); ok {
-				attrs, blocks, err := parseProviderComposite(lit, res)
+				info, err := parseProviderComposite(lit, fn.Body, res)
 				if err != nil {
 					return ProviderInfo{}, false, err
 				}
-				return ProviderInfo{Attributes: attrs, Blocks: blocks}, true, nil
+				return info, true, nil
 			}
 		}
 
 		if ident, ok := ret.Results[0].(*ast.Ident); ok {
-			if lit := findLocalProviderLiteral(fn, ident.Name); lit != nil {
-				attrs, blocks, err := parseProviderComposite(lit, res)
+			if lit := findLocalCompositeLiteral(fn, ident.Name); lit != nil {
+				info, err := parseProviderComposite(lit, fn.Body, res)
 				if err != nil {
 					return ProviderInfo{}, false, err
 				}
-				return ProviderInfo{Attributes: attrs, Blocks: blocks}, true, nil
+				return info, true, nil
 			}
 		}
 	}
@@ -154,11 +284,14 @@ func parseProviderFunction(fn *ast.FuncDecl, res resolver) (ProviderInfo, bool,
 	return ProviderInfo{}, false, nil
 }
 
-func parseProviderComposite(lit *ast.CompositeLit, res resolver) ([]Attribute, []Block, error) {
+// parseProviderComposite reads a schema.Provider literal. scope is the body
+// of the function declaring it, in which local registry maps are followed.
+func parseProviderComposite(lit *ast.CompositeLit, scope *ast.BlockStmt, res resolver) (ProviderInfo, error) {
 	if !isSchemaProviderType(lit.Type) {
-		return nil, nil, fmt.Errorf("return value is not schema.Provider literal")
+		return ProviderInfo{}, fmt.Errorf("return value is not schema.Provider literal")
 	}
 
+	var info ProviderInfo
 	for _, elt := range lit.Elts {
 		kv, ok := elt.(*ast.KeyValueExpr)
 		if !ok {
@@ -166,18 +299,54 @@ func parseProviderComposite(lit *ast.CompositeLit, res resolver) ([]Attribute, [
 		}
 
 		key, ok := kv.Key.(*ast.Ident)
-		if !ok || key.Name != "Schema" {
+		if !ok {
 			continue
 		}
 
-		attrs, blocks, err := parseSchemaMapExpr(kv.Value, res)
-		if err != nil {
-			return nil, nil, err
+		switch key.Name {
+		case "Schema", "SchemaFunc":
+			parse := parseSchemaMapExpr
+			if key.Name == "SchemaFunc" {
+				parse = parseSchemaFunc
+			}
+			attrs, blocks, err := parse(kv.Value, res)
+			if err != nil {
+				return ProviderInfo{}, err
+			}
+			info.Attributes = attrs
+			info.Blocks = blocks
+		case "ProviderMetaSchema":
+			attrs, blocks, err := parseSchemaMapExpr(kv.Value, res)
+			if err != nil {
+				return ProviderInfo{}, fmt.Errorf("ProviderMetaSchema: %w", err)
+			}
+			info.MetaAttributes = attrs
+			info.MetaBlocks = blocks
+		case "ResourcesMap", "DataSourcesMap":
+			refs, unfollowed := parseRegistry(key.Name, kv.Value, scope, res)
+			if key.Name == "ResourcesMap" {
+				info.Resources = refs
+			} else {
+				info.DataSources = refs
+			}
+			info.Unfollowed = append(info.Unfollowed, unfollowed...)
+		case "ConfigureContextFunc", "ConfigureFunc":
+			info.configure = resolveFunc(key.Name, kv.Value, res)
+			meta, err := parseConfigureFunc(kv.Value, res)
+			if err != nil {
+				info.Unfollowed = append(info.Unfollowed, SchemaMapping{
+					Path:     key.Name,
+					Field:    types.ExprString(kv.Value),
+					Position: res.fset.Position(kv.Value.Pos()),
+					Result:   "meta type not found: " + err.Error() + "; framework resources get no typed provider data",
+				})
+				continue
+			}
+			info.MetaType = meta
 		}
-		return attrs, blocks, nil
 	}
 
-	return nil, nil, nil
+	return info, nil
 }
 
 func parseSchemaMapExpr(expr ast.Expr, res resolver) ([]Attribute, []Block, error) {
@@ -216,9 +385,9 @@ func parseSchemaMap(lit *ast.CompositeLit, res resolver) ([]Attribute, []Block,
 			continue
 		}
 
-		name, ok := parseStringLiteral(kv.Key)
+		name, ok := res.evalString(kv.Key)
 		if !ok {
-			return nil, nil, fmt.Errorf("schema attribute name must be string literal")
+			return nil, nil, fmt.Errorf("schema attribute name %s is not a constant string", types.ExprString(kv.Key))
 		}
 
 		attr, block, err := parseSchemaAttribute(name, kv.Value, res)
@@ -255,8 +424,9 @@ func parseSchemaComposite(name string, lit *ast.CompositeLit, res resolver) (Att
 		return Attribute{}, nil, fmt.Errorf("schema attribute %q is not schema.Schema", name)
 	}
 
-	attr := Attribute{Name: name}
+	attr := Attribute{Name: name, Fields: map[string]token.Position{}}
 	var elemInfo elemInfo
+	configMode := ""
 
 	for _, elt := range lit.Elts {
 		kv, ok := elt.(*ast.KeyValueExpr)
@@ -268,6 +438,7 @@ func parseSchemaComposite(name string, lit *ast.CompositeLit, res resolver) (Att
 		if !ok {
 			continue
 		}
+		attr.Fields[key.Name] = res.fset.Position(kv.Pos())
 
 		switch key.Name {
 		case "Type":
@@ -277,32 +448,42 @@ func parseSchemaComposite(name string, lit *ast.CompositeLit, res resolver) (Att
 			}
 			attr.Type = typ
 		case "Optional":
-			val, ok := parseBoolLiteral(kv.Value)
+			val, ok := res.evalBool(kv.Value)
 			if !ok {
-				return Attribute{}, nil, fmt.Errorf("schema attribute %q Optional must be bool literal", name)
+				return Attribute{}, nil, fmt.Errorf("schema attribute %q Optional must be a constant bool", name)
 			}
 			attr.Optional = val
 		case "Required":
-			val, ok := parseBoolLiteral(kv.Value)
+			val, ok := res.evalBool(kv.Value)
 			if !ok {
-				return Attribute{}, nil, fmt.Errorf("schema attribute %q Required must be bool literal", name)
+				return Attribute{}, nil, fmt.Errorf("schema attribute %q Required must be a constant bool", name)
 			}
 			attr.Required = val
 		case "Computed":
-			val, ok := parseBoolLiteral(kv.Value)
+			val, ok := res.evalBool(kv.Value)
 			if !ok {
-				return Attribute{}, nil, fmt.Errorf("schema attribute %q Computed must be bool literal", name)
+				return Attribute{}, nil, fmt.Errorf("schema attribute %q Computed must be a constant bool", name)
 			}
 			attr.Computed = val
 		case "Sensitive":
-			val, ok := parseBoolLiteral(kv.Value)
+			val, ok := res.evalBool(kv.Value)
 			if !ok {
-				return Attribute{}, nil, fmt.Errorf("schema attribute %q Sensitive must be bool literal", name)
+				return Attribute{}, nil, fmt.Errorf("schema attribute %q Sensitive must be a constant bool", name)
 			}
 			attr.Sensitive = val
+		case "ForceNew":
+			val, ok := res.evalBool(kv.Value)
+			if !ok {
+				return Attribute{}, nil, fmt.Errorf("schema attribute %q ForceNew must be a constant bool", name)
+			}
+			attr.ForceNew = val
+		case "Default":
+			attr.Default = res.defaultLiteral(kv.Value)
 		case "Description":
-			if val, ok := parseStringExpr(kv.Value); ok {
+			if val, ok := res.evalString(kv.Value); ok {
 				attr.Description = val
+			} else {
+				attr.DescriptionExpr = types.ExprString(kv.Value)
 			}
 		case "Elem":
 			info, err := parseElem(kv.Value, res)
@@ -310,12 +491,22 @@ func parseSchemaComposite(name string, lit *ast.CompositeLit, res resolver) (Att
 				return Attribute{}, nil, fmt.Errorf("schema attribute %q: %w", name, err)
 			}
 			elemInfo = info
+		case "ValidateFunc", "ValidateDiagFunc":
+			attr.ValidateFunc = qualifiedFunc(unwrapDiagFunc(kv.Value), res)
+		case "DiffSuppressFunc":
+			attr.DiffSuppressFunc = qualifiedFunc(kv.Value, res)
+		case "ConfigMode":
+			mode, err := parseConfigMode(kv.Value)
+			if err != nil {
+				return Attribute{}, nil, fmt.Errorf("schema attribute %q: %w", name, err)
+			}
+			configMode = mode
 		case "MinItems":
-			if val, ok := parseIntLiteral(kv.Value); ok {
+			if val, ok := res.evalInt(kv.Value); ok {
 				attr.MinItems = &val
 			}
 		case "MaxItems":
-			if val, ok := parseIntLiteral(kv.Value); ok {
+			if val, ok := res.evalInt(kv.Value); ok {
 				attr.MaxItems = &val
 			}
 		}
@@ -330,30 +521,63 @@ func parseSchemaComposite(name string, lit *ast.CompositeLit, res resolver) (Att
 			return Attribute{}, nil, fmt.Errorf("schema attribute %q has resource Elem but type is %s", name, attr.Type)
 		}
 
-		if len(elemInfo.blocks) > 0 {
-			return Attribute{}, nil, fmt.Errorf("schema attribute %q has nested blocks inside Elem resource (unsupported)", name)
-		}
-
 		block := Block{
-			Name:        name,
-			Kind:        attr.Type,
-			Description: attr.Description,
-			Attributes:  elemInfo.attrs,
+			Name:            name,
+			Kind:            attr.Type,
+			Description:     attr.Description,
+			DescriptionExpr: attr.DescriptionExpr,
+			Attributes:      elemInfo.attrs,
+			Blocks:          elemInfo.blocks,
+			ConfigMode:      configMode,
+			Optional:        attr.Optional,
+			Required:        attr.Required,
+			Computed:        attr.Computed,
+			Sensitive:       attr.Sensitive,
+			ForceNew:        attr.ForceNew,
+			Fields:          attr.Fields,
+		}
+		if block.computedOnly() {
+			block.Attributes, block.Blocks = computedSchema(block.Attributes, block.Blocks)
+		}
+		if block.Sensitive {
+			block.Attributes, block.Blocks = sensitiveSchema(block.Attributes, block.Blocks)
+		}
+		if attr.MinItems != nil {
+			block.MinItems = *attr.MinItems
+		}
+		if attr.MaxItems != nil {
+			block.MaxItems = *attr.MaxItems
 		}
 		return Attribute{}, &block, nil
 	}
 
-	if elemInfo.elemType != "" {
-		attr.ElemType = elemInfo.elemType
-	}
+	attr.ElemType = elemInfo.elemType
 
-	if (attr.Type == "list" || attr.Type == "set" || attr.Type == "map") && attr.ElemType == "" {
+	if (attr.Type == "list" || attr.Type == "set" || attr.Type == "map") && attr.ElemType == nil {
 		return Attribute{}, nil, fmt.Errorf("schema attribute %q missing Elem type", name)
 	}
 
 	return attr, nil, nil
 }
 
+// parseConfigMode reads a schema.SchemaConfigMode constant.
+func parseConfigMode(expr ast.Expr) (string, error) {
+	name := types.ExprString(expr)
+	if sel, ok := expr.(*ast.SelectorExpr); ok {
+		name = sel.Sel.Name
+	}
+	switch name {
+	case "SchemaConfigModeAuto":
+		return "", nil
+	case "SchemaConfigModeAttr":
+		return configModeAttr, nil
+	case "SchemaConfigModeBlock":
+		return configModeBlock, nil
+	default:
+		return "", fmt.Errorf("unsupported ConfigMode %s", types.ExprString(expr))
+	}
+}
+
 func parseSchemaType(expr ast.Expr) (string, error) {
 	switch v := expr.(type) {
 	case *ast.SelectorExpr:
@@ -389,7 +613,7 @@ func normalizeSchemaType(name string) (string, error) {
 }
 
 type elemInfo struct {
-	elemType   string
+	elemType   *ElemType
 	attrs      []Attribute
 	blocks     []Block
 	isResource bool
@@ -412,23 +636,11 @@ func parseElem(expr ast.Expr, res resolver) (elemInfo, error) {
 
 func parseElemFromComposite(lit *ast.CompositeLit, res resolver) (elemInfo, error) {
 	if lit.Type == nil || isSchemaSchemaType(lit.Type) {
-		for _, elt := range lit.Elts {
-			kv, ok := elt.(*ast.KeyValueExpr)
-			if !ok {
-				continue
-			}
-			key, ok := kv.Key.(*ast.Ident)
-			if !ok || key.Name != "Type" {
-				continue
-			}
-
-			elemType, err := parseSchemaType(kv.Value)
-			if err != nil {
-				return elemInfo{}, err
-			}
-			return elemInfo{elemType: elemType}, nil
+		elemType, err := parseElemType(lit, res)
+		if err != nil {
+			return elemInfo{}, err
 		}
-		return elemInfo{}, fmt.Errorf("Elem schema missing Type")
+		return elemInfo{elemType: elemType}, nil
 	}
 
 	if isSchemaResourceType(lit.Type) {
@@ -442,6 +654,53 @@ func parseElemFromComposite(lit *ast.CompositeLit, res resolver) (elemInfo, erro
 	return elemInfo{}, fmt.Errorf("Elem must be schema.Schema or schema.Resource literal")
 }
 
+// parseElemType reads the element type of a collection from its Elem
+// schema. Elements that are collections must declare their own Elem; a
+// schema.Resource element of an element has no framework type.
+func parseElemType(lit *ast.CompositeLit, res resolver) (*ElemType, error) {
+	var elemType *ElemType
+	var elem ast.Expr
+	for _, elt := range lit.Elts {
+		kv, ok := elt.(*ast.KeyValueExpr)
+		if !ok {
+			continue
+		}
+		key, ok := kv.Key.(*ast.Ident)
+		if !ok {
+			continue
+		}
+		switch key.Name {
+		case "Type":
+			typ, err := parseSchemaType(kv.Value)
+			if err != nil {
+				return nil, err
+			}
+			elemType = &ElemType{Type: typ}
+		case "Elem":
+			elem = kv.Value
+		}
+	}
+	if elemType == nil {
+		return nil, fmt.Errorf("Elem schema missing Type")
+	}
+	if elemType.Type != "list" && elemType.Type != "set" && elemType.Type != "map" {
+		return elemType, nil
+	}
+
+	if elem == nil {
+		return nil, fmt.Errorf("Elem schema of type %s missing Elem", elemType.Type)
+	}
+	info, err := parseElem(elem, res)
+	if err != nil {
+		return nil, err
+	}
+	if info.isResource {
+		return nil, fmt.Errorf("Elem schema of type %s with a schema.Resource Elem has no framework element type", elemType.Type)
+	}
+	elemType.Elem = info.elemType
+	return elemType, nil
+}
+
 func parseResourceSchema(lit *ast.CompositeLit, res resolver) ([]Attribute, []Block, error) {
 	for _, elt := range lit.Elts {
 		kv, ok := elt.(*ast.KeyValueExpr)
@@ -449,15 +708,34 @@ func parseResourceSchema(lit *ast.CompositeLit, res resolver) ([]Attribute, []Bl
 			continue
 		}
 		key, ok := kv.Key.(*ast.Ident)
-		if !ok || key.Name != "Schema" {
+		if !ok {
 			continue
 		}
-		return parseSchemaMapExpr(kv.Value, res)
+		switch key.Name {
+		case "Schema":
+			return parseSchemaMapExpr(kv.Value, res)
+		case "SchemaFunc":
+			return parseSchemaFunc(kv.Value, res)
+		}
 	}
 
 	return nil, nil, fmt.Errorf("resource Schema field not found")
 }
 
+// parseSchemaFunc reads the schema map returned by a SchemaFunc: a function
+// literal or a function of the module.
+func parseSchemaFunc(expr ast.Expr, res resolver) ([]Attribute, []Block, error) {
+	switch v := expr.(type) {
+	case *ast.FuncLit:
+		return parseSchemaMapFromFunc(&ast.FuncDecl{Name: ast.NewIdent("SchemaFunc"), Type: v.Type, Body: v.Body}, res)
+	case *ast.Ident, *ast.SelectorExpr:
+		if fn := res.lookupFunc(v); fn != nil {
+			return parseSchemaMapFromFunc(fn, res)
+		}
+	}
+	return nil, nil, fmt.Errorf("SchemaFunc %s at %s not resolved", types.ExprString(expr), res.fset.Position(expr.Pos()))
+}
+
 func returnsSchemaProvider(fnType *ast.FuncType) bool {
 	if fnType.Results == nil || len(fnType.Results.List) == 0 {
 		return false
@@ -513,76 +791,43 @@ func isSchemaResourceType(expr ast.Expr) bool {
 	return false
 }
 
-func parseStringLiteral(expr ast.Expr) (string, bool) {
-	lit, ok := expr.(*ast.BasicLit)
-	if !ok || lit.Kind != token.STRING {
-		return "", false
-	}
-	value, err := strconv.Unquote(lit.Value)
-	if err != nil {
-		return "", false
-	}
-	return value, true
-}
-
-func parseStringExpr(expr ast.Expr) (string, bool) {
-	if val, ok := parseStringLiteral(expr); ok {
-		return val, true
-	}
-	if bin, ok := expr.(*ast.BinaryExpr); ok && bin.Op == token.ADD {
-		left, ok := parseStringExpr(bin.X)
-		if !ok {
-			return "", false
-		}
-		right, ok := parseStringExpr(bin.Y)
-		if !ok {
-			return "", false
-		}
-		return left + right, true
-	}
-	return "", false
-}
-
-func parseBoolLiteral(expr ast.Expr) (bool, bool) {
-	ident, ok := expr.(*ast.Ident)
-	if !ok {
-		return false, false
-	}
-	if ident.Name == "true" {
-		return true, true
-	}
-	if ident.Name == "false" {
-		return false, true
-	}
-	return false, false
-}
-
-func parseIntLiteral(expr ast.Expr) (int, bool) {
-	lit, ok := expr.(*ast.BasicLit)
-	if !ok || (lit.Kind != token.INT && lit.Kind != token.FLOAT) {
-		return 0, false
-	}
-	val, err := strconv.Atoi(strings.TrimSpace(lit.Value))
-	if err != nil {
-		return 0, false
-	}
-	return val, true
-}
-
-func buildResolver(files []*ast.File) resolver {
+func buildResolver(fset *token.FileSet, files []*ast.File) resolver {
 	res := resolver{
-		varMaps: map[string]*ast.CompositeLit{},
-		funcs:   map[string]*ast.FuncDecl{},
+		fset:      fset,
+		varMaps:   map[string]*ast.CompositeLit{},
+		varSlices: map[string]*ast.CompositeLit{},
+		funcs:     map[string]*ast.FuncDecl{},
+		files:     map[string]*ast.File{},
+		decls:     map[string]bool{},
+		consts:    map[string]ast.Expr{},
+		typeNames: map[string]bool{},
 	}
+	res.checker = newTypeChecker(fset, res.files)
 
 	for _, node := range files {
+		res.files[fset.File(node.Pos()).Name()] = node
 		for _, decl := range node.Decls {
 			switch d := decl.(type) {
 			case *ast.FuncDecl:
-				if d.Name != nil && d.Name.Name != "" {
+				if d.Name != nil && d.Name.Name != "" && d.Recv == nil {
 					res.funcs[d.Name.Name] = d
+					res.decls[d.Name.Name] = true
 				}
 			case *ast.GenDecl:
+				for _, spec := range d.Specs {
+					switch spec := spec.(type) {
+					case *ast.TypeSpec:
+						res.decls[spec.Name.Name] = true
+						res.typeNames[spec.Name.Name] = true
+					case *ast.ValueSpec:
+						for i, name := range spec.Names {
+							res.decls[name.Name] = true
+							if d.Tok == token.CONST && i < len(spec.Values) {
+								res.consts[name.Name] = spec.Values[i]
+							}
+						}
+					}
+				}
 				if d.Tok != token.VAR {
 					continue
 				}
@@ -596,8 +841,11 @@ func buildResolver(files []*ast.File) resolver {
 							continue
 						}
 						if lit, ok := valueSpec.Values[i].(*ast.CompositeLit); ok {
-							if _, ok := lit.Type.(*ast.MapType); ok || lit.Type == nil {
+							switch lit.Type.(type) {
+							case *ast.MapType, nil:
 								res.varMaps[name.Name] = lit
+							case *ast.ArrayType:
+								res.varSlices[name.Name] = lit
 							}
 						}
 					}
@@ -619,8 +867,8 @@ func parseSchemaMapFromFunc(fn *ast.FuncDecl, res resolver) ([]Attribute, []Bloc
 			continue
 		}
 		if ident, ok := ret.Results[0].(*ast.Ident); ok {
-			if lit := findLocalMapLiteral(fn, ident.Name); lit != nil {
-				return parseSchemaMap(lit, res)
+			if attrs, blocks, ok, err := parseLocalSchemaMap(fn.Body, ident.Name, res); ok {
+				return attrs, blocks, err
 			}
 		}
 		return parseSchemaMapExpr(ret.Results[0], res)
@@ -637,49 +885,9 @@ func functionName(expr ast.Expr) string {
 	}
 }
 
-func findLocalMapLiteral(fn *ast.FuncDecl, name string) *ast.CompositeLit {
-	if fn.Body == nil {
-		return nil
-	}
-
-	for _, stmt := range fn.Body.List {
-		switch s := stmt.(type) {
-		case *ast.AssignStmt:
-			for i, lhs := range s.Lhs {
-				ident, ok := lhs.(*ast.Ident)
-				if !ok || ident.Name != name || i >= len(s.Rhs) {
-					continue
-				}
-				if lit, ok := s.Rhs[i].(*ast.CompositeLit); ok {
-					return lit
-				}
-			}
-		case *ast.DeclStmt:
-			gen, ok := s.Decl.(*ast.GenDecl)
-			if !ok || gen.Tok != token.VAR {
-				continue
-			}
-			for _, spec := range gen.Specs {
-				valueSpec, ok := spec.(*ast.ValueSpec)
-				if !ok {
-					continue
-				}
-				for i, ident := range valueSpec.Names {
-					if ident.Name != name || i >= len(valueSpec.Values) {
-						continue
-					}
-					if lit, ok := valueSpec.Values[i].(*ast.CompositeLit); ok {
-						return lit
-					}
-				}
-			}
-		}
-	}
-
-	return nil
-}
-
-func findLocalProviderLiteral(fn *ast.FuncDecl, name string) *ast.CompositeLit {
+// findLocalCompositeLiteral returns the composite literal, or the address of
+// one, assigned to the local variable name.
+func findLocalCompositeLiteral(fn *ast.FuncDecl, name string) *ast.CompositeLit {
 	if fn.Body == nil {
 		return nil
 	}
@@ -741,6 +949,10 @@ func goFiles(root string) ([]string, error) {
 			if shouldSkipDir(d.Name()) {
 				return filepath.SkipDir
 			}
+			if path != root && fileExists(filepath.Join(path, "go.mod")) {
+				// nested modules are built separately and never part of the provider
+				return filepath.SkipDir
+			}
 			return nil
 		}
 
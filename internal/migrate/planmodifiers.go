@@ -0,0 +1,168 @@
+package migrate
+
+import (
+	"fmt"
+	"go/token"
+	"path/filepath"
+	"strings"
+)
+
+// SchemaMapping records an SDK schema field that was translated to framework
+// schema code, so the report can show what was applied and where it came
+// from.
+type SchemaMapping struct {
+	// Path is the attribute path, e.g. "rule.cidr".
+	Path string
+	// Field is the SDK field, e.g. "ForceNew" or "Optional+Computed".
+	Field    string
+	Position token.Position
+	// Result is the framework code generated for the field, or why none was.
+	Result string
+}
+
+func (m SchemaMapping) String() string {
+	return fmt.Sprintf("%s: %s (%s) -> %s", m.Path, m.Field, m.Position, m.Result)
+}
+
+// relativeTo returns the mapping with its position relative to root.
+func (m SchemaMapping) relativeTo(root string) SchemaMapping {
+	if rel, err := filepath.Rel(root, m.Position.Filename); err == nil {
+		m.Position.Filename = filepath.ToSlash(rel)
+	}
+	return m
+}
+
+// applyResourceMappings translates ForceNew, Default and Optional+Computed
+// of resource attributes, and ForceNew of blocks, to framework plan modifiers
+// and defaults. Provider and data source schemas have neither.
+func applyResourceMappings(info *ResourceInfo) {
+	info.Attributes, info.Blocks = mapSchema("", info.Attributes, info.Blocks, &info.Mappings)
+}
+
+func mapSchema(prefix string, attrs []Attribute, blocks []Block, mappings *[]SchemaMapping) ([]Attribute, []Block) {
+	mappedAttrs := make([]Attribute, len(attrs))
+	for i, attr := range attrs {
+		mappedAttrs[i] = mapAttribute(prefix, attr, mappings)
+	}
+
+	mappedBlocks := make([]Block, len(blocks))
+	for i, block := range blocks {
+		if block.ForceNew {
+			modifier := planModifierPackage(block.Kind) + ".RequiresReplace()"
+			block.PlanModifiers = append(block.PlanModifiers, modifier)
+			*mappings = append(*mappings, SchemaMapping{
+				Path:     prefix + block.Name,
+				Field:    "ForceNew",
+				Position: block.Fields["ForceNew"],
+				Result:   modifier,
+			})
+		}
+		block.Attributes, block.Blocks = mapSchema(prefix+block.Name+".", block.Attributes, block.Blocks, mappings)
+		mappedBlocks[i] = block
+	}
+	return mappedAttrs, mappedBlocks
+}
+
+func mapAttribute(prefix string, attr Attribute, mappings *[]SchemaMapping) Attribute {
+	if attr.Literal != "" {
+		return attr
+	}
+
+	path := prefix + attr.Name
+	record := func(field, positionField, result string) {
+		*mappings = append(*mappings, SchemaMapping{
+			Path:     path,
+			Field:    field,
+			Position: attr.Fields[positionField],
+			Result:   result,
+		})
+	}
+	modifiers := planModifierPackage(attr.Type)
+
+	if attr.ForceNew {
+		modifier := modifiers + ".RequiresReplace()"
+		attr.PlanModifiers = append(attr.PlanModifiers, modifier)
+		record("ForceNew", "ForceNew", modifier)
+	}
+
+	if _, ok := attr.Fields["Default"]; ok {
+		switch expr := defaultExpr(attr); {
+		case attr.Default == "":
+			record("Default", "Default", "not translated: Default is not a constant, set it in the framework schema by hand")
+		case expr == "":
+			record("Default", "Default", fmt.Sprintf("not translated: no framework default for %s attributes", attr.Type))
+		default:
+			attr.DefaultExpr = expr
+			attr.Computed = true
+			record("Default", "Default", expr+" (framework defaults require Computed)")
+		}
+		return attr
+	}
+
+	if attr.Optional && attr.Computed {
+		modifier := modifiers + ".UseStateForUnknown()"
+		attr.PlanModifiers = append(attr.PlanModifiers, modifier)
+		record("Optional+Computed", "Computed", modifier)
+	}
+
+	return attr
+}
+
+// singlePlanModifiers moves the plan modifiers of a list block rendered as a
+// single object, and the mappings reporting them, to objectplanmodifier.
+func singlePlanModifiers(info *ResourceInfo, block *Block) {
+	modifiers := make([]string, len(block.PlanModifiers))
+	for i, modifier := range block.PlanModifiers {
+		modifiers[i] = objectPlanModifier(modifier)
+	}
+	block.PlanModifiers = modifiers
+	for i, mapping := range info.Mappings {
+		if mapping.Path == block.Name {
+			info.Mappings[i].Result = objectPlanModifier(mapping.Result)
+		}
+	}
+}
+
+func objectPlanModifier(expr string) string {
+	if rest, ok := strings.CutPrefix(expr, "listplanmodifier."); ok {
+		return "objectplanmodifier." + rest
+	}
+	return expr
+}
+
+func planModifierPackage(attrType string) string {
+	switch attrType {
+	case "bool":
+		return "boolplanmodifier"
+	case "int":
+		return "int64planmodifier"
+	case "float":
+		return "float64planmodifier"
+	case "list":
+		return "listplanmodifier"
+	case "set":
+		return "setplanmodifier"
+	case "map":
+		return "mapplanmodifier"
+	default:
+		return "stringplanmodifier"
+	}
+}
+
+func defaultExpr(attr Attribute) string {
+	if attr.Default == "" {
+		return ""
+	}
+	switch attr.Type {
+	case "string":
+		return fmt.Sprintf("stringdefault.StaticString(%s)", attr.Default)
+	case "bool":
+		return fmt.Sprintf("booldefault.StaticBool(%s)", attr.Default)
+	case "int":
+		return fmt.Sprintf("int64default.StaticInt64(%s)", attr.Default)
+	case "float":
+		return fmt.Sprintf("float64default.StaticFloat64(%s)", attr.Default)
+	default:
+		return ""
+	}
+}
@@ -0,0 +1,96 @@
+package migrate
+
+import (
+	"go/token"
+	"reflect"
+	"testing"
+)
+
+func TestMapAttribute(t *testing.T) {
+	t.Parallel()
+
+	forceNew := token.Position{Filename: "resource.go", Line: 3, Column: 5}
+	def := token.Position{Filename: "resource.go", Line: 4, Column: 5}
+	computed := token.Position{Filename: "resource.go", Line: 5, Column: 5}
+	tests := []struct {
+		name         string
+		attr         Attribute
+		want         Attribute
+		wantMappings []SchemaMapping
+	}{
+		{
+			name: "ForceNew",
+			attr: Attribute{Name: "name", Type: "string", Required: true, ForceNew: true, Fields: map[string]token.Position{"ForceNew": forceNew}},
+			want: Attribute{Name: "name", Type: "string", Required: true, ForceNew: true, Fields: map[string]token.Position{"ForceNew": forceNew},
+				PlanModifiers: []string{"stringplanmodifier.RequiresReplace()"}},
+			wantMappings: []SchemaMapping{
+				{Path: "rule.name", Field: "ForceNew", Position: forceNew, Result: "stringplanmodifier.RequiresReplace()"},
+			},
+		},
+		{
+			name: "constant Default",
+			attr: Attribute{Name: "size", Type: "int", Optional: true, Default: "3", Fields: map[string]token.Position{"Default": def}},
+			want: Attribute{Name: "size", Type: "int", Optional: true, Computed: true, Default: "3", DefaultExpr: "int64default.StaticInt64(3)", Fields: map[string]token.Position{"Default": def}},
+			wantMappings: []SchemaMapping{
+				{Path: "rule.size", Field: "Default", Position: def, Result: "int64default.StaticInt64(3) (framework defaults require Computed)"},
+			},
+		},
+		{
+			name: "Default is not a constant",
+			attr: Attribute{Name: "zone", Type: "string", Optional: true, Fields: map[string]token.Position{"Default": def}},
+			want: Attribute{Name: "zone", Type: "string", Optional: true, Fields: map[string]token.Position{"Default": def}},
+			wantMappings: []SchemaMapping{
+				{Path: "rule.zone", Field: "Default", Position: def, Result: "not translated: Default is not a constant, set it in the framework schema by hand"},
+			},
+		},
+		{
+			name: "Default of a collection",
+			attr: Attribute{Name: "tags", Type: "list", Optional: true, Default: "nil", Fields: map[string]token.Position{"Default": def}},
+			want: Attribute{Name: "tags", Type: "list", Optional: true, Default: "nil", Fields: map[string]token.Position{"Default": def}},
+			wantMappings: []SchemaMapping{
+				{Path: "rule.tags", Field: "Default", Position: def, Result: "not translated: no framework default for list attributes"},
+			},
+		},
+		{
+			name: "Optional+Computed",
+			attr: Attribute{Name: "labels", Type: "map", Optional: true, Computed: true, Fields: map[string]token.Position{"Computed": computed}},
+			want: Attribute{Name: "labels", Type: "map", Optional: true, Computed: true, Fields: map[string]token.Position{"Computed": computed},
+				PlanModifiers: []string{"mapplanmodifier.UseStateForUnknown()"}},
+			wantMappings: []SchemaMapping{
+				{Path: "rule.labels", Field: "Optional+Computed", Position: computed, Result: "mapplanmodifier.UseStateForUnknown()"},
+			},
+		},
+		{
+			name: "Default takes precedence over Optional+Computed",
+			attr: Attribute{Name: "enabled", Type: "bool", Optional: true, Computed: true, ForceNew: true, Default: "true",
+				Fields: map[string]token.Position{"ForceNew": forceNew, "Default": def, "Computed": computed}},
+			want: Attribute{Name: "enabled", Type: "bool", Optional: true, Computed: true, ForceNew: true, Default: "true", DefaultExpr: "booldefault.StaticBool(true)",
+				Fields:        map[string]token.Position{"ForceNew": forceNew, "Default": def, "Computed": computed},
+				PlanModifiers: []string{"boolplanmodifier.RequiresReplace()"}},
+			wantMappings: []SchemaMapping{
+				{Path: "rule.enabled", Field: "ForceNew", Position: forceNew, Result: "boolplanmodifier.RequiresReplace()"},
+				{Path: "rule.enabled", Field: "Default", Position: def, Result: "booldefault.StaticBool(true) (framework defaults require Computed)"},
+			},
+		},
+		{
+			name: "literal",
+			attr: Attribute{Name: "status", Type: "string", ForceNew: true, Literal: "schema.StringAttribute{Computed: true}"},
+			want: Attribute{Name: "status", Type: "string", ForceNew: true, Literal: "schema.StringAttribute{Computed: true}"},
+		},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			var mappings []SchemaMapping
+			got := mapAttribute("rule.", tt.attr, &mappings)
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("unexpected attribute:\n%#v\nwant:\n%#v", got, tt.want)
+			}
+			if !reflect.DeepEqual(mappings, tt.wantMappings) {
+				t.Errorf("unexpected mappings:\n%v\nwant:\n%v", mappings, tt.wantMappings)
+			}
+		})
+	}
+}
@@ -0,0 +1,346 @@
+package migrate
+
+import (
+	"go/ast"
+	"go/token"
+	"go/types"
+	"path/filepath"
+)
+
+// registryWalker collects the entries of a ResourcesMap or DataSourcesMap.
+// Besides map literals it follows package variables, helper functions
+// returning a map, and local maps filled with maps.Copy, range loops copying
+// another map, or index assignments. Code that adds entries in any other way
+// is recorded in unfollowed.
+type registryWalker struct {
+	field      string
+	res        resolver
+	refs       []ResourceRef
+	unfollowed []SchemaMapping
+	visited    map[*ast.FuncDecl]bool
+}
+
+func parseRegistry(field string, expr ast.Expr, scope *ast.BlockStmt, res resolver) ([]ResourceRef, []SchemaMapping) {
+	w := &registryWalker{field: field, res: res, visited: map[*ast.FuncDecl]bool{}}
+	w.expr(expr, scope)
+	return w.refs, w.unfollowed
+}
+
+func (w *registryWalker) skip(node ast.Node, what, why string) {
+	w.unfollowed = append(w.unfollowed, SchemaMapping{
+		Path:     w.field,
+		Field:    what,
+		Position: w.res.fset.Position(node.Pos()),
+		Result:   "not followed: " + why,
+	})
+}
+
+// expr adds the entries of a map expression evaluated in scope, the body of
+// the function it appears in.
+func (w *registryWalker) expr(expr ast.Expr, scope *ast.BlockStmt) {
+	switch v := expr.(type) {
+	case *ast.CompositeLit:
+		w.literal(v)
+	case *ast.Ident:
+		if scope != nil && w.local(v.Name, scope) {
+			return
+		}
+		if lit, ok := w.res.varMaps[v.Name]; ok {
+			w.literal(lit)
+			return
+		}
+		w.skip(v, v.Name, "the map is not declared in the module")
+	case *ast.CallExpr:
+		if ident, ok := v.Fun.(*ast.Ident); ok && ident.Name == "make" {
+			return
+		}
+		fn := w.res.lookupFunc(v.Fun)
+		if fn == nil || fn.Body == nil {
+			w.skip(v, types.ExprString(v), "the function is not declared in the module")
+			return
+		}
+		if w.visited[fn] {
+			return
+		}
+		w.visited[fn] = true
+		for _, stmt := range fn.Body.List {
+			if ret, ok := stmt.(*ast.ReturnStmt); ok && len(ret.Results) > 0 {
+				w.expr(ret.Results[0], fn.Body)
+				return
+			}
+		}
+		w.skip(v, types.ExprString(v), "the function does not return a map")
+	default:
+		w.skip(expr, types.ExprString(expr), "the map is not a literal, variable or function call")
+	}
+}
+
+func (w *registryWalker) literal(lit *ast.CompositeLit) {
+	for _, elt := range lit.Elts {
+		kv, ok := elt.(*ast.KeyValueExpr)
+		if !ok {
+			continue
+		}
+		name, ok := w.res.evalString(kv.Key)
+		if !ok {
+			w.skip(kv, types.ExprString(kv.Key), "the key is not a constant string")
+			continue
+		}
+		w.refs = append(w.refs, ResourceRef{Name: name, value: kv.Value, node: kv})
+	}
+}
+
+// local follows the local map name declared in the top-level statements of
+// scope. It reports false when scope does not declare name.
+func (w *registryWalker) local(name string, scope *ast.BlockStmt) bool {
+	declared := false
+	for _, stmt := range scope.List {
+		if !declared {
+			if value := localValue(stmt, name); value != nil {
+				declared = true
+				w.expr(value, scope)
+			}
+			continue
+		}
+		if !mutatesMap(stmt, name) {
+			continue
+		}
+
+		switch s := stmt.(type) {
+		case *ast.ExprStmt:
+			if call, ok := s.X.(*ast.CallExpr); ok && w.res.isMapsCopy(call) && len(call.Args) == 2 && isIdent(call.Args[0], name) {
+				w.expr(call.Args[1], scope)
+				continue
+			}
+		case *ast.RangeStmt:
+			if src, ok := copyLoop(s, name); ok {
+				w.expr(src, scope)
+				continue
+			}
+			w.skip(s, "for range "+types.ExprString(s.X), "the loop does not copy another map entry by entry")
+			continue
+		case *ast.AssignStmt:
+			if len(s.Lhs) == 1 && len(s.Rhs) == 1 {
+				if index, ok := s.Lhs[0].(*ast.IndexExpr); ok && isIdent(index.X, name) {
+					if key, ok := w.res.evalString(index.Index); ok {
+						w.refs = append(w.refs, ResourceRef{Name: key, value: s.Rhs[0], node: s})
+						continue
+					}
+					w.skip(s, types.ExprString(s.Lhs[0]), "the key is not a constant string")
+					continue
+				}
+			}
+		}
+		w.skip(stmt, statementSummary(stmt), "entries added here are not migrated")
+	}
+	return declared
+}
+
+// isMapsCopy reports whether call is maps.Copy of the standard library or
+// golang.org/x/exp/maps.
+func (res resolver) isMapsCopy(call *ast.CallExpr) bool {
+	sel, ok := call.Fun.(*ast.SelectorExpr)
+	if !ok || sel.Sel.Name != "Copy" {
+		return false
+	}
+	pkg, ok := sel.X.(*ast.Ident)
+	if !ok {
+		return false
+	}
+	importPath, _ := res.importPath(pkg)
+	return importPath == "maps" || importPath == "golang.org/x/exp/maps"
+}
+
+// localValue returns the value stmt declares or assigns name to.
+func localValue(stmt ast.Stmt, name string) ast.Expr {
+	switch s := stmt.(type) {
+	case *ast.AssignStmt:
+		for i, lhs := range s.Lhs {
+			if isIdent(lhs, name) && i < len(s.Rhs) {
+				return s.Rhs[i]
+			}
+		}
+	case *ast.DeclStmt:
+		gen, ok := s.Decl.(*ast.GenDecl)
+		if !ok || gen.Tok != token.VAR {
+			return nil
+		}
+		for _, spec := range gen.Specs {
+			valueSpec, ok := spec.(*ast.ValueSpec)
+			if !ok {
+				continue
+			}
+			for i, ident := range valueSpec.Names {
+				if ident.Name == name && i < len(valueSpec.Values) {
+					return valueSpec.Values[i]
+				}
+			}
+		}
+	}
+	return nil
+}
+
+// mutatesMap reports whether stmt assigns an index of the map name, or a
+// field of an entry, or passes the map to a function, any of which may
+// change its entries.
+func mutatesMap(stmt ast.Stmt, name string) bool {
+	mutates := false
+	ast.Inspect(stmt, func(n ast.Node) bool {
+		switch v := n.(type) {
+		case *ast.AssignStmt:
+			for _, lhs := range v.Lhs {
+				if indexesMap(lhs, name) {
+					mutates = true
+				}
+			}
+		case *ast.CallExpr:
+			for _, arg := range v.Args {
+				if isIdent(arg, name) {
+					mutates = true
+				}
+			}
+		}
+		return !mutates
+	})
+	return mutates
+}
+
+// indexesMap reports whether expr is an entry of the map name, or a field or
+// element reached from one, e.g. m["x"].Optional.
+func indexesMap(expr ast.Expr, name string) bool {
+	for {
+		switch v := expr.(type) {
+		case *ast.IndexExpr:
+			if isIdent(v.X, name) {
+				return true
+			}
+			expr = v.X
+		case *ast.SelectorExpr:
+			expr = v.X
+		case *ast.StarExpr:
+			expr = v.X
+		case *ast.ParenExpr:
+			expr = v.X
+		default:
+			return false
+		}
+	}
+}
+
+// copyLoop matches for k, v := range src { name[k] = v } and returns src.
+func copyLoop(loop *ast.RangeStmt, name string) (ast.Expr, bool) {
+	key, ok := loop.Key.(*ast.Ident)
+	if !ok || loop.Value == nil || len(loop.Body.List) != 1 {
+		return nil, false
+	}
+	assign, ok := loop.Body.List[0].(*ast.AssignStmt)
+	if !ok || len(assign.Lhs) != 1 || len(assign.Rhs) != 1 {
+		return nil, false
+	}
+	index, ok := assign.Lhs[0].(*ast.IndexExpr)
+	if !ok || !isIdent(index.X, name) || !isIdent(index.Index, key.Name) || !isIdent(assign.Rhs[0], types.ExprString(loop.Value)) {
+		return nil, false
+	}
+	return loop.X, true
+}
+
+func isIdent(expr ast.Expr, name string) bool {
+	ident, ok := expr.(*ast.Ident)
+	return ok && ident.Name == name
+}
+
+// statementSummary describes a statement for the report.
+func statementSummary(stmt ast.Stmt) string {
+	switch s := stmt.(type) {
+	case *ast.ExprStmt:
+		return types.ExprString(s.X)
+	case *ast.AssignStmt:
+		return types.ExprString(s.Lhs[0]) + " " + s.Tok.String() + " " + types.ExprString(s.Rhs[0])
+	case *ast.RangeStmt:
+		return "for range " + types.ExprString(s.X)
+	default:
+		return "statement"
+	}
+}
+
+// lookupFunc returns the package level function a call refers to: a
+// function of the same module by name, or pkg.Name for a package of the
+// module.
+func (res resolver) lookupFunc(fun ast.Expr) *ast.FuncDecl {
+	switch v := fun.(type) {
+	case *ast.Ident:
+		return res.funcs[v.Name]
+	case *ast.SelectorExpr:
+		pkg, ok := v.X.(*ast.Ident)
+		if !ok {
+			return nil
+		}
+		importPath, ok := res.importPath(pkg)
+		if !ok {
+			return nil
+		}
+		return res.pkgFuncs[importPath+"."+v.Sel.Name]
+	}
+	return nil
+}
+
+// importPath returns the import path the package name pkg refers to in the
+// file it appears in.
+func (res resolver) importPath(pkg *ast.Ident) (string, bool) {
+	tokenFile := res.fset.File(pkg.Pos())
+	if tokenFile == nil || res.files[tokenFile.Name()] == nil {
+		return "", false
+	}
+	importPath, ok := fileImports(res.files[tokenFile.Name()])[pkg.Name]
+	return importPath, ok
+}
+
+// indexPackageDecls records the package level functions and constants of
+// each file by the import path of its package, derived from the module path
+// and the directory of the file relative to the module root.
+func (res *resolver) indexPackageDecls(moduleRoot, modulePath string) {
+	res.pkgFuncs = map[string]*ast.FuncDecl{}
+	res.pkgConsts = map[string]ast.Expr{}
+	res.packages = map[string]string{}
+	for name, file := range res.files {
+		rel, err := filepath.Rel(moduleRoot, filepath.Dir(name))
+		if err != nil {
+			continue
+		}
+		importPath := modulePath
+		if rel != "." {
+			importPath += "/" + filepath.ToSlash(rel)
+		}
+		res.packages[name] = importPath
+		for _, decl := range file.Decls {
+			switch d := decl.(type) {
+			case *ast.FuncDecl:
+				if d.Recv == nil {
+					res.pkgFuncs[importPath+"."+d.Name.Name] = d
+				}
+			case *ast.GenDecl:
+				if d.Tok != token.CONST {
+					continue
+				}
+				for _, spec := range d.Specs {
+					valueSpec := spec.(*ast.ValueSpec)
+					for i, name := range valueSpec.Names {
+						if i < len(valueSpec.Values) {
+							res.pkgConsts[importPath+"."+name.Name] = valueSpec.Values[i]
+						}
+					}
+				}
+			}
+		}
+	}
+}
+
+// registryNotes lists the registry and configure code that could not be
+// followed.
+func registryNotes(info ProviderInfo, moduleRoot string) []string {
+	var notes []string
+	for _, m := range info.Unfollowed {
+		notes = append(notes, "provider "+m.relativeTo(moduleRoot).String())
+	}
+	return notes
+}
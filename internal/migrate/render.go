@@ -6,88 +6,105 @@ import (
 	"go/format"
 	"path"
 	"sort"
+	"strconv"
 	"strings"
 	"text/template"
 )
 
-func renderFrameworkProvider(info ProviderInfo, providerName string) ([]byte, error) {
-	attrs := make([]Attribute, len(info.Attributes))
-	copy(attrs, info.Attributes)
-	sort.Slice(attrs, func(i, j int) bool {
-		return attrs[i].Name < attrs[j].Name
-	})
-
-	blocks := make([]Block, len(info.Blocks))
-	copy(blocks, info.Blocks)
-	sort.Slice(blocks, func(i, j int) bool {
-		return blocks[i].Name < blocks[j].Name
-	})
+func renderFrameworkProvider(tmpls templateSet, info ProviderInfo, providerName string, layout Layout, modulePath, configureMode string, configure crudTranslation) ([]byte, error) {
+	attrs := sortedAttributes(info.Attributes)
+	blocks := sortedBlocks(info.Blocks)
+	useTypes := usesCollectionTypes(attrs, blocks)
+	schemaBlocks, nestedAttrs := splitSchemaBlocks(blocks)
+	metaType, metaImport := metaTypeData(info.MetaType)
+	metaAttrs := sortedAttributes(info.MetaAttributes)
+	useTypes = useTypes || usesCollectionTypes(metaAttrs, nil)
+	// the custom types of the meta schema come from the same packages
+	imports := schemaImports(append(append([]Attribute{}, attrs...), metaAttrs...), blocks)
+	useFmt := metaType != "" && !layout.Split
 
-	useTypes := false
-	for _, attr := range attrs {
-		if attr.Type == "list" || attr.Type == "set" || attr.Type == "map" {
-			useTypes = true
-			break
-		}
-	}
-	if !useTypes {
-		for _, block := range blocks {
-			for _, attr := range block.Attributes {
-				if attr.Type == "list" || attr.Type == "set" || attr.Type == "map" {
-					useTypes = true
-					break
-				}
+	var models []ModelStruct
+	if configureMode == configureIndependent {
+		models = buildModels("fwproviderModel", attrs, blocks)
+		for _, model := range models {
+			for _, field := range model.Fields {
+				useTypes = useTypes || strings.HasPrefix(field.Type, "types.")
 			}
-			if useTypes {
-				break
+		}
+		// context is always imported, and the provider file imports the
+		// package of the meta type itself unless resources are split.
+		for _, spec := range configure.Imports {
+			switch {
+			case spec == strconv.Quote("fmt"):
+				useFmt = true
+			case spec == strconv.Quote("context"), spec == metaImport && !layout.Split:
+			default:
+				imports = append(imports, spec)
 			}
 		}
 	}
 
 	data := map[string]interface{}{
-		"ProviderName": providerName,
-		"Attributes":   attrs,
-		"Blocks":       blocks,
-		"UseTypes":     useTypes,
-	}
-
-	var buf bytes.Buffer
-	tmpl := template.Must(template.New("framework").Funcs(template.FuncMap{
-		"attrLiteral":  renderAttributeLiteral,
-		"blockLiteral": renderBlockLiteral,
-		"elementType":  renderElementType,
-	}).Parse(frameworkTemplate))
-
-	if err := tmpl.Execute(&buf, data); err != nil {
-		return nil, err
+		"Package":           layout.Package,
+		"Split":             layout.Split,
+		"ResourcesImport":   path.Join(layout.importPath(modulePath), resourcesPackage),
+		"DataSourcesImport": path.Join(layout.importPath(modulePath), dataSourcesPackage),
+		"ProviderName":      providerName,
+		"Attributes":        attrs,
+		"NestedAttributes":  nestedAttrs,
+		"Blocks":            schemaBlocks,
+		"UseTypes":          useTypes,
+		"UseFmt":            useFmt,
+		"Imports":           imports,
+		"MetaType":          metaType,
+		"MetaImport":        metaImport,
+		"ConfigureMode":     configureMode,
+		"Configure":         configure.Body,
+		"ConfigModels":      models,
+		"MetaAttributes":    metaAttrs,
 	}
 
-	return format.Source(buf.Bytes())
+	return executeTemplate(tmpls.framework, data)
 }
 
-func renderMuxedMain(info MainInfo, registryAddress string) ([]byte, error) {
+func renderFrameworkProviderTest(tmpls templateSet, info MainInfo, layout Layout, protocolVersion int) ([]byte, error) {
 	data := map[string]interface{}{
-		"BuildTags":       info.BuildTags,
-		"GoGenerate":      info.GoGenerate,
+		"Package":         layout.Package,
 		"ProviderImport":  info.ProviderImport,
-		"ProviderAlias":   info.ProviderAlias,
-		"FrameworkImport": deriveFrameworkImport(info.ProviderImport),
-		"Registry":        registryAddress,
+		"ProtocolVersion": protocolVersion,
 	}
 
-	var buf bytes.Buffer
-	tmpl := template.Must(template.New("main").Funcs(template.FuncMap{
-		"join": strings.Join,
-	}).Parse(mainTemplate))
+	return executeTemplate(tmpls.frameworkTest, data)
+}
 
-	if err := tmpl.Execute(&buf, data); err != nil {
-		return nil, err
+func renderMuxedMain(tmpls templateSet, info MainInfo, registryAddress string, protocolVersion int, layout Layout, modulePath string) ([]byte, error) {
+	frameworkImport := layout.importPath(modulePath)
+	alias := frameworkAlias(layout.Package, info.ProviderAlias)
+	importName := ""
+	if alias != path.Base(frameworkImport) {
+		importName = alias
+	}
+
+	data := map[string]interface{}{
+		"BuildTags":           info.BuildTags,
+		"GoGenerate":          info.GoGenerate,
+		"ProviderImport":      info.ProviderImport,
+		"ProviderAlias":       info.ProviderAlias,
+		"FrameworkImport":     frameworkImport,
+		"FrameworkImportName": importName,
+		"FrameworkAlias":      alias,
+		"Registry":            registryAddress,
+		"ProtocolVersion":     protocolVersion,
 	}
 
-	return format.Source(buf.Bytes())
+	return executeTemplate(tmpls.main, data)
 }
 
 func renderAttributeLiteral(attr Attribute) string {
+	if attr.Literal != "" {
+		return attr.Literal
+	}
+
 	attrType := frameworkAttributeType(attr.Type)
 	var buf bytes.Buffer
 
@@ -107,18 +124,113 @@ func renderAttributeLiteral(attr Attribute) string {
 	if attr.Sensitive {
 		fmt.Fprintf(&buf, "Sensitive: true,")
 	}
+	if attr.CustomType != "" {
+		fmt.Fprintf(&buf, "CustomType: %s,", attr.CustomType)
+	}
 	if attr.Type == "list" || attr.Type == "set" || attr.Type == "map" {
 		fmt.Fprintf(&buf, "ElementType: %s,", renderElementType(attr))
 	}
+	if len(attr.Validators) > 0 {
+		kind := strings.TrimSuffix(attrType, "Attribute")
+		fmt.Fprintf(&buf, "Validators: []validator.%s{%s},", kind, strings.Join(attr.Validators, ", "))
+	}
+	if len(attr.PlanModifiers) > 0 {
+		kind := strings.TrimSuffix(attrType, "Attribute")
+		fmt.Fprintf(&buf, "PlanModifiers: []planmodifier.%s{%s},", kind, strings.Join(attr.PlanModifiers, ", "))
+	}
+	if attr.DefaultExpr != "" {
+		fmt.Fprintf(&buf, "Default: %s,", attr.DefaultExpr)
+	}
 	buf.WriteString("}")
 	return buf.String()
 }
 
+// schemaPackages maps the helper packages generated schema code may refer to
+// to their import paths.
+var schemaPackages = map[string]string{
+	"attr":                frameworkModule + "/attr",
+	"planmodifier":        "github.com/hashicorp/terraform-plugin-framework/resource/schema/planmodifier",
+	"stringplanmodifier":  "github.com/hashicorp/terraform-plugin-framework/resource/schema/stringplanmodifier",
+	"boolplanmodifier":    "github.com/hashicorp/terraform-plugin-framework/resource/schema/boolplanmodifier",
+	"int64planmodifier":   "github.com/hashicorp/terraform-plugin-framework/resource/schema/int64planmodifier",
+	"float64planmodifier": "github.com/hashicorp/terraform-plugin-framework/resource/schema/float64planmodifier",
+	"listplanmodifier":    "github.com/hashicorp/terraform-plugin-framework/resource/schema/listplanmodifier",
+	"setplanmodifier":     "github.com/hashicorp/terraform-plugin-framework/resource/schema/setplanmodifier",
+	"mapplanmodifier":     "github.com/hashicorp/terraform-plugin-framework/resource/schema/mapplanmodifier",
+	"objectplanmodifier":  "github.com/hashicorp/terraform-plugin-framework/resource/schema/objectplanmodifier",
+	"stringdefault":       "github.com/hashicorp/terraform-plugin-framework/resource/schema/stringdefault",
+	"booldefault":         "github.com/hashicorp/terraform-plugin-framework/resource/schema/booldefault",
+	"int64default":        "github.com/hashicorp/terraform-plugin-framework/resource/schema/int64default",
+	"float64default":      "github.com/hashicorp/terraform-plugin-framework/resource/schema/float64default",
+	"jsontypes":           jsonTypesModule + "/jsontypes",
+	"timetypes":           timeTypesModule + "/timetypes",
+	"iptypes":             netTypesModule + "/iptypes",
+}
+
+// schemaImports returns the import specs of the helper packages used by the
+// attributes of a schema, e.g. for plan modifiers, defaults and custom types.
+func schemaImports(attrs []Attribute, blocks []Block) []string {
+	used := map[string]bool{}
+	if usesObjectTypes(blocks) {
+		used["attr"] = true
+	}
+	// Object types only keep the custom types of their attributes.
+	var collect func([]Attribute, []Block, bool)
+	collect = func(attrs []Attribute, blocks []Block, object bool) {
+		for _, attr := range attrs {
+			if attr.Literal != "" {
+				continue
+			}
+			exprs := []string{attr.CustomType}
+			if !object {
+				exprs = append(exprs, attr.PlanModifiers...)
+				exprs = append(exprs, attr.DefaultExpr)
+				if len(attr.PlanModifiers) > 0 {
+					used["planmodifier"] = true
+				}
+			}
+			for _, expr := range exprs {
+				if pkg, _, ok := strings.Cut(expr, "."); ok && schemaPackages[pkg] != "" {
+					used[pkg] = true
+				}
+			}
+		}
+		for _, block := range blocks {
+			if !object && len(block.PlanModifiers) > 0 {
+				used["planmodifier"] = true
+				for _, expr := range block.PlanModifiers {
+					if pkg, _, ok := strings.Cut(expr, "."); ok && schemaPackages[pkg] != "" {
+						used[pkg] = true
+					}
+				}
+			}
+			collect(block.Attributes, block.Blocks, object || block.AttributeMode == attributeModeObject)
+		}
+	}
+	collect(attrs, blocks, false)
+
+	imports := make([]string, 0, len(used))
+	for pkg := range used {
+		imports = append(imports, strconv.Quote(schemaPackages[pkg]))
+	}
+	sort.Strings(imports)
+	return imports
+}
+
 func renderBlockLiteral(block Block) string {
+	if block.AttributeMode != "" {
+		return renderAttributeBlockLiteral(block)
+	}
+
 	var buf bytes.Buffer
 
 	blockType := "ListNestedBlock"
-	if block.Kind == "set" {
+	switch {
+	case block.Strategy == maxItemsOneSingleBlock:
+		blockType = "SingleNestedBlock"
+	case block.Strategy == maxItemsOneSingleAttribute:
+		blockType = "SingleNestedAttribute"
+	case block.Kind == "set":
 		blockType = "SetNestedBlock"
 	}
 
@@ -126,32 +238,103 @@ func renderBlockLiteral(block Block) string {
 	if block.Description != "" {
 		fmt.Fprintf(&buf, "Description: %q,", block.Description)
 	}
-	buf.WriteString("NestedObject: schema.NestedBlockObject{Attributes: map[string]schema.Attribute{")
+	if block.Strategy == maxItemsOneSingleAttribute {
+		if block.MinItems > 0 {
+			buf.WriteString("Required: true,")
+		} else {
+			buf.WriteString("Optional: true,")
+		}
+		if block.Sensitive {
+			buf.WriteString("Sensitive: true,")
+		}
+	}
+	if len(block.PlanModifiers) > 0 {
+		kind := "Object"
+		switch blockType {
+		case "ListNestedBlock":
+			kind = "List"
+		case "SetNestedBlock":
+			kind = "Set"
+		}
+		fmt.Fprintf(&buf, "PlanModifiers: []planmodifier.%s{%s},", kind, strings.Join(block.PlanModifiers, ", "))
+	}
+	if blockType == "ListNestedBlock" || blockType == "SetNestedBlock" {
+		buf.WriteString("NestedObject: schema.NestedBlockObject{")
+	}
+	buf.WriteString("Attributes: map[string]schema.Attribute{")
+	for _, attr := range sortedAttributes(block.Attributes) {
+		fmt.Fprintf(&buf, "%q: %s,", attr.Name, renderAttributeLiteral(attr))
+	}
+	buf.WriteString("},")
+	if len(block.Blocks) > 0 {
+		buf.WriteString("Blocks: map[string]schema.Block{")
+		for _, nested := range sortedBlocks(block.Blocks) {
+			fmt.Fprintf(&buf, "%q: %s,", nested.Name, renderBlockLiteral(nested))
+		}
+		buf.WriteString("},")
+	}
+	if blockType == "ListNestedBlock" || blockType == "SetNestedBlock" {
+		buf.WriteString("},")
+	}
+	buf.WriteString("}")
 
-	attrs := make([]Attribute, len(block.Attributes))
-	copy(attrs, block.Attributes)
-	sort.Slice(attrs, func(i, j int) bool {
-		return attrs[i].Name < attrs[j].Name
-	})
+	return buf.String()
+}
 
+// usesCollectionTypes reports whether any attribute in the schema needs the
+// types package for its element type.
+func usesCollectionTypes(attrs []Attribute, blocks []Block) bool {
 	for _, attr := range attrs {
-		fmt.Fprintf(&buf, "%q: %s,", attr.Name, renderAttributeLiteral(attr))
+		if attr.Type == "list" || attr.Type == "set" || attr.Type == "map" {
+			return true
+		}
+	}
+	for _, block := range blocks {
+		if block.AttributeMode == attributeModeObject || usesCollectionTypes(block.Attributes, block.Blocks) {
+			return true
+		}
 	}
-	buf.WriteString("}},}")
+	return false
+}
 
-	return buf.String()
+func sortedAttributes(attrs []Attribute) []Attribute {
+	sorted := make([]Attribute, len(attrs))
+	copy(sorted, attrs)
+	sort.Slice(sorted, func(i, j int) bool {
+		return sorted[i].Name < sorted[j].Name
+	})
+	return sorted
+}
+
+func sortedBlocks(blocks []Block) []Block {
+	sorted := make([]Block, len(blocks))
+	copy(sorted, blocks)
+	sort.Slice(sorted, func(i, j int) bool {
+		return sorted[i].Name < sorted[j].Name
+	})
+	return sorted
 }
 
 func renderElementType(attr Attribute) string {
-	switch attr.ElemType {
-	case "string":
-		return "types.StringType"
+	return renderElemType(attr.ElemType)
+}
+
+// renderElemType renders an element type with the element types of nested
+// collections, e.g. types.MapType{ElemType: types.ListType{...}}.
+func renderElemType(elem *ElemType) string {
+	switch elem.Type {
 	case "bool":
 		return "types.BoolType"
 	case "int":
 		return "types.Int64Type"
 	case "float":
 		return "types.Float64Type"
+	case "list":
+		return fmt.Sprintf("types.ListType{ElemType: %s}", renderElemType(elem.Elem))
+	case "set":
+		return fmt.Sprintf("types.SetType{ElemType: %s}", renderElemType(elem.Elem))
+	case "map":
+		return fmt.Sprintf("types.MapType{ElemType: %s}", renderElemType(elem.Elem))
 	default:
 		return "types.StringType"
 	}
@@ -178,38 +361,291 @@ func frameworkAttributeType(typ string) string {
 	}
 }
 
-func deriveFrameworkImport(providerImport string) string {
-	if providerImport == "" {
-		return ""
+func renderRegistry(tmpls templateSet, kind string, meta *MetaType) ([]byte, error) {
+	metaType, metaImport := metaTypeData(meta)
+	data := map[string]interface{}{
+		"Kind":       kind,
+		"MetaType":   metaType,
+		"MetaImport": metaImport,
+	}
+
+	return executeTemplate(tmpls.registry, data)
+}
+
+// renderResource renders a framework resource or data source. bodies holds
+// the translated CRUD function bodies by operation; missing operations get a
+// TODO stub. timeoutsStyle selects a timeouts block or attribute. With a
+// meta type the resource gets it in Configure.
+func renderResource(tmpls templateSet, info ResourceInfo, bodies map[string]crudTranslation, providerName string, layout Layout, timeoutsStyle string, meta *MetaType) ([]byte, error) {
+	attrs := sortedAttributes(info.Attributes)
+	blocks := sortedBlocks(info.Blocks)
+
+	kind := "Resource"
+	pkg := layout.Package
+	registry := "resourceFactories"
+	tmpl := tmpls.resource
+	if info.DataSource {
+		kind = "DataSource"
+		registry = "dataSourceFactories"
+		tmpl = tmpls.dataSource
+	}
+	if layout.Split {
+		pkg = resourcesPackage
+		if info.DataSource {
+			pkg = dataSourcesPackage
+		}
+		registry = "factories"
+	}
+
+	base := resourceTypeBase(info.Name, providerName)
+	typeName := lowerGoName(base) + kind
+	modelName := typeName + "Model"
+
+	useTypes := len(attrs) > 0 || usesCollectionTypes(attrs, blocks) || schemaHasAttributes(blocks)
+	importSet := map[string]bool{}
+	var stdImports, imports []string
+	for _, spec := range schemaImports(attrs, blocks) {
+		importSet[spec] = true
+		imports = append(imports, spec)
+	}
+	if info.Importer == importerPassthrough {
+		spec := strconv.Quote(frameworkModule + "/path")
+		importSet[spec] = true
+		imports = append(imports, spec)
+	}
+	metaType, metaImport := metaTypeData(meta)
+	if metaImport != "" {
+		importSet[metaImport] = true
+		imports = append(imports, metaImport)
+	}
+	var upgraders []map[string]interface{}
+	for _, upgrade := range info.StateUpgrades {
+		useTypes = useTypes || usesCollectionTypes(upgrade.Attributes, upgrade.Blocks)
+		for _, spec := range schemaImports(nil, upgrade.Blocks) {
+			if !importSet[spec] {
+				importSet[spec] = true
+				imports = append(imports, spec)
+			}
+		}
+		priorBlocks, priorNested := splitSchemaBlocks(sortedBlocks(upgrade.Blocks))
+		upgraders = append(upgraders, map[string]interface{}{
+			"Version":          upgrade.Version,
+			"Attributes":       sortedAttributes(upgrade.Attributes),
+			"NestedAttributes": priorNested,
+			"Blocks":           priorBlocks,
+			"Timeouts":         "",
+			"Body":             bodies[upgradeOp(upgrade.Version)].Body,
+		})
+	}
+	models := buildModels(modelName, attrs, blocks)
+	schemaBlocks, nestedAttrs := splitSchemaBlocks(blocks)
+	timeoutsCode := ""
+	if info.Timeouts != nil {
+		timeoutsCode = timeoutsSchema(info, timeoutsStyle)
+		spec := resourceTimeoutsImport
+		if info.DataSource {
+			spec = dataSourceTimeoutsImport
+		}
+		importSet[spec] = true
+		imports = append(imports, spec)
+		if timeoutsUseTime(info.Timeouts) {
+			importSet[`"time"`] = true
+			stdImports = append(stdImports, `"time"`)
+		}
+		models[0].Fields = append(models[0].Fields, ModelField{Name: "Timeouts", Type: "timeouts.Value", Tag: "timeouts"})
+	}
+	for _, body := range bodies {
+		useTypes = useTypes || body.UsesTypes
+		for _, spec := range body.Imports {
+			if importSet[spec] {
+				continue
+			}
+			importSet[spec] = true
+			if isStdImport(spec) {
+				stdImports = append(stdImports, spec)
+			} else {
+				imports = append(imports, spec)
+			}
+		}
+	}
+	sort.Strings(stdImports)
+	sort.Strings(imports)
+
+	schemaVersion := info.SchemaVersion
+	if upgrader, prior, ok := blockStrategyUpgrader(info, models, timeoutsCode); ok {
+		upgraders = append(upgraders, upgrader)
+		models = append(models, prior)
+		schemaVersion++
+	}
+	upgradeTimeouts := false
+	for _, upgrader := range upgraders {
+		upgradeTimeouts = upgradeTimeouts || upgrader["Timeouts"] != ""
+	}
+
+	data := map[string]interface{}{
+		"Package":           pkg,
+		"Registry":          registry,
+		"Name":              info.Name,
+		"TypeName":          typeName,
+		"Constructor":       "New" + goName(base) + kind,
+		"ModelName":         modelName,
+		"Models":            models,
+		"Attributes":        attrs,
+		"NestedAttributes":  nestedAttrs,
+		"Blocks":            schemaBlocks,
+		"Timeouts":          timeoutsCode,
+		"TimeoutsAttribute": timeoutsStyle == timeoutsAttributes,
+		"OperationTimeouts": info.Timeouts,
+		"UseTypes":          useTypes,
+		"StdImports":        stdImports,
+		"Imports":           imports,
+		"Create":            bodies["create"].Body,
+		"Read":              bodies["read"].Body,
+		"Update":            bodies["update"].Body,
+		"Delete":            bodies["delete"].Body,
+		"Importer":          info.Importer,
+		"ImportState":       bodies["import"].Body,
+		"SchemaVersion":     schemaVersion,
+		"StateUpgraders":    upgraders,
+		"UpgradeTimeouts":   upgradeTimeouts,
+		"ModifyPlan":        bodies["modify plan"].Body,
+		"PlanFunctions":     bodies["plan functions"].Body,
+		"Validators":        bodies["validators"].Body,
+		"MetaType":          metaType,
+	}
+
+	return executeTemplate(tmpl, data)
+}
+
+// isStdImport reports whether an import spec such as `"fmt"` or
+// `str "strings"` names a standard library package.
+func isStdImport(spec string) bool {
+	importPath := spec[strings.Index(spec, `"`):]
+	first, _, _ := strings.Cut(strings.Trim(importPath, `"`), "/")
+	return !strings.Contains(first, ".")
+}
+
+func schemaHasAttributes(blocks []Block) bool {
+	for _, block := range blocks {
+		if len(block.Attributes) > 0 || schemaHasAttributes(block.Blocks) {
+			return true
+		}
+	}
+	return false
+}
+
+func executeTemplate(tmpl *template.Template, data interface{}) ([]byte, error) {
+	var buf bytes.Buffer
+	if err := tmpl.Execute(&buf, data); err != nil {
+		return nil, err
+	}
+
+	source, err := format.Source(buf.Bytes())
+	if err != nil {
+		return nil, fmt.Errorf("template %s: generated code does not parse: %w", tmpl.Name(), err)
 	}
-	return path.Join(path.Dir(providerImport), "framework")
+	return source, nil
 }
 
-const frameworkTemplate = `package framework
+const frameworkTemplate = `package {{ .Package }}
 
 import (
 	"context"
+	{{- if .UseFmt }}
+	"fmt"
+	{{- end }}
+	{{- if eq .ConfigureMode "shared" }}
+	"sync"
+	{{- end }}
 
 	"github.com/hashicorp/terraform-plugin-framework/datasource"
+	{{- if and .MetaType (not .Split) }}
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+	{{- end }}
 	"github.com/hashicorp/terraform-plugin-framework/provider"
+	{{- if .MetaAttributes }}
+	"github.com/hashicorp/terraform-plugin-framework/provider/metaschema"
+	{{- end }}
 	"github.com/hashicorp/terraform-plugin-framework/provider/schema"
 	"github.com/hashicorp/terraform-plugin-framework/resource"
 	{{- if .UseTypes }}
 	"github.com/hashicorp/terraform-plugin-framework/types"
 	{{- end }}
+	{{- range .Imports }}
+	{{ . }}
+	{{- end }}
+	{{- if .Split }}
+
+	"{{ .DataSourcesImport }}"
+	"{{ .ResourcesImport }}"
+	{{- else if .MetaType }}
+
+	{{ .MetaImport }}
+	{{- end }}
 )
 
 var _ provider.Provider = (*fwprovider)(nil)
+{{- if .MetaAttributes }}
+var _ provider.ProviderWithMetaSchema = (*fwprovider)(nil)
+{{- end }}
+{{- if not .Split }}
+
+// resourceFactories and dataSourceFactories are appended to by the resources
+// and data sources generated with migrate-resources.
+var (
+	resourceFactories   []func() resource.Resource
+	dataSourceFactories []func() datasource.DataSource
+)
+{{- end }}
 
 type fwprovider struct {
 	Primary interface {
 		Meta() interface{}
 	}
+	{{- if eq .ConfigureMode "shared" }}
+	meta *sdkMeta
+	{{- end }}
 }
 
 func New(primary interface{ Meta() interface{} }) provider.Provider {
+	{{- if eq .ConfigureMode "shared" }}
+	return &fwprovider{Primary: primary, meta: &sdkMeta{primary: primary}}
+	{{- else }}
 	return &fwprovider{Primary: primary}
+	{{- end }}
+}
+{{- if eq .ConfigureMode "shared" }}
+
+// sdkMeta is the provider data of the framework resources and data sources.
+// The mux server does not order ConfigureProvider between the SDK and the
+// framework provider, so the SDK meta value is read when a resource or data
+// source is configured instead of in Configure, and kept once it is set.
+type sdkMeta struct {
+	primary interface{ Meta() interface{} }
+
+	mu    sync.Mutex
+	value interface{}
+}
+
+// Meta returns the meta value of the SDK provider, or nil while the SDK
+// provider is not configured yet.
+func (m *sdkMeta) Meta() interface{} {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if m.value == nil {
+		m.value = m.primary.Meta()
+	}
+	return m.value
 }
+{{- else }}
+{{ range .ConfigModels }}
+type {{ .Name }} struct {
+	{{- range .Fields }}
+	{{ .Name }} {{ .Type }} ` + "`" + `tfsdk:"{{ .Tag }}"` + "`" + `
+	{{- end }}
+}
+{{ end }}
+{{- end }}
 
 func (p *fwprovider) Metadata(_ context.Context, _ provider.MetadataRequest, response *provider.MetadataResponse) {
 	response.TypeName = "{{ .ProviderName }}"
@@ -221,6 +657,9 @@ func (p *fwprovider) Schema(_ context.Context, _ provider.SchemaRequest, respons
 			{{- range .Attributes }}
 			"{{ .Name }}": {{ attrLiteral . }},
 			{{- end }}
+			{{- range .NestedAttributes }}
+			"{{ .Name }}": {{ blockLiteral . }},
+			{{- end }}
 		},
 		Blocks: map[string]schema.Block{
 			{{- range .Blocks }}
@@ -229,19 +668,627 @@ func (p *fwprovider) Schema(_ context.Context, _ provider.SchemaRequest, respons
 		},
 	}
 }
+{{- if .MetaAttributes }}
+
+// MetaSchema declares the provider_meta schema of the SDK ProviderMetaSchema,
+// which mux requires both providers to serve alike.
+func (p *fwprovider) MetaSchema(_ context.Context, _ provider.MetaSchemaRequest, response *provider.MetaSchemaResponse) {
+	response.Schema = metaschema.Schema{
+		Attributes: map[string]metaschema.Attribute{
+			{{- range .MetaAttributes }}
+			"{{ .Name }}": {{ metaAttrLiteral . }},
+			{{- end }}
+		},
+	}
+}
+{{- end }}
+
+{{- if eq .ConfigureMode "shared" }}
 
 func (p *fwprovider) Configure(_ context.Context, _ provider.ConfigureRequest, response *provider.ConfigureResponse) {
-	response.DataSourceData = p.Primary.Meta()
-	response.ResourceData = p.Primary.Meta()
+	response.DataSourceData = p.meta
+	response.ResourceData = p.meta
 }
+{{- else }}
+
+// Configure builds the provider data from the provider configuration, ported
+// from the SDK configure function, instead of reading the SDK meta value.
+func (p *fwprovider) Configure(ctx context.Context, req provider.ConfigureRequest, resp *provider.ConfigureResponse) {
+	var config fwproviderModel
+	resp.Diagnostics.Append(req.Config.Get(ctx, &config)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+	{{- with .Configure }}
+
+	{{ . }}
+	{{- end }}
+}
+{{- end }}
 
 func (p *fwprovider) DataSources(_ context.Context) []func() datasource.DataSource {
-	return nil
+	{{- if .Split }}
+	return datasources.All()
+	{{- else }}
+	return dataSourceFactories
+	{{- end }}
 }
 
 func (p *fwprovider) Resources(_ context.Context) []func() resource.Resource {
-	return nil
+	{{- if .Split }}
+	return resources.All()
+	{{- else }}
+	return resourceFactories
+	{{- end }}
 }
+{{- if and .MetaType (not .Split) }}
+` + providerMetaTemplate + `
+{{- end }}
+`
+
+// providerMetaTemplate is the typed accessor of the provider data, generated
+// next to the resources and data sources that use it.
+const providerMetaTemplate = `
+// providerMeta returns the provider data as the {{ .MetaType }} the SDK
+// provider configures, resolving the SDK meta accessor the framework
+// provider hands out. It reports false without a diagnostic while the
+// provider is not configured yet, e.g. during validation.
+func providerMeta(data interface{}, diags *diag.Diagnostics) (meta {{ .MetaType }}, ok bool) {
+	if source, isSource := data.(interface{ Meta() interface{} }); isSource {
+		data = source.Meta()
+	}
+	if data == nil {
+		return meta, false
+	}
+	meta, ok = data.({{ .MetaType }})
+	if !ok {
+		diags.AddError(
+			"Unexpected Provider Data",
+			fmt.Sprintf("Expected {{ .MetaType }}, got: %T. Please report this issue to the provider developers.", data),
+		)
+	}
+	return meta, ok
+}`
+
+// frameworkTestTemplate checks that the framework provider hands out the SDK
+// meta value when it is configured before the SDK provider.
+const frameworkTestTemplate = `package {{ .Package }}
+
+import (
+	"context"
+	"reflect"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-framework/provider"
+	"github.com/hashicorp/terraform-plugin-framework/providerserver"
+	"github.com/hashicorp/terraform-plugin-go/tfprotov5"
+	{{- if eq .ProtocolVersion 6 }}
+	"github.com/hashicorp/terraform-plugin-go/tfprotov6"
+	{{- end }}
+	"github.com/hashicorp/terraform-plugin-go/tftypes"
+	{{- if eq .ProtocolVersion 6 }}
+	"github.com/hashicorp/terraform-plugin-mux/tf5to6server"
+	"github.com/hashicorp/terraform-plugin-mux/tf6muxserver"
+	{{- else }}
+	"github.com/hashicorp/terraform-plugin-mux/tf5muxserver"
+	{{- end }}
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+
+	sdkprovider "{{ .ProviderImport }}"
+)
+
+// TestConfigureBeforeSDK configures a mux server that lists the framework
+// server before the SDK server. The mux server configures its servers in
+// order, so the framework provider is configured first; the provider data
+// handed to resources must still resolve to the SDK meta value afterwards.
+func TestConfigureBeforeSDK(t *testing.T) {
+	primary := sdkprovider.Provider()
+	p := New(primary)
+
+	ctx := context.Background()
+	var schemaResp provider.SchemaResponse
+	p.Schema(ctx, provider.SchemaRequest{}, &schemaResp)
+	configType := schemaResp.Schema.Type().TerraformType(ctx)
+	{{- if eq .ProtocolVersion 6 }}
+	config, err := tfprotov6.NewDynamicValue(configType, tftypes.NewValue(configType, nil))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	upgradedSdkServer, err := tf5to6server.UpgradeServer(ctx,
+		func() tfprotov5.ProviderServer {
+			return schema.NewGRPCProviderServer(primary)
+		},
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+	muxServer, err := tf6muxserver.NewMuxServer(ctx,
+		providerserver.NewProtocol6(p),
+		func() tfprotov6.ProviderServer {
+			return upgradedSdkServer
+		},
+	)
+	{{- else }}
+	config, err := tfprotov5.NewDynamicValue(configType, tftypes.NewValue(configType, nil))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	muxServer, err := tf5muxserver.NewMuxServer(ctx,
+		providerserver.NewProtocol5(p),
+		func() tfprotov5.ProviderServer {
+			return schema.NewGRPCProviderServer(primary)
+		},
+	)
+	{{- end }}
+	if err != nil {
+		t.Fatal(err)
+	}
+	server := muxServer.ProviderServer()
+
+	{{- if eq .ProtocolVersion 6 }}
+	resp, err := server.ConfigureProvider(ctx, &tfprotov6.ConfigureProviderRequest{Config: &config})
+	{{- else }}
+	resp, err := server.ConfigureProvider(ctx, &tfprotov5.ConfigureProviderRequest{Config: &config})
+	{{- end }}
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, d := range resp.Diagnostics {
+		{{- if eq .ProtocolVersion 6 }}
+		if d.Severity == tfprotov6.DiagnosticSeverityError {
+		{{- else }}
+		if d.Severity == tfprotov5.DiagnosticSeverityError {
+		{{- end }}
+			t.Skipf("the provider cannot be configured without a configuration: %s: %s", d.Summary, d.Detail)
+		}
+	}
+
+	data := p.(*fwprovider).meta
+	if got, want := data.Meta(), primary.Meta(); !reflect.DeepEqual(got, want) {
+		t.Errorf("resource provider data resolves to %v, want the SDK meta value %v", got, want)
+	}
+}
+`
+
+const registryTemplate = `{{- if eq .Kind "resources" -}}
+package resources
+
+{{ if .MetaType -}}
+import (
+	"fmt"
+
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+	"github.com/hashicorp/terraform-plugin-framework/resource"
+
+	{{ .MetaImport }}
+)
+{{- else -}}
+import "github.com/hashicorp/terraform-plugin-framework/resource"
+{{- end }}
+
+// factories is appended to by the resources generated with migrate-resources.
+var factories []func() resource.Resource
+
+// All returns the framework resources served by the provider.
+func All() []func() resource.Resource {
+	return factories
+}
+{{- else -}}
+package datasources
+
+{{ if .MetaType -}}
+import (
+	"fmt"
+
+	"github.com/hashicorp/terraform-plugin-framework/datasource"
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+
+	{{ .MetaImport }}
+)
+{{- else -}}
+import "github.com/hashicorp/terraform-plugin-framework/datasource"
+{{- end }}
+
+// factories is appended to by the data sources generated with migrate-resources.
+var factories []func() datasource.DataSource
+
+// All returns the framework data sources served by the provider.
+func All() []func() datasource.DataSource {
+	return factories
+}
+{{- end }}
+{{- if .MetaType }}
+` + providerMetaTemplate + `
+{{- end }}
+`
+
+const resourceTemplate = `package {{ .Package }}
+
+import (
+	"context"
+	{{- range .StdImports }}
+	{{ . }}
+	{{- end }}
+
+	"github.com/hashicorp/terraform-plugin-framework/resource"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema"
+	{{- if .UseTypes }}
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	{{- end }}
+	{{- range .Imports }}
+	{{ . }}
+	{{- end }}
+)
+
+var _ resource.Resource = (*{{ .TypeName }})(nil)
+{{- if .Importer }}
+var _ resource.ResourceWithImportState = (*{{ .TypeName }})(nil)
+{{- end }}
+{{- if .StateUpgraders }}
+var _ resource.ResourceWithUpgradeState = (*{{ .TypeName }})(nil)
+{{- end }}
+{{- if .ModifyPlan }}
+var _ resource.ResourceWithModifyPlan = (*{{ .TypeName }})(nil)
+{{- end }}
+{{- if .MetaType }}
+var _ resource.ResourceWithConfigure = (*{{ .TypeName }})(nil)
+{{- end }}
+
+func init() {
+	{{ .Registry }} = append({{ .Registry }}, {{ .Constructor }})
+}
+
+func {{ .Constructor }}() resource.Resource {
+	return &{{ .TypeName }}{}
+}
+
+{{ if .MetaType -}}
+type {{ .TypeName }} struct {
+	meta {{ .MetaType }}
+}
+{{- else -}}
+type {{ .TypeName }} struct{}
+{{- end }}
+{{ range .Models }}
+type {{ .Name }} struct {
+	{{- range .Fields }}
+	{{ .Name }} {{ .Type }} ` + "`" + `tfsdk:"{{ .Tag }}"` + "`" + `
+	{{- end }}
+}
+{{ end }}
+func (r *{{ .TypeName }}) Metadata(_ context.Context, _ resource.MetadataRequest, resp *resource.MetadataResponse) {
+	resp.TypeName = "{{ .Name }}"
+}
+
+func (r *{{ .TypeName }}) Schema({{ if .Timeouts }}ctx{{ else }}_{{ end }} context.Context, _ resource.SchemaRequest, resp *resource.SchemaResponse) {
+	resp.Schema = schema.Schema{
+		{{- if .SchemaVersion }}
+		Version: {{ .SchemaVersion }},
+		{{- end }}
+		Attributes: map[string]schema.Attribute{
+			{{- range .Attributes }}
+			"{{ .Name }}": {{ attrLiteral . }},
+			{{- end }}
+			{{- range .NestedAttributes }}
+			"{{ .Name }}": {{ blockLiteral . }},
+			{{- end }}
+			{{- if and .Timeouts .TimeoutsAttribute }}
+			"timeouts": {{ .Timeouts }},
+			{{- end }}
+		},
+		Blocks: map[string]schema.Block{
+			{{- range .Blocks }}
+			"{{ .Name }}": {{ blockLiteral . }},
+			{{- end }}
+			{{- if and .Timeouts (not .TimeoutsAttribute) }}
+			"timeouts": {{ .Timeouts }},
+			{{- end }}
+		},
+	}
+}
+{{- if .MetaType }}
+
+func (r *{{ .TypeName }}) Configure(_ context.Context, req resource.ConfigureRequest, resp *resource.ConfigureResponse) {
+	meta, ok := providerMeta(req.ProviderData, &resp.Diagnostics)
+	if !ok {
+		return
+	}
+	r.meta = meta
+}
+{{- end }}
+
+func (r *{{ .TypeName }}) Create(ctx context.Context, req resource.CreateRequest, resp *resource.CreateResponse) {
+	var plan {{ .ModelName }}
+	resp.Diagnostics.Append(req.Plan.Get(ctx, &plan)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+	{{- with index .OperationTimeouts "create" }}
+
+	createTimeout, diags := plan.Timeouts.Create(ctx, {{ . }})
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+	ctx, cancel := context.WithTimeout(ctx, createTimeout)
+	defer cancel()
+	{{- end }}
+
+	{{- if .Create }}
+
+	{{ .Create }}
+	{{- else }}
+
+	// TODO(migrate): port the create logic of the SDK resource.
+	{{- end }}
+
+	resp.Diagnostics.Append(resp.State.Set(ctx, &plan)...)
+}
+
+func (r *{{ .TypeName }}) Read(ctx context.Context, req resource.ReadRequest, resp *resource.ReadResponse) {
+	var state {{ .ModelName }}
+	resp.Diagnostics.Append(req.State.Get(ctx, &state)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+	{{- with index .OperationTimeouts "read" }}
+
+	readTimeout, diags := state.Timeouts.Read(ctx, {{ . }})
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+	ctx, cancel := context.WithTimeout(ctx, readTimeout)
+	defer cancel()
+	{{- end }}
+
+	{{- if .Read }}
+
+	{{ .Read }}
+	{{- else }}
+
+	// TODO(migrate): port the read logic of the SDK resource.
+	{{- end }}
+
+	resp.Diagnostics.Append(resp.State.Set(ctx, &state)...)
+}
+
+func (r *{{ .TypeName }}) Update(ctx context.Context, req resource.UpdateRequest, resp *resource.UpdateResponse) {
+	var plan {{ .ModelName }}
+	resp.Diagnostics.Append(req.Plan.Get(ctx, &plan)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+	{{- with index .OperationTimeouts "update" }}
+
+	updateTimeout, diags := plan.Timeouts.Update(ctx, {{ . }})
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+	ctx, cancel := context.WithTimeout(ctx, updateTimeout)
+	defer cancel()
+	{{- end }}
+
+	{{- if .Update }}
+
+	{{ .Update }}
+	{{- else }}
+
+	// TODO(migrate): port the update logic of the SDK resource.
+	{{- end }}
+
+	resp.Diagnostics.Append(resp.State.Set(ctx, &plan)...)
+}
+
+func (r *{{ .TypeName }}) Delete(ctx context.Context, req resource.DeleteRequest, resp *resource.DeleteResponse) {
+	var state {{ .ModelName }}
+	resp.Diagnostics.Append(req.State.Get(ctx, &state)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+	{{- with index .OperationTimeouts "delete" }}
+
+	deleteTimeout, diags := state.Timeouts.Delete(ctx, {{ . }})
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+	ctx, cancel := context.WithTimeout(ctx, deleteTimeout)
+	defer cancel()
+	{{- end }}
+
+	{{- if .Delete }}
+
+	{{ .Delete }}
+	{{- else }}
+
+	// TODO(migrate): port the delete logic of the SDK resource.
+	{{- end }}
+}
+{{- if .Importer }}
+
+func (r *{{ .TypeName }}) ImportState(ctx context.Context, req resource.ImportStateRequest, resp *resource.ImportStateResponse) {
+	{{- if eq .Importer "passthrough" }}
+	resource.ImportStatePassthroughID(ctx, path.Root("id"), req, resp)
+	{{- else if .ImportState }}
+	{{ .ImportState }}
+	{{- else }}
+	// TODO(migrate): port the importer of the SDK resource.
+	{{- end }}
+}
+{{- end }}
+{{- if .StateUpgraders }}
+
+func (r *{{ .TypeName }}) UpgradeState({{ if .UpgradeTimeouts }}ctx{{ else }}_{{ end }} context.Context) map[int64]resource.StateUpgrader {
+	return map[int64]resource.StateUpgrader{
+		{{- range .StateUpgraders }}
+		{{ .Version }}: {
+			PriorSchema: &schema.Schema{
+				Attributes: map[string]schema.Attribute{
+					{{- range .Attributes }}
+					"{{ .Name }}": {{ attrLiteral . }},
+					{{- end }}
+					{{- range .NestedAttributes }}
+					"{{ .Name }}": {{ blockLiteral . }},
+					{{- end }}
+					{{- if and .Timeouts $.TimeoutsAttribute }}
+					"timeouts": {{ .Timeouts }},
+					{{- end }}
+				},
+				Blocks: map[string]schema.Block{
+					{{- range .Blocks }}
+					"{{ .Name }}": {{ blockLiteral . }},
+					{{- end }}
+					{{- if and .Timeouts (not $.TimeoutsAttribute) }}
+					"timeouts": {{ .Timeouts }},
+					{{- end }}
+				},
+			},
+			StateUpgrader: func(ctx context.Context, req resource.UpgradeStateRequest, resp *resource.UpgradeStateResponse) {
+				{{- if .Body }}
+				{{ .Body }}
+				{{- else }}
+				// TODO(migrate): port the state upgrader of the SDK resource.
+				{{- end }}
+			},
+		},
+		{{- end }}
+	}
+}
+{{- end }}
+{{- if .ModifyPlan }}
+
+func (r *{{ .TypeName }}) ModifyPlan(ctx context.Context, req resource.ModifyPlanRequest, resp *resource.ModifyPlanResponse) {
+	{{ .ModifyPlan }}
+}
+{{- end }}
+{{- if .PlanFunctions }}
+
+{{ .PlanFunctions }}
+{{- end }}
+{{- if .Validators }}
+
+{{ .Validators }}
+{{- end }}
+`
+
+const dataSourceTemplate = `package {{ .Package }}
+
+import (
+	"context"
+	{{- range .StdImports }}
+	{{ . }}
+	{{- end }}
+
+	"github.com/hashicorp/terraform-plugin-framework/datasource"
+	"github.com/hashicorp/terraform-plugin-framework/datasource/schema"
+	{{- if .UseTypes }}
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	{{- end }}
+	{{- range .Imports }}
+	{{ . }}
+	{{- end }}
+)
+
+var _ datasource.DataSource = (*{{ .TypeName }})(nil)
+{{- if .MetaType }}
+var _ datasource.DataSourceWithConfigure = (*{{ .TypeName }})(nil)
+{{- end }}
+
+func init() {
+	{{ .Registry }} = append({{ .Registry }}, {{ .Constructor }})
+}
+
+func {{ .Constructor }}() datasource.DataSource {
+	return &{{ .TypeName }}{}
+}
+
+{{ if .MetaType -}}
+type {{ .TypeName }} struct {
+	meta {{ .MetaType }}
+}
+{{- else -}}
+type {{ .TypeName }} struct{}
+{{- end }}
+{{ range .Models }}
+type {{ .Name }} struct {
+	{{- range .Fields }}
+	{{ .Name }} {{ .Type }} ` + "`" + `tfsdk:"{{ .Tag }}"` + "`" + `
+	{{- end }}
+}
+{{ end }}
+func (d *{{ .TypeName }}) Metadata(_ context.Context, _ datasource.MetadataRequest, resp *datasource.MetadataResponse) {
+	resp.TypeName = "{{ .Name }}"
+}
+
+func (d *{{ .TypeName }}) Schema({{ if .Timeouts }}ctx{{ else }}_{{ end }} context.Context, _ datasource.SchemaRequest, resp *datasource.SchemaResponse) {
+	resp.Schema = schema.Schema{
+		Attributes: map[string]schema.Attribute{
+			{{- range .Attributes }}
+			"{{ .Name }}": {{ attrLiteral . }},
+			{{- end }}
+			{{- range .NestedAttributes }}
+			"{{ .Name }}": {{ blockLiteral . }},
+			{{- end }}
+			{{- if and .Timeouts .TimeoutsAttribute }}
+			"timeouts": {{ .Timeouts }},
+			{{- end }}
+		},
+		Blocks: map[string]schema.Block{
+			{{- range .Blocks }}
+			"{{ .Name }}": {{ blockLiteral . }},
+			{{- end }}
+			{{- if and .Timeouts (not .TimeoutsAttribute) }}
+			"timeouts": {{ .Timeouts }},
+			{{- end }}
+		},
+	}
+}
+{{- if .MetaType }}
+
+func (d *{{ .TypeName }}) Configure(_ context.Context, req datasource.ConfigureRequest, resp *datasource.ConfigureResponse) {
+	meta, ok := providerMeta(req.ProviderData, &resp.Diagnostics)
+	if !ok {
+		return
+	}
+	d.meta = meta
+}
+{{- end }}
+
+func (d *{{ .TypeName }}) Read(ctx context.Context, req datasource.ReadRequest, resp *datasource.ReadResponse) {
+	var data {{ .ModelName }}
+	resp.Diagnostics.Append(req.Config.Get(ctx, &data)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+	{{- with index .OperationTimeouts "read" }}
+
+	readTimeout, diags := data.Timeouts.Read(ctx, {{ . }})
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+	ctx, cancel := context.WithTimeout(ctx, readTimeout)
+	defer cancel()
+	{{- end }}
+
+	{{- if .Read }}
+
+	{{ .Read }}
+	{{- else }}
+
+	// TODO(migrate): port the read logic of the SDK data source.
+	{{- end }}
+
+	resp.Diagnostics.Append(resp.State.Set(ctx, &data)...)
+}
+{{- if .Validators }}
+
+{{ .Validators }}
+{{- end }}
 `
 
 const mainTemplate = `{{- if .BuildTags }}{{ join .BuildTags "\n" }}{{ "\n\n" }}{{- end -}}
@@ -251,12 +1298,19 @@ import (
 	"context"
 	"log"
 
-	"{{ .FrameworkImport }}"
+	{{ with .FrameworkImportName }}{{ . }} {{ end }}"{{ .FrameworkImport }}"
 	"{{ .ProviderImport }}"
 	"github.com/hashicorp/terraform-plugin-framework/providerserver"
 	"github.com/hashicorp/terraform-plugin-go/tfprotov5"
+	{{- if eq .ProtocolVersion 6 }}
+	"github.com/hashicorp/terraform-plugin-go/tfprotov6"
+	"github.com/hashicorp/terraform-plugin-go/tfprotov6/tf6server"
+	"github.com/hashicorp/terraform-plugin-mux/tf5to6server"
+	"github.com/hashicorp/terraform-plugin-mux/tf6muxserver"
+	{{- else }}
 	"github.com/hashicorp/terraform-plugin-go/tfprotov5/tf5server"
 	"github.com/hashicorp/terraform-plugin-mux/tf5muxserver"
+	{{- end }}
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
 )
 
@@ -268,11 +1322,38 @@ func main() {
 	primary := {{ .ProviderAlias }}.Provider()
 
 	ctx := context.Background()
+	{{- if eq .ProtocolVersion 6 }}
+	upgradedSdkServer, err := tf5to6server.UpgradeServer(ctx,
+		func() tfprotov5.ProviderServer {
+			return schema.NewGRPCProviderServer(primary)
+		},
+	)
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	muxServer, err := tf6muxserver.NewMuxServer(ctx,
+		func() tfprotov6.ProviderServer {
+			return upgradedSdkServer
+		},
+		providerserver.NewProtocol6({{ .FrameworkAlias }}.New(primary)),
+	)
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	goServeOpts := []tf6server.ServeOpt{}
+	err = tf6server.Serve(
+		"{{ .Registry }}",
+		muxServer.ProviderServer,
+		goServeOpts...,
+	)
+	{{- else }}
 	muxServer, err := tf5muxserver.NewMuxServer(ctx,
 		func() tfprotov5.ProviderServer {
 			return schema.NewGRPCProviderServer(primary)
 		},
-		providerserver.NewProtocol5(framework.New(primary)),
+		providerserver.NewProtocol5({{ .FrameworkAlias }}.New(primary)),
 	)
 	if err != nil {
 		log.Fatal(err)
@@ -284,6 +1365,7 @@ func main() {
 		muxServer.ProviderServer,
 		goServeOpts...,
 	)
+	{{- end }}
 	if err != nil {
 		log.Fatal(err)
 	}
@@ -0,0 +1,458 @@
+package migrate
+
+import (
+	"bytes"
+	"fmt"
+	"go/ast"
+	"go/format"
+	"go/token"
+	"go/types"
+	"os"
+	"sort"
+	"strings"
+)
+
+// ResourceInfo is the parsed form of an SDK resource or data source.
+type ResourceInfo struct {
+	Name       string
+	DataSource bool
+	Attributes []Attribute
+	Blocks     []Block
+	// ImplicitID is set when the id attribute was added because the SDK
+	// schema does not declare it.
+	ImplicitID bool
+	// Mappings lists the SDK schema fields translated to plan modifiers,
+	// defaults, custom types and timeouts.
+	Mappings []SchemaMapping
+	// Timeouts holds the default timeout of each operation declared by the
+	// SDK Timeouts field, keyed by operation, as a Go duration expression.
+	Timeouts map[string]string
+	// SchemaVersion and StateUpgrades are the SDK schema version and state
+	// upgraders of a resource.
+	SchemaVersion int64
+	StateUpgrades []StateUpgrade
+	// Importer is importerPassthrough or importerCustom when the SDK
+	// resource is importable, and empty otherwise.
+	Importer string
+	// CustomizeDiff lists the rules of the SDK CustomizeDiff of a resource.
+	CustomizeDiff []DiffRule
+
+	// importer is the custom SDK import function.
+	importer crudFunc
+	// crud holds the SDK functions the framework CRUD methods are ported
+	// from, keyed by operation ("create", "read", "update", "delete").
+	crud map[string]crudFunc
+}
+
+// crudFunc is an SDK CRUD function referenced from a schema.Resource literal,
+// either a function declared in the module or a function literal. file is nil
+// when the function could not be found.
+type crudFunc struct {
+	field string
+	name  string
+	file  *ast.File
+	node  ast.Node
+}
+
+// crudFields lists the schema.Resource fields each framework CRUD method is
+// ported from, in order of preference.
+var crudFields = []struct {
+	op     string
+	fields []string
+}{
+	{"create", []string{"CreateContext", "CreateWithoutTimeout", "Create"}},
+	{"read", []string{"ReadContext", "ReadWithoutTimeout", "Read"}},
+	{"update", []string{"UpdateContext", "UpdateWithoutTimeout", "Update"}},
+	{"delete", []string{"DeleteContext", "DeleteWithoutTimeout", "Delete"}},
+}
+
+func parseResource(ref ResourceRef, dataSource bool, res resolver) (ResourceInfo, error) {
+	lit, err := resolveResourceLiteral(ref.value, res)
+	if err != nil {
+		return ResourceInfo{}, err
+	}
+
+	attrs, blocks, err := parseResourceSchema(lit, res)
+	if err != nil {
+		return ResourceInfo{}, err
+	}
+
+	info := ResourceInfo{
+		Name:       ref.Name,
+		DataSource: dataSource,
+		Attributes: attrs,
+		Blocks:     blocks,
+		crud:       parseCRUDFuncs(lit, res),
+	}
+	addImplicitID(&info)
+	applyCustomTypes(&info)
+	applySensitiveBlocks(&info)
+	applyUnevaluatedDescriptions(&info)
+	applyConditionalSchema(&info)
+	if !dataSource {
+		applyResourceMappings(&info)
+	}
+	parseTimeouts(lit, &info, res)
+	if !dataSource {
+		parseImporter(lit, &info, res)
+		parseCustomizeDiff(lit, &info, res)
+		if err := parseStateUpgraders(lit, &info, res); err != nil {
+			return ResourceInfo{}, err
+		}
+	}
+	return info, nil
+}
+
+// addImplicitID declares the id attribute every SDK resource and data source
+// has without listing it in its schema. Framework schemas must declare it, or
+// configurations and state referencing .id break.
+func addImplicitID(info *ResourceInfo) {
+	id := Attribute{Name: "id", Type: "string", Computed: true}
+	if !info.DataSource {
+		id.PlanModifiers = []string{"stringplanmodifier.UseStateForUnknown()"}
+	}
+	info.Attributes, info.ImplicitID = withImplicitID(info.Attributes, id)
+}
+
+// withImplicitID prepends id to attrs unless they declare an id attribute,
+// and reports whether it did.
+func withImplicitID(attrs []Attribute, id Attribute) ([]Attribute, bool) {
+	for _, attr := range attrs {
+		if attr.Name == "id" {
+			return attrs, false
+		}
+	}
+	return append([]Attribute{id}, attrs...), true
+}
+
+func parseCRUDFuncs(lit *ast.CompositeLit, res resolver) map[string]crudFunc {
+	values := map[string]ast.Expr{}
+	for _, elt := range lit.Elts {
+		kv, ok := elt.(*ast.KeyValueExpr)
+		if !ok {
+			continue
+		}
+		if key, ok := kv.Key.(*ast.Ident); ok {
+			values[key.Name] = kv.Value
+		}
+	}
+
+	funcs := map[string]crudFunc{}
+	for _, op := range crudFields {
+		for _, field := range op.fields {
+			value, ok := values[field]
+			if !ok {
+				continue
+			}
+			funcs[op.op] = resolveFunc(field, value, res)
+			break
+		}
+	}
+	return funcs
+}
+
+// resolveFunc finds the function a schema.Resource field refers to: a
+// function literal or a function declared in the module.
+func resolveFunc(field string, value ast.Expr, res resolver) crudFunc {
+	fn := crudFunc{field: field}
+	switch v := value.(type) {
+	case *ast.FuncLit:
+		fn.node = v
+	case *ast.Ident:
+		fn.name = v.Name
+		if decl, ok := res.funcs[v.Name]; ok && decl.Body != nil {
+			fn.node = decl
+		}
+	default:
+		fn.name = types.ExprString(value)
+	}
+	if fn.node != nil {
+		fn.file = res.files[res.fset.File(fn.node.Pos()).Name()]
+	}
+	return fn
+}
+
+var (
+	numericIDParsers    = map[string]bool{"Atoi": true, "ParseInt": true, "ParseUint": true, "ParseFloat": true}
+	numericIDFormatters = map[string]bool{"Itoa": true, "FormatInt": true, "FormatUint": true, "FormatFloat": true}
+)
+
+// idFormatNotes warns about resources whose ID is not a plain string: an
+// explicit non-string id attribute, or CRUD functions converting the ID to
+// and from a number with strconv.
+func idFormatNotes(info ResourceInfo) []string {
+	kind := "resource"
+	if info.DataSource {
+		kind = "data source"
+	}
+
+	var notes []string
+	for _, attr := range info.Attributes {
+		if attr.Name == "id" && attr.Type != "string" {
+			notes = append(notes, fmt.Sprintf("%s %s: declares id as %s; framework resources need a string id", kind, info.Name, attr.Type))
+		}
+	}
+
+	for _, op := range crudFields {
+		fn, ok := info.crud[op.op]
+		if !ok || fn.node == nil {
+			continue
+		}
+		var fnType *ast.FuncType
+		switch node := fn.node.(type) {
+		case *ast.FuncDecl:
+			fnType = node.Type
+		case *ast.FuncLit:
+			fnType = node.Type
+		}
+		data := resourceDataParam(fnType)
+		if data == "" {
+			continue
+		}
+		where := fn.name
+		if where == "" {
+			where = fn.field
+		}
+
+		ast.Inspect(fn.node, func(node ast.Node) bool {
+			call, ok := node.(*ast.CallExpr)
+			if !ok || len(call.Args) == 0 {
+				return true
+			}
+			if fnName, ok := strconvCall(call); ok && numericIDParsers[fnName] && isMethodCall(call.Args[0], data, "Id") {
+				notes = append(notes, fmt.Sprintf("%s %s: %s parses %s.Id() with strconv.%s; the framework id is a string, keep the conversion when porting", kind, info.Name, where, data, fnName))
+			}
+			if isMethodCall(call, data, "SetId") {
+				if inner, ok := call.Args[0].(*ast.CallExpr); ok {
+					if fnName, ok := strconvCall(inner); ok && numericIDFormatters[fnName] {
+						notes = append(notes, fmt.Sprintf("%s %s: %s sets a numeric ID with strconv.%s; the framework id is a string, keep the conversion when porting", kind, info.Name, where, fnName))
+					}
+				}
+			}
+			return true
+		})
+	}
+	return notes
+}
+
+func resourceDataParam(fnType *ast.FuncType) string {
+	if fnType == nil || fnType.Params == nil {
+		return ""
+	}
+	for _, field := range fnType.Params.List {
+		if types.ExprString(field.Type) == "*schema.ResourceData" && len(field.Names) > 0 {
+			return field.Names[0].Name
+		}
+	}
+	return ""
+}
+
+func strconvCall(call *ast.CallExpr) (string, bool) {
+	sel, ok := call.Fun.(*ast.SelectorExpr)
+	if !ok {
+		return "", false
+	}
+	pkg, ok := sel.X.(*ast.Ident)
+	if !ok || pkg.Name != "strconv" {
+		return "", false
+	}
+	return sel.Sel.Name, true
+}
+
+// isMethodCall reports whether expr calls method on the variable recv.
+func isMethodCall(expr ast.Expr, recv, method string) bool {
+	call, ok := expr.(*ast.CallExpr)
+	if !ok {
+		return false
+	}
+	sel, ok := call.Fun.(*ast.SelectorExpr)
+	if !ok || sel.Sel.Name != method {
+		return false
+	}
+	ident, ok := sel.X.(*ast.Ident)
+	return ok && ident.Name == recv
+}
+
+// resolveResourceLiteral follows a ResourcesMap value to the
+// schema.Resource literal it produces, either inline or returned from a
+// function in the same module.
+func resolveResourceLiteral(expr ast.Expr, res resolver) (*ast.CompositeLit, error) {
+	switch v := expr.(type) {
+	case *ast.UnaryExpr:
+		if v.Op == token.AND {
+			return resolveResourceLiteral(v.X, res)
+		}
+	case *ast.CompositeLit:
+		if isSchemaResourceType(v.Type) {
+			return v, nil
+		}
+	case *ast.CallExpr:
+		fnName := types.ExprString(v.Fun)
+		fn := res.lookupFunc(v.Fun)
+		if fn == nil || fn.Body == nil {
+			return nil, fmt.Errorf("resource function %q not resolved", fnName)
+		}
+		for _, stmt := range fn.Body.List {
+			ret, ok := stmt.(*ast.ReturnStmt)
+			if !ok || len(ret.Results) == 0 {
+				continue
+			}
+			if ident, ok := ret.Results[0].(*ast.Ident); ok {
+				if lit := findLocalCompositeLiteral(fn, ident.Name); lit != nil && isSchemaResourceType(lit.Type) {
+					return lit, nil
+				}
+				continue
+			}
+			return resolveResourceLiteral(ret.Results[0], res)
+		}
+		return nil, fmt.Errorf("resource function %q does not return a schema.Resource literal", fnName)
+	}
+
+	return nil, fmt.Errorf("resource is not a schema.Resource literal or function call")
+}
+
+// selectResources returns the registry entries named in names, or every
+// entry not listed in skip when all is set.
+func selectResources(kind string, registered []ResourceRef, names, skip []string, all bool) ([]ResourceRef, error) {
+	skipped := make(map[string]bool, len(skip))
+	for _, name := range skip {
+		skipped[name] = true
+	}
+
+	if all {
+		var selected []ResourceRef
+		for _, ref := range registered {
+			if !skipped[ref.Name] {
+				selected = append(selected, ref)
+			}
+		}
+		return selected, nil
+	}
+
+	byName := make(map[string]ResourceRef, len(registered))
+	for _, ref := range registered {
+		byName[ref.Name] = ref
+	}
+
+	selected := make([]ResourceRef, 0, len(names))
+	for _, name := range names {
+		ref, ok := byName[name]
+		if !ok {
+			return nil, fmt.Errorf("%s %q is not registered by the provider", kind, name)
+		}
+		if skipped[name] {
+			return nil, fmt.Errorf("%s %q is on the skip list", kind, name)
+		}
+		selected = append(selected, ref)
+	}
+	return selected, nil
+}
+
+// removeRegistryEntries deletes the given ResourcesMap/DataSourcesMap entries,
+// or the index assignments adding them, from the SDK provider source, so mux
+// does not see the same type served by both providers. It returns the
+// rewritten content per file.
+func removeRegistryEntries(fset *token.FileSet, refs []ResourceRef) (map[string][]byte, error) {
+	byFile := map[string][]ast.Node{}
+	for _, ref := range refs {
+		file := fset.Position(ref.node.Pos()).Filename
+		byFile[file] = append(byFile[file], ref.node)
+	}
+
+	edited := make(map[string][]byte, len(byFile))
+	for file, entries := range byFile {
+		src, err := os.ReadFile(file)
+		if err != nil {
+			return nil, err
+		}
+
+		sort.Slice(entries, func(i, j int) bool {
+			return entries[i].Pos() > entries[j].Pos()
+		})
+		for _, entry := range entries {
+			start := fset.Position(entry.Pos()).Offset
+			end := fset.Position(entry.End()).Offset
+			start, end = widenToLine(src, start, end)
+			src = append(src[:start:start], src[end:]...)
+		}
+
+		formatted, err := format.Source(src)
+		if err != nil {
+			return nil, fmt.Errorf("%s: %w", file, err)
+		}
+		edited[file] = formatted
+	}
+
+	return edited, nil
+}
+
+// widenToLine extends [start, end) over a trailing comma and, when the entry
+// is alone on its line, over the whole line.
+func widenToLine(src []byte, start, end int) (int, int) {
+	if end < len(src) && src[end] == ',' {
+		end++
+	}
+
+	lineStart := start
+	for lineStart > 0 && (src[lineStart-1] == ' ' || src[lineStart-1] == '\t') {
+		lineStart--
+	}
+	lineEnd := end
+	for lineEnd < len(src) && (src[lineEnd] == ' ' || src[lineEnd] == '\t') {
+		lineEnd++
+	}
+	if (lineStart == 0 || src[lineStart-1] == '\n') && lineEnd < len(src) && src[lineEnd] == '\n' {
+		return lineStart, lineEnd + 1
+	}
+	return start, end
+}
+
+// resourceTypeBase strips the provider prefix from a resource type name,
+// e.g. "widget_rule" for "acme_widget_rule".
+func resourceTypeBase(name, providerName string) string {
+	if base := strings.TrimPrefix(name, providerName+"_"); base != name && base != "" {
+		return base
+	}
+	return name
+}
+
+var commonInitialisms = map[string]bool{
+	"acl": true, "api": true, "arn": true, "cidr": true, "cpu": true, "dns": true,
+	"http": true, "https": true, "id": true, "ip": true, "json": true, "sql": true,
+	"ssh": true, "tls": true, "ttl": true, "uri": true, "url": true, "uuid": true,
+}
+
+// goName converts a snake_case Terraform name to an exported Go identifier.
+func goName(name string) string {
+	var buf bytes.Buffer
+	for _, part := range nameParts(name) {
+		if commonInitialisms[part] {
+			buf.WriteString(strings.ToUpper(part))
+			continue
+		}
+		buf.WriteString(strings.ToUpper(part[:1]) + part[1:])
+	}
+	if buf.Len() == 0 {
+		return "Value"
+	}
+	return buf.String()
+}
+
+// lowerGoName is goName with the first word in lower case, for unexported
+// identifiers.
+func lowerGoName(name string) string {
+	parts := nameParts(name)
+	if len(parts) == 0 {
+		return "value"
+	}
+	if len(parts) == 1 {
+		return strings.ToLower(parts[0])
+	}
+	return strings.ToLower(parts[0]) + goName(strings.Join(parts[1:], "_"))
+}
+
+func nameParts(name string) []string {
+	return strings.FieldsFunc(strings.ToLower(name), func(r rune) bool {
+		return r == '_' || r == '-' || r == '.'
+	})
+}
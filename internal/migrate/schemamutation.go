@@ -0,0 +1,355 @@
+package migrate
+
+import (
+	"fmt"
+	"go/ast"
+	"go/token"
+	"go/types"
+	"maps"
+	"strconv"
+)
+
+// schemaMutations follows the statements of a function that build a local
+// schema map after its declaration: index assignments, delete, maps.Copy,
+// loops copying another schema map, and module functions the map is passed
+// to. Entries set or deleted inside an if, switch or loop are marked
+// Conditional; a loop over a string slice literal is unrolled, with the
+// loop variable bound to each element. Any other statement changing the map
+// is an error.
+type schemaMutations struct {
+	res     resolver
+	attrs   []Attribute
+	blocks  []Block
+	visited map[*ast.FuncDecl]bool
+	// bindings holds the value of the loop variables of the loops being
+	// unrolled.
+	bindings map[string]string
+}
+
+// parseLocalSchemaMap reads the local schema map name of body, including
+// the changes made to it before the return statement. It reports false when
+// body does not declare name.
+func parseLocalSchemaMap(body *ast.BlockStmt, name string, res resolver) ([]Attribute, []Block, bool, error) {
+	m := &schemaMutations{res: res, visited: map[*ast.FuncDecl]bool{}, bindings: map[string]string{}}
+	declared := false
+	for _, stmt := range body.List {
+		if _, ok := stmt.(*ast.ReturnStmt); ok && declared {
+			break
+		}
+		if !declared {
+			value := localValue(stmt, name)
+			if value == nil {
+				continue
+			}
+			declared = true
+			if call, ok := value.(*ast.CallExpr); ok && isIdent(call.Fun, "make") {
+				continue
+			}
+			attrs, blocks, err := parseSchemaMapExpr(value, res)
+			if err != nil {
+				return nil, nil, true, err
+			}
+			m.attrs, m.blocks = attrs, blocks
+			continue
+		}
+		if err := m.stmt(stmt, name, false); err != nil {
+			return nil, nil, true, err
+		}
+	}
+	return m.attrs, m.blocks, declared, nil
+}
+
+func (m *schemaMutations) stmt(stmt ast.Stmt, name string, conditional bool) error {
+	if !mutatesMap(stmt, name) {
+		return nil
+	}
+
+	switch s := stmt.(type) {
+	case *ast.AssignStmt:
+		if len(s.Lhs) == 1 && len(s.Rhs) == 1 {
+			if index, ok := s.Lhs[0].(*ast.IndexExpr); ok && isIdent(index.X, name) {
+				key, ok := m.key(index.Index)
+				if !ok {
+					return m.notFollowed(stmt, name, "the key is not a constant string")
+				}
+				attr, block, err := parseSchemaAttribute(key, s.Rhs[0], m.scope())
+				if err != nil {
+					return err
+				}
+				m.set(attr, block, m.conditional(conditional, stmt))
+				return nil
+			}
+		}
+	case *ast.ExprStmt:
+		call, ok := s.X.(*ast.CallExpr)
+		if !ok {
+			break
+		}
+		switch {
+		case isIdent(call.Fun, "delete") && len(call.Args) == 2 && isIdent(call.Args[0], name):
+			key, ok := m.key(call.Args[1])
+			if !ok {
+				return m.notFollowed(stmt, name, "the key is not a constant string")
+			}
+			m.remove(key, m.conditional(conditional, stmt))
+			return nil
+		case m.res.isMapsCopy(call) && len(call.Args) == 2 && isIdent(call.Args[0], name):
+			return m.merge(call.Args[1], m.conditional(conditional, stmt))
+		}
+		if fn := m.res.lookupFunc(call.Fun); fn != nil && fn.Body != nil {
+			return m.call(call, fn, name, conditional)
+		}
+	case *ast.RangeStmt:
+		if src, ok := copyLoop(s, name); ok {
+			return m.merge(src, m.conditional(conditional, stmt))
+		}
+		if values, ok := m.res.evalStrings(s.X); ok && (s.Key == nil || isIdent(s.Key, "_")) {
+			return m.unroll(s, values, name)
+		}
+		return m.block(s.Body, name)
+	case *ast.ForStmt:
+		return m.block(s.Body, name)
+	case *ast.IfStmt:
+		if err := m.block(s.Body, name); err != nil {
+			return err
+		}
+		if s.Else != nil {
+			return m.stmt(s.Else, name, true)
+		}
+		return nil
+	case *ast.SwitchStmt:
+		return m.block(s.Body, name)
+	case *ast.TypeSwitchStmt:
+		return m.block(s.Body, name)
+	case *ast.CaseClause:
+		for _, nested := range s.Body {
+			if err := m.stmt(nested, name, true); err != nil {
+				return err
+			}
+		}
+		return nil
+	case *ast.BlockStmt:
+		for _, nested := range s.List {
+			if err := m.stmt(nested, name, conditional); err != nil {
+				return err
+			}
+		}
+		return nil
+	}
+	return m.notFollowed(stmt, name, "the schema cannot be derived statically")
+}
+
+// block follows the statements of the body of an if, switch or loop, which
+// may run any number of times.
+func (m *schemaMutations) block(body *ast.BlockStmt, name string) error {
+	for _, stmt := range body.List {
+		if err := m.stmt(stmt, name, true); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// unroll follows the body of a loop over values once per element, with the
+// loop variable bound to the element.
+func (m *schemaMutations) unroll(s *ast.RangeStmt, values []string, name string) error {
+	value, _ := s.Value.(*ast.Ident)
+	if value == nil || value.Name == "_" {
+		return m.block(s.Body, name)
+	}
+	prev, bound := m.bindings[value.Name]
+	defer func() {
+		if bound {
+			m.bindings[value.Name] = prev
+		} else {
+			delete(m.bindings, value.Name)
+		}
+	}()
+	for _, v := range values {
+		m.bindings[value.Name] = v
+		if err := m.block(s.Body, name); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// key evaluates a key of the schema map, a constant string that may refer
+// to the variables of the loops being unrolled.
+func (m *schemaMutations) key(expr ast.Expr) (string, bool) {
+	return m.scope().evalString(expr)
+}
+
+// scope returns the resolver with the variables of the loops being unrolled
+// bound as string constants.
+func (m *schemaMutations) scope() resolver {
+	if len(m.bindings) == 0 {
+		return m.res
+	}
+	res := m.res
+	res.consts = maps.Clone(m.res.consts)
+	for name, value := range m.bindings {
+		res.consts[name] = &ast.BasicLit{Kind: token.STRING, Value: strconv.Quote(value)}
+	}
+	return res
+}
+
+// call follows a module function the schema map is passed to, as the
+// parameter at the same position.
+func (m *schemaMutations) call(call *ast.CallExpr, fn *ast.FuncDecl, name string, conditional bool) error {
+	param := ""
+	i := 0
+	for _, field := range fn.Type.Params.List {
+		for _, ident := range field.Names {
+			if i < len(call.Args) && isIdent(call.Args[i], name) {
+				param = ident.Name
+			}
+			i++
+		}
+	}
+	if param == "" || param == "_" {
+		return m.notFollowed(call, name, "the function does not take the schema map as a named parameter")
+	}
+	if m.visited[fn] {
+		return nil
+	}
+	m.visited[fn] = true
+	defer delete(m.visited, fn)
+	// The loop variables of the caller are not in scope in fn.
+	bindings := m.bindings
+	m.bindings = map[string]string{}
+	defer func() { m.bindings = bindings }()
+	for _, stmt := range fn.Body.List {
+		if err := m.stmt(stmt, param, conditional); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (m *schemaMutations) merge(src ast.Expr, conditional token.Position) error {
+	attrs, blocks, err := parseSchemaMapExpr(src, m.res)
+	if err != nil {
+		return err
+	}
+	for _, attr := range attrs {
+		m.set(attr, nil, conditional)
+	}
+	for i := range blocks {
+		m.set(Attribute{}, &blocks[i], conditional)
+	}
+	return nil
+}
+
+// conditional returns the position of stmt when it only runs under a
+// condition, and the zero position otherwise.
+func (m *schemaMutations) conditional(conditional bool, stmt ast.Node) token.Position {
+	if !conditional {
+		return token.Position{}
+	}
+	return m.res.fset.Position(stmt.Pos())
+}
+
+// set adds an attribute or block, replacing an entry of the same name.
+func (m *schemaMutations) set(attr Attribute, block *Block, conditional token.Position) {
+	name := attr.Name
+	if block != nil {
+		name = block.Name
+	}
+	m.removeEntry(name)
+	if block != nil {
+		block.Conditional = conditional
+		m.blocks = append(m.blocks, *block)
+		return
+	}
+	attr.Conditional = conditional
+	m.attrs = append(m.attrs, attr)
+}
+
+// remove deletes an entry, or marks it Conditional when it is only deleted
+// under a condition.
+func (m *schemaMutations) remove(name string, conditional token.Position) {
+	if !conditional.IsValid() {
+		m.removeEntry(name)
+		return
+	}
+	for i := range m.attrs {
+		if m.attrs[i].Name == name {
+			m.attrs[i].Conditional = conditional
+		}
+	}
+	for i := range m.blocks {
+		if m.blocks[i].Name == name {
+			m.blocks[i].Conditional = conditional
+		}
+	}
+}
+
+func (m *schemaMutations) removeEntry(name string) {
+	attrs := m.attrs[:0]
+	for _, attr := range m.attrs {
+		if attr.Name != name {
+			attrs = append(attrs, attr)
+		}
+	}
+	m.attrs = attrs
+	blocks := m.blocks[:0]
+	for _, block := range m.blocks {
+		if block.Name != name {
+			blocks = append(blocks, block)
+		}
+	}
+	m.blocks = blocks
+}
+
+func (m *schemaMutations) notFollowed(node ast.Node, name, why string) error {
+	var summary string
+	switch n := node.(type) {
+	case ast.Stmt:
+		summary = statementSummary(n)
+	case ast.Expr:
+		summary = types.ExprString(n)
+	}
+	return fmt.Errorf("schema map %s: %s at %s not followed: %s", name, summary, m.res.fset.Position(node.Pos()), why)
+}
+
+// applyConditionalSchema records the attributes and blocks of info that the
+// SDK schema only declares under a condition in the mappings.
+func applyConditionalSchema(info *ResourceInfo) {
+	walkConditionalSchema(info.Attributes, info.Blocks, func(path string, pos token.Position) {
+		info.Mappings = append(info.Mappings, SchemaMapping{
+			Path:     path,
+			Field:    "schema map",
+			Position: pos,
+			Result:   "non-deterministic schema: set or deleted inside an if, switch or loop, the framework schema always declares it (mux requires a static schema)",
+		})
+	})
+}
+
+// conditionalSchemaNotes warns about the attributes and blocks a schema only
+// declares under a condition, e.g. for scope "resource example_widget".
+func conditionalSchemaNotes(scope string, attrs []Attribute, blocks []Block) []string {
+	var notes []string
+	walkConditionalSchema(attrs, blocks, func(path string, _ token.Position) {
+		notes = append(notes, fmt.Sprintf("%s: %s is set or deleted inside an if, switch or loop, a non-deterministic schema that mux rejects; the framework schema always declares it", scope, path))
+	})
+	return notes
+}
+
+func walkConditionalSchema(attrs []Attribute, blocks []Block, visit func(path string, pos token.Position)) {
+	var walk func(prefix string, attrs []Attribute, blocks []Block)
+	walk = func(prefix string, attrs []Attribute, blocks []Block) {
+		for _, attr := range attrs {
+			if attr.Conditional.IsValid() {
+				visit(prefix+attr.Name, attr.Conditional)
+			}
+		}
+		for _, block := range blocks {
+			if block.Conditional.IsValid() {
+				visit(prefix+block.Name, block.Conditional)
+			}
+			walk(prefix+block.Name+".", block.Attributes, block.Blocks)
+		}
+	}
+	walk("", attrs, blocks)
+}
@@ -0,0 +1,184 @@
+package migrate
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestSchemaMutations(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name string
+		body string
+		// want lists the attributes as name:type and the blocks as
+		// name{}, with a ? suffix for Conditional entries.
+		want    []string
+		wantErr string
+	}{
+		{
+			name: "index assignment and delete",
+			body: `s := map[string]*schema.Schema{
+		"a": {Type: schema.TypeString, Optional: true},
+		"b": {Type: schema.TypeInt, Optional: true},
+	}
+	s["c"] = &schema.Schema{Type: schema.TypeBool, Computed: true}
+	s["a"] = &schema.Schema{Type: schema.TypeInt, Required: true}
+	delete(s, "b")`,
+			want: []string{"c:bool", "a:int"},
+		},
+		{
+			name: "conditional",
+			body: `s := map[string]*schema.Schema{
+		"a": {Type: schema.TypeString, Optional: true},
+		"b": {Type: schema.TypeString, Optional: true},
+	}
+	if enabled {
+		s["c"] = &schema.Schema{Type: schema.TypeString, Optional: true}
+	} else {
+		delete(s, "b")
+	}`,
+			want: []string{"a:string", "b:string?", "c:string?"},
+		},
+		{
+			name: "maps.Copy and copy loop",
+			body: `s := map[string]*schema.Schema{}
+	maps.Copy(s, baseSchema())
+	for k, v := range extraSchema() {
+		s[k] = v
+	}`,
+			want: []string{"base:string", "extra:int"},
+		},
+		{
+			name: "module function",
+			body: `s := map[string]*schema.Schema{}
+	addCommon(s)`,
+			want: []string{"common:string"},
+		},
+		{
+			name: "block",
+			body: `s := map[string]*schema.Schema{}
+	s["rule"] = &schema.Schema{
+		Type:     schema.TypeList,
+		Optional: true,
+		Elem: &schema.Resource{Schema: map[string]*schema.Schema{
+			"cidr": {Type: schema.TypeString, Required: true},
+		}},
+	}`,
+			want: []string{"rule{}"},
+		},
+		{
+			name: "loop over a package level string slice",
+			body: `s := map[string]*schema.Schema{}
+	for _, feature := range features {
+		s[feature] = &schema.Schema{Type: schema.TypeBool, Computed: true}
+		s[feature+"_note"] = &schema.Schema{Type: schema.TypeString, Computed: true}
+	}`,
+			want: []string{"alpha:bool?", "alpha_note:string?", "gamma:bool?", "gamma_note:string?"},
+		},
+		{
+			name: "loop over a string slice literal",
+			body: `s := map[string]*schema.Schema{"x": {Type: schema.TypeString, Optional: true}}
+	for _, name := range []string{"x"} {
+		delete(s, name)
+	}`,
+			want: []string{"x:string?"},
+		},
+		{
+			name: "loop variable is not in scope of a called function",
+			body: `s := map[string]*schema.Schema{}
+	for _, name := range features {
+		addNamed(s)
+	}`,
+			wantErr: "schema map m: m[name] = &schema.Schema{…} at ",
+		},
+		{
+			name: "loop over a slice that is not constant",
+			body: `s := map[string]*schema.Schema{}
+	for _, name := range names() {
+		s[name] = &schema.Schema{Type: schema.TypeString, Optional: true}
+	}`,
+			wantErr: "not followed: the key is not a constant string",
+		},
+		{
+			name: "other change",
+			body: `s := map[string]*schema.Schema{}
+	s["a"].Optional = true`,
+			wantErr: "not followed: the schema cannot be derived statically",
+		},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			res := testResolver(t, map[string]string{"schema.go": `package provider
+
+import (
+	"maps"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+)
+
+var enabled = true
+
+var features = []string{"alpha", "gamma"}
+
+func names() []string {
+	return features
+}
+
+func baseSchema() map[string]*schema.Schema {
+	return map[string]*schema.Schema{"base": {Type: schema.TypeString, Optional: true}}
+}
+
+func extraSchema() map[string]*schema.Schema {
+	return map[string]*schema.Schema{"extra": {Type: schema.TypeInt, Optional: true}}
+}
+
+func addCommon(m map[string]*schema.Schema) {
+	m["common"] = &schema.Schema{Type: schema.TypeString, Optional: true}
+}
+
+func addNamed(m map[string]*schema.Schema) {
+	m[name] = &schema.Schema{Type: schema.TypeString, Optional: true}
+}
+
+func thingSchema() map[string]*schema.Schema {
+	` + tt.body + `
+	return s
+}
+`})
+			attrs, blocks, err := parseSchemaMapFromFunc(res.funcs["thingSchema"], res)
+			if tt.wantErr != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+					t.Fatalf("expected an error containing %q, got %v", tt.wantErr, err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("parse schema map: %v", err)
+			}
+
+			var got []string
+			for _, attr := range attrs {
+				entry := attr.Name + ":" + attr.Type
+				if attr.Conditional.IsValid() {
+					entry += "?"
+				}
+				got = append(got, entry)
+			}
+			for _, block := range blocks {
+				entry := block.Name + "{}"
+				if block.Conditional.IsValid() {
+					entry += "?"
+				}
+				got = append(got, entry)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Fatalf("unexpected schema %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
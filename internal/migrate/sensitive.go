@@ -0,0 +1,57 @@
+package migrate
+
+import "fmt"
+
+// sensitiveSchema marks every attribute inside a Sensitive SDK block
+// Sensitive, including those of nested blocks, so that the values stay out of
+// plan output although the framework block itself cannot be sensitive.
+func sensitiveSchema(attrs []Attribute, blocks []Block) ([]Attribute, []Block) {
+	sensitiveAttrs := make([]Attribute, len(attrs))
+	for i, attr := range attrs {
+		attr.Sensitive = true
+		sensitiveAttrs[i] = attr
+	}
+	sensitiveBlocks := make([]Block, len(blocks))
+	for i, block := range blocks {
+		block.Attributes, block.Blocks = sensitiveSchema(block.Attributes, block.Blocks)
+		sensitiveBlocks[i] = block
+	}
+	return sensitiveAttrs, sensitiveBlocks
+}
+
+// applySensitiveBlocks records the Sensitive SDK blocks of info in the
+// mappings.
+func applySensitiveBlocks(info *ResourceInfo) {
+	var record func(prefix string, blocks []Block)
+	record = func(prefix string, blocks []Block) {
+		for _, block := range blocks {
+			if block.Sensitive {
+				info.Mappings = append(info.Mappings, SchemaMapping{
+					Path:     prefix + block.Name,
+					Field:    "Sensitive",
+					Position: block.Fields["Sensitive"],
+					Result:   "Sensitive on every nested attribute (framework blocks cannot be sensitive)",
+				})
+			}
+			record(prefix+block.Name+".", block.Blocks)
+		}
+	}
+	record("", info.Blocks)
+}
+
+// sensitiveBlockNotes warns about the Sensitive blocks of a schema, e.g.
+// for scope "resource example_widget".
+func sensitiveBlockNotes(scope string, blocks []Block) []string {
+	var notes []string
+	var walk func(prefix string, blocks []Block)
+	walk = func(prefix string, blocks []Block) {
+		for _, block := range blocks {
+			if block.Sensitive {
+				notes = append(notes, fmt.Sprintf("%s: block %s is Sensitive, which framework blocks do not support; every attribute inside it is marked Sensitive instead", scope, prefix+block.Name))
+			}
+			walk(prefix+block.Name+".", block.Blocks)
+		}
+	}
+	walk("", blocks)
+	return notes
+}
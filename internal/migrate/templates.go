@@ -0,0 +1,315 @@
+package migrate
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"text/template"
+)
+
+const (
+	frameworkTemplateFile     = "provider.go.tmpl"
+	frameworkTestTemplateFile = "provider_test.go.tmpl"
+	mainTemplateFile          = "main.go.tmpl"
+	registryTemplateFile      = "registry.go.tmpl"
+	resourceTemplateFile      = "resource.go.tmpl"
+	dataSourceTemplateFile    = "datasource.go.tmpl"
+)
+
+// defaultTemplates maps each template file name to the built-in template it
+// replaces when present in a --templates directory.
+var defaultTemplates = map[string]string{
+	frameworkTemplateFile:     frameworkTemplate,
+	frameworkTestTemplateFile: frameworkTestTemplate,
+	mainTemplateFile:          mainTemplate,
+	registryTemplateFile:      registryTemplate,
+	resourceTemplateFile:      resourceTemplate,
+	dataSourceTemplateFile:    dataSourceTemplate,
+}
+
+type templateSet struct {
+	framework     *template.Template
+	frameworkTest *template.Template
+	main          *template.Template
+	registry      *template.Template
+	resource      *template.Template
+	dataSource    *template.Template
+}
+
+// TemplateFuncs returns the functions available to every template. They are
+// part of the contract for user templates:
+//
+//   - attrLiteral Attribute: framework schema attribute literal, e.g. schema.StringAttribute{...}
+//   - metaAttrLiteral Attribute: framework meta schema attribute literal, e.g. metaschema.StringAttribute{...}
+//   - blockLiteral Block: framework nested block literal, e.g. schema.ListNestedBlock{...}
+//   - elementType Attribute: element type of a list, set or map attribute, e.g. types.StringType
+//   - join []string sep: strings.Join
+func TemplateFuncs() template.FuncMap {
+	return template.FuncMap{
+		"attrLiteral":     renderAttributeLiteral,
+		"metaAttrLiteral": renderMetaAttributeLiteral,
+		"blockLiteral":    renderBlockLiteral,
+		"elementType":     renderElementType,
+		"join":            strings.Join,
+	}
+}
+
+// loadTemplates returns the built-in templates, overridden by any template
+// file found in dir. An empty dir selects the built-in templates only.
+func loadTemplates(dir string) (templateSet, error) {
+	sources := make(map[string]string, len(defaultTemplates))
+	for name, text := range defaultTemplates {
+		sources[name] = text
+	}
+
+	if dir != "" {
+		entries, err := os.ReadDir(dir)
+		if err != nil {
+			return templateSet{}, fmt.Errorf("read templates: %w", err)
+		}
+		for _, entry := range entries {
+			if entry.IsDir() || !strings.HasSuffix(entry.Name(), ".tmpl") {
+				continue
+			}
+			if _, ok := defaultTemplates[entry.Name()]; !ok {
+				return templateSet{}, fmt.Errorf("unknown template %s in %s (expected one of %s)", entry.Name(), dir, strings.Join(templateNames(), ", "))
+			}
+			data, err := os.ReadFile(filepath.Join(dir, entry.Name()))
+			if err != nil {
+				return templateSet{}, err
+			}
+			sources[entry.Name()] = string(data)
+		}
+	}
+
+	parsed := make(map[string]*template.Template, len(sources))
+	for name, text := range sources {
+		tmpl, err := template.New(name).Funcs(TemplateFuncs()).Option("missingkey=error").Parse(text)
+		if err != nil {
+			return templateSet{}, fmt.Errorf("parse template: %w", err)
+		}
+		parsed[name] = tmpl
+	}
+
+	set := templateSet{
+		framework:     parsed[frameworkTemplateFile],
+		frameworkTest: parsed[frameworkTestTemplateFile],
+		main:          parsed[mainTemplateFile],
+		registry:      parsed[registryTemplateFile],
+		resource:      parsed[resourceTemplateFile],
+		dataSource:    parsed[dataSourceTemplateFile],
+	}
+	if dir != "" {
+		if err := validateTemplates(set); err != nil {
+			return templateSet{}, err
+		}
+	}
+	return set, nil
+}
+
+// validateTemplates renders every template against fixture data so broken
+// user templates are reported before any file is written.
+func validateTemplates(set templateSet) error {
+	info := ProviderInfo{
+		Attributes: []Attribute{
+			{Name: "endpoint", Type: "string", Optional: true, Description: "Endpoint"},
+			{Name: "token", Type: "string", Required: true, Sensitive: true},
+			{Name: "insecure", Type: "bool", Optional: true},
+			{Name: "retries", Type: "int", Optional: true},
+			{Name: "backoff", Type: "float", Optional: true},
+			{Name: "regions", Type: "list", ElemType: &ElemType{Type: "string"}, Optional: true},
+			{Name: "tags", Type: "map", ElemType: &ElemType{Type: "map", Elem: &ElemType{Type: "list", Elem: &ElemType{Type: "string"}}}, Optional: true},
+		},
+		Blocks: []Block{
+			{
+				Name: "auth",
+				Kind: "list",
+				Attributes: []Attribute{
+					{Name: "profile", Type: "string", Optional: true},
+				},
+			},
+		},
+		MetaAttributes: []Attribute{
+			{Name: "module_name", Type: "string", Optional: true, Description: "Module name"},
+			{Name: "labels", Type: "map", ElemType: &ElemType{Type: "string"}, Optional: true},
+		},
+	}
+
+	mainInfo := MainInfo{
+		ProviderImport: "github.com/example/terraform-provider-example/internal/provider",
+		ProviderAlias:  "provider",
+		BuildTags:      []string{"//go:build !example"},
+		GoGenerate:     []string{"//go:generate go run example"},
+	}
+	modulePath := "github.com/example/terraform-provider-example"
+	meta := &MetaType{Name: "Client", Pointer: true, Import: modulePath + "/internal/provider", Package: "provider"}
+
+	for _, split := range []bool{false, true} {
+		layout := Layout{Dir: defaultLayoutDir, Package: defaultLayoutDir, Split: split}
+		providerInfo := info
+		if split {
+			providerInfo.Blocks = withAttributeMode(info.Blocks, attributeModeObject)
+		}
+		configure := crudTranslation{
+			Body:    "client := sdkprovider.NewClient(config.Endpoint.ValueString())\nresp.DataSourceData = client\nresp.ResourceData = resp.DataSourceData",
+			Imports: []string{meta.importSpec()},
+		}
+		for _, meta := range []*MetaType{nil, meta} {
+			providerInfo.MetaType = meta
+			if _, err := renderFrameworkProvider(set, providerInfo, "example", layout, modulePath, configureShared, crudTranslation{}); err != nil {
+				return fmt.Errorf("validate %s: %w", frameworkTemplateFile, err)
+			}
+			if _, err := renderFrameworkProvider(set, providerInfo, "example", layout, modulePath, configureIndependent, configure); err != nil {
+				return fmt.Errorf("validate %s: %w", frameworkTemplateFile, err)
+			}
+		}
+		for _, protocol := range []int{5, 6} {
+			if _, err := renderFrameworkProviderTest(set, mainInfo, layout, protocol); err != nil {
+				return fmt.Errorf("validate %s: %w", frameworkTestTemplateFile, err)
+			}
+			if _, err := renderMuxedMain(set, mainInfo, "registry.terraform.io/example/example", protocol, layout, modulePath); err != nil {
+				return fmt.Errorf("validate %s: %w", mainTemplateFile, err)
+			}
+		}
+	}
+	for _, kind := range []string{resourcesPackage, dataSourcesPackage} {
+		for _, meta := range []*MetaType{nil, meta} {
+			if _, err := renderRegistry(set, kind, meta); err != nil {
+				return fmt.Errorf("validate %s: %w", registryTemplateFile, err)
+			}
+		}
+	}
+
+	timeouts := map[string]string{"create": "10 * time.Minute", "read": sdkDefaultTimeout}
+	bodies := map[string]crudTranslation{
+		"create":         {Body: "plan.Endpoint = types.StringValue(fmt.Sprint(plan.Retries.ValueInt64()))", Imports: []string{`"fmt"`}, UsesTypes: true},
+		"read":           {Body: "// TODO(migrate): uses the provider meta value"},
+		"import":         {Body: "// TODO(migrate): port the SDK importer exampleWidgetImport:"},
+		"modify plan":    {Body: "// TODO(migrate): port the SDK CustomizeDiff rule exampleWidgetCustomizeDiff:"},
+		"plan functions": {Body: "func exampleWidgetEndpointRequiresReplaceIf() {}"},
+		"validators":     {Body: "type exampleWidgetResourceCIDRValidator struct{}"},
+	}
+	resourceCases := []struct {
+		name   string
+		info   ResourceInfo
+		bodies map[string]crudTranslation
+		meta   *MetaType
+	}{
+		{
+			name:   "resource with list blocks",
+			info:   ResourceInfo{Name: "example_widget", Attributes: info.Attributes, Blocks: info.Blocks, Timeouts: timeouts},
+			bodies: bodies,
+			meta:   meta,
+		},
+		{
+			name: "resource with nested attributes, passthrough importer and state upgrader",
+			info: ResourceInfo{
+				Name:          "example_widget",
+				Attributes:    info.Attributes,
+				Blocks:        withAttributeMode(info.Blocks, attributeModeNested),
+				Timeouts:      timeouts,
+				Importer:      importerPassthrough,
+				SchemaVersion: 1,
+				StateUpgrades: []StateUpgrade{{Version: 0, Attributes: info.Attributes, Blocks: withAttributeMode(info.Blocks, attributeModeObject)}},
+			},
+			bodies: bodies,
+			meta:   meta,
+		},
+		{
+			name: "resource with single blocks, custom importer and no meta type",
+			info: ResourceInfo{
+				Name:          "example_widget",
+				Attributes:    info.Attributes,
+				Blocks:        withBlockStrategy(info.Blocks, maxItemsOneSingleBlock),
+				Timeouts:      timeouts,
+				Importer:      importerCustom,
+				SchemaVersion: 1,
+				StateUpgrades: []StateUpgrade{{Version: 0, Attributes: info.Attributes, Blocks: info.Blocks}},
+			},
+			bodies: bodies,
+		},
+		{
+			name: "data source with single attributes",
+			info: ResourceInfo{
+				Name:       "example_widget",
+				DataSource: true,
+				Attributes: info.Attributes,
+				Blocks:     withBlockStrategy(info.Blocks, maxItemsOneSingleAttribute),
+				Timeouts:   timeouts,
+			},
+			bodies: map[string]crudTranslation{"validators": {Body: "type exampleWidgetDataSourceCIDRValidator struct{}"}},
+			meta:   meta,
+		},
+	}
+	for _, split := range []bool{false, true} {
+		layout := Layout{Dir: defaultLayoutDir, Package: defaultLayoutDir, Split: split}
+		for _, style := range []string{timeoutsBlock, timeoutsAttributes} {
+			for _, tc := range resourceCases {
+				name := resourceTemplateFile
+				if tc.info.DataSource {
+					name = dataSourceTemplateFile
+				}
+				if _, err := renderResource(set, tc.info, tc.bodies, "example", layout, style, tc.meta); err != nil {
+					return fmt.Errorf("validate %s (%s): %w", name, tc.name, err)
+				}
+			}
+		}
+	}
+
+	return nil
+}
+
+// ExportTemplates writes the built-in templates to dir as a starting point
+// for --templates. Existing files are only replaced when force is set.
+func ExportTemplates(dir string, force bool) ([]string, error) {
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return nil, err
+	}
+
+	var written []string
+	for _, name := range templateNames() {
+		target := filepath.Join(dir, name)
+		if !force && fileExists(target) {
+			return written, fmt.Errorf("%s already exists (use --force to overwrite)", target)
+		}
+		if err := os.WriteFile(target, []byte(defaultTemplates[name]), 0o644); err != nil {
+			return written, err
+		}
+		written = append(written, target)
+	}
+
+	return written, nil
+}
+
+func templateNames() []string {
+	names := make([]string, 0, len(defaultTemplates))
+	for name := range defaultTemplates {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// withBlockStrategy returns a copy of blocks as MaxItems: 1 blocks rendered
+// with strategy.
+func withBlockStrategy(blocks []Block, strategy string) []Block {
+	single := make([]Block, len(blocks))
+	for i, block := range blocks {
+		block.MaxItems = 1
+		block.Strategy = strategy
+		single[i] = block
+	}
+	return single
+}
+
+// withAttributeMode returns a copy of blocks rendered as attributes in mode.
+func withAttributeMode(blocks []Block, mode string) []Block {
+	moded := make([]Block, len(blocks))
+	for i, block := range blocks {
+		block.AttributeMode = mode
+		moded[i] = block
+	}
+	return moded
+}
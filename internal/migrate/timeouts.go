@@ -0,0 +1,176 @@
+package migrate
+
+import (
+	"fmt"
+	"go/ast"
+	"go/token"
+	"go/types"
+	"strings"
+)
+
+const (
+	timeoutsBlock      = "block"
+	timeoutsAttributes = "attributes"
+
+	// sdkDefaultTimeout is the timeout the SDK applies to operations that
+	// declare none.
+	sdkDefaultTimeout = "20 * time.Minute"
+
+	resourceTimeoutsImport   = `"github.com/hashicorp/terraform-plugin-framework-timeouts/resource/timeouts"`
+	dataSourceTimeoutsImport = `"github.com/hashicorp/terraform-plugin-framework-timeouts/datasource/timeouts"`
+)
+
+// parseTimeouts reads the Timeouts field of a schema.Resource literal into
+// info.Timeouts. The SDK Default timeout is applied to every operation that
+// has a CRUD function but no timeout of its own, since framework timeouts
+// have no default key.
+func parseTimeouts(lit *ast.CompositeLit, info *ResourceInfo, res resolver) {
+	value, ok := compositeValue(lit, "Timeouts")
+	if !ok {
+		return
+	}
+
+	record := func(path, field string, pos token.Pos, result string) {
+		info.Mappings = append(info.Mappings, SchemaMapping{
+			Path:     path,
+			Field:    field,
+			Position: res.fset.Position(pos),
+			Result:   result,
+		})
+	}
+
+	if unary, ok := value.(*ast.UnaryExpr); ok && unary.Op == token.AND {
+		value = unary.X
+	}
+	timeoutsLit, ok := value.(*ast.CompositeLit)
+	if !ok {
+		record("timeouts", "Timeouts", value.Pos(), "not translated: Timeouts is not a schema.ResourceTimeout literal")
+		return
+	}
+
+	info.Timeouts = map[string]string{}
+	var defaultTimeout string
+	var defaultPos token.Pos
+	for _, elt := range timeoutsLit.Elts {
+		kv, ok := elt.(*ast.KeyValueExpr)
+		if !ok {
+			continue
+		}
+		key, ok := kv.Key.(*ast.Ident)
+		if !ok {
+			continue
+		}
+		op := strings.ToLower(key.Name)
+		path, field := "timeouts."+op, "Timeouts."+key.Name
+
+		expr, ok := timeoutDuration(kv.Value)
+		result := expr
+		if !ok {
+			result = fmt.Sprintf("not translated: %s is not a literal duration, defaulting to %s", expr, sdkDefaultTimeout)
+			expr = sdkDefaultTimeout
+		}
+		if op == "default" {
+			defaultTimeout, defaultPos = expr, kv.Pos()
+			if !ok {
+				record(path, field, kv.Pos(), result)
+			}
+			continue
+		}
+		if info.DataSource && op != "read" {
+			record(path, field, kv.Pos(), "not translated: data sources only have a read timeout")
+			continue
+		}
+		info.Timeouts[op] = expr
+		record(path, field, kv.Pos(), result)
+	}
+
+	if defaultTimeout != "" {
+		var applied []string
+		for _, op := range crudFields {
+			if _, ok := info.Timeouts[op.op]; ok {
+				continue
+			}
+			if _, ok := info.crud[op.op]; !ok || (info.DataSource && op.op != "read") {
+				continue
+			}
+			info.Timeouts[op.op] = defaultTimeout
+			applied = append(applied, op.op)
+		}
+		result := "not translated: no operation without its own timeout"
+		if len(applied) > 0 {
+			result = fmt.Sprintf("%s for %s (framework timeouts have no default key)", defaultTimeout, strings.Join(applied, ", "))
+		}
+		record("timeouts.default", "Timeouts.Default", defaultPos, result)
+	}
+
+	if len(info.Timeouts) == 0 {
+		info.Timeouts = nil
+	}
+}
+
+// timeoutDuration returns the argument of schema.DefaultTimeout(...) and
+// whether it is a constant duration that can be copied into the framework
+// package, e.g. 10 * time.Minute.
+func timeoutDuration(expr ast.Expr) (string, bool) {
+	call, ok := expr.(*ast.CallExpr)
+	if !ok || len(call.Args) != 1 {
+		return types.ExprString(expr), false
+	}
+	arg := call.Args[0]
+	if sel, ok := call.Fun.(*ast.SelectorExpr); !ok || sel.Sel.Name != "DefaultTimeout" {
+		return types.ExprString(expr), false
+	}
+
+	portable := true
+	ast.Inspect(arg, func(node ast.Node) bool {
+		switch n := node.(type) {
+		case nil, *ast.ParenExpr, *ast.BinaryExpr:
+			return true
+		case *ast.BasicLit:
+			portable = portable && (n.Kind == token.INT || n.Kind == token.FLOAT)
+		case *ast.SelectorExpr:
+			pkg, ok := n.X.(*ast.Ident)
+			portable = portable && ok && pkg.Name == "time"
+		case *ast.CallExpr:
+			sel, ok := n.Fun.(*ast.SelectorExpr)
+			if ok && len(n.Args) == 1 && types.ExprString(sel) == "time.Duration" {
+				return true
+			}
+			portable = false
+		default:
+			portable = false
+		}
+		return false
+	})
+	return types.ExprString(arg), portable
+}
+
+// timeoutsSchema returns the framework timeouts block or attribute for the
+// operations in info.Timeouts.
+func timeoutsSchema(info ResourceInfo, style string) string {
+	fn := "Block"
+	if style == timeoutsAttributes {
+		fn = "Attributes"
+	}
+	if info.DataSource {
+		return fmt.Sprintf("timeouts.%s(ctx)", fn)
+	}
+
+	var opts []string
+	for _, op := range crudFields {
+		if _, ok := info.Timeouts[op.op]; ok {
+			opts = append(opts, goName(op.op)+": true")
+		}
+	}
+	return fmt.Sprintf("timeouts.%s(ctx, timeouts.Opts{%s})", fn, strings.Join(opts, ", "))
+}
+
+// timeoutsUseTime reports whether any timeout refers to the time package.
+func timeoutsUseTime(timeouts map[string]string) bool {
+	for _, timeout := range timeouts {
+		if strings.Contains(timeout, "time.") {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,132 @@
+package migrate
+
+import (
+	"go/ast"
+	"go/token"
+	"reflect"
+	"testing"
+)
+
+func TestParseTimeouts(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name         string
+		dataSource   bool
+		fields       string
+		want         map[string]string
+		wantMappings []SchemaMapping
+	}{
+		{
+			name: "no timeouts",
+			fields: `CreateContext: resourceThingCreate,
+		ReadContext: resourceThingRead,`,
+		},
+		{
+			name: "operation timeouts and default",
+			fields: `CreateContext: resourceThingCreate,
+		ReadContext: resourceThingRead,
+		DeleteContext: resourceThingDelete,
+		Timeouts: &schema.ResourceTimeout{
+			Create:  schema.DefaultTimeout(10 * time.Minute),
+			Default: schema.DefaultTimeout(5 * time.Minute),
+		},`,
+			want: map[string]string{"create": "10 * time.Minute", "read": "5 * time.Minute", "delete": "5 * time.Minute"},
+			wantMappings: []SchemaMapping{
+				{Path: "timeouts.create", Field: "Timeouts.Create", Result: "10 * time.Minute"},
+				{Path: "timeouts.default", Field: "Timeouts.Default", Result: "5 * time.Minute for read, delete (framework timeouts have no default key)"},
+			},
+		},
+		{
+			name: "default without an operation left",
+			fields: `CreateContext: resourceThingCreate,
+		Timeouts: &schema.ResourceTimeout{
+			Create:  schema.DefaultTimeout(time.Duration(90) * time.Second),
+			Default: schema.DefaultTimeout(5 * time.Minute),
+		},`,
+			want: map[string]string{"create": "time.Duration(90) * time.Second"},
+			wantMappings: []SchemaMapping{
+				{Path: "timeouts.create", Field: "Timeouts.Create", Result: "time.Duration(90) * time.Second"},
+				{Path: "timeouts.default", Field: "Timeouts.Default", Result: "not translated: no operation without its own timeout"},
+			},
+		},
+		{
+			name: "duration that is not a literal",
+			fields: `CreateContext: resourceThingCreate,
+		Timeouts: &schema.ResourceTimeout{
+			Create: schema.DefaultTimeout(createTimeout),
+		},`,
+			want: map[string]string{"create": sdkDefaultTimeout},
+			wantMappings: []SchemaMapping{
+				{Path: "timeouts.create", Field: "Timeouts.Create", Result: "not translated: createTimeout is not a literal duration, defaulting to 20 * time.Minute"},
+			},
+		},
+		{
+			name:       "data source",
+			dataSource: true,
+			fields: `ReadContext: resourceThingRead,
+		Timeouts: &schema.ResourceTimeout{
+			Create: schema.DefaultTimeout(10 * time.Minute),
+			Read:   schema.DefaultTimeout(2 * time.Minute),
+		},`,
+			want: map[string]string{"read": "2 * time.Minute"},
+			wantMappings: []SchemaMapping{
+				{Path: "timeouts.create", Field: "Timeouts.Create", Result: "not translated: data sources only have a read timeout"},
+				{Path: "timeouts.read", Field: "Timeouts.Read", Result: "2 * time.Minute"},
+			},
+		},
+		{
+			name: "not a literal",
+			fields: `CreateContext: resourceThingCreate,
+		Timeouts: resourceThingTimeouts(),`,
+			wantMappings: []SchemaMapping{
+				{Path: "timeouts", Field: "Timeouts", Result: "not translated: Timeouts is not a schema.ResourceTimeout literal"},
+			},
+		},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			res := testResolver(t, map[string]string{"resource.go": `package provider
+
+import (
+	"time"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+)
+
+const createTimeout = 10 * time.Minute
+
+func resourceThing() *schema.Resource {
+	return &schema.Resource{
+		` + tt.fields + `
+	}
+}
+`})
+			var lit *ast.CompositeLit
+			ast.Inspect(res.funcs["resourceThing"], func(n ast.Node) bool {
+				if v, ok := n.(*ast.CompositeLit); ok && lit == nil {
+					lit = v
+				}
+				return lit == nil
+			})
+
+			info := ResourceInfo{Name: "example_thing", DataSource: tt.dataSource, crud: parseCRUDFuncs(lit, res)}
+			parseTimeouts(lit, &info, res)
+			if !reflect.DeepEqual(info.Timeouts, tt.want) {
+				t.Errorf("unexpected timeouts %v, want %v", info.Timeouts, tt.want)
+			}
+			for i := range info.Mappings {
+				if !info.Mappings[i].Position.IsValid() {
+					t.Errorf("mapping %s has no position", info.Mappings[i])
+				}
+				info.Mappings[i].Position = token.Position{}
+			}
+			if !reflect.DeepEqual(info.Mappings, tt.wantMappings) {
+				t.Errorf("unexpected mappings:\n%v\nwant:\n%v", info.Mappings, tt.wantMappings)
+			}
+		})
+	}
+}
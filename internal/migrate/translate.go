@@ -0,0 +1,1051 @@
+package migrate
+
+import (
+	"fmt"
+	"go/ast"
+	"go/parser"
+	"go/token"
+	"go/types"
+	"os"
+	"path"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+const sdkModulePrefix = "github.com/hashicorp/terraform-plugin-sdk"
+
+// frameworkImports are the packages every generated resource file imports.
+// Code that refers to a different package under one of these names is not
+// translated.
+var frameworkImports = map[string]string{
+	"context":    "context",
+	"fmt":        "fmt",
+	"datasource": "github.com/hashicorp/terraform-plugin-framework/datasource",
+	"resource":   "github.com/hashicorp/terraform-plugin-framework/resource",
+	"schema":     "", // resource/schema or datasource/schema
+	"types":      "github.com/hashicorp/terraform-plugin-framework/types",
+}
+
+// crudTranslation is the framework version of an SDK CRUD function body.
+type crudTranslation struct {
+	Body      string
+	Imports   []string
+	TODOs     int
+	UsesTypes bool
+}
+
+// translateResource translates the CRUD functions of a resource. Functions
+// that cannot be found or parsed are reported in the returned notes and left
+// to the template's TODO stub. Custom importers and state upgraders are not
+// translated; their "import" and "upgrade from version N" bodies are TODO
+// stubs quoting the SDK function. CustomizeDiff becomes the "modify plan" body
+// and the "plan functions" of RequiresReplaceIf plan modifiers, and the
+// "validators" body declares the validator types the schema refers to.
+func translateResource(info ResourceInfo, res resolver) (map[string]crudTranslation, []string) {
+	bodies := map[string]crudTranslation{}
+	var notes []string
+	for _, op := range crudFields {
+		fn, ok := info.crud[op.op]
+		if !ok || (info.DataSource && op.op != "read") {
+			continue
+		}
+		if fn.file == nil {
+			notes = append(notes, fmt.Sprintf("%s %s: %s %s not found in the module, left as TODO(migrate)", info.Name, op.op, fn.field, fn.name))
+			continue
+		}
+
+		translation, err := translateCRUD(fn, op.op, info, res)
+		if err != nil {
+			notes = append(notes, fmt.Sprintf("%s %s: %v, left as TODO(migrate)", info.Name, op.op, err))
+			continue
+		}
+		if translation.TODOs > 0 {
+			notes = append(notes, fmt.Sprintf("%s %s: %d statements left as TODO(migrate)", info.Name, op.op, translation.TODOs))
+		}
+		bodies[op.op] = translation
+	}
+
+	if info.Importer == importerCustom {
+		stub, err := referenceStub("importer", info.importer, res)
+		if err != nil {
+			notes = append(notes, fmt.Sprintf("%s import: %v, left as TODO(migrate)", info.Name, err))
+		} else {
+			bodies["import"] = crudTranslation{Body: stub}
+			notes = append(notes, fmt.Sprintf("%s import: custom importer left as TODO(migrate)", info.Name))
+		}
+	}
+
+	for _, upgrade := range info.StateUpgrades {
+		op := upgradeOp(upgrade.Version)
+		stub, err := referenceStub("state upgrader", upgrade.upgrade, res)
+		if err != nil {
+			notes = append(notes, fmt.Sprintf("%s %s: %v, left as TODO(migrate)", info.Name, op, err))
+			continue
+		}
+		bodies[op] = crudTranslation{Body: stub}
+		notes = append(notes, fmt.Sprintf("%s %s: state upgrader left as TODO(migrate)", info.Name, op))
+	}
+
+	if len(info.CustomizeDiff) > 0 {
+		modifyPlan, planFuncs := translateCustomizeDiff(info, res)
+		bodies["modify plan"], bodies["plan functions"] = modifyPlan, planFuncs
+		notes = append(notes, fmt.Sprintf("%s modify plan: %d CustomizeDiff rules left as TODO(migrate)", info.Name, modifyPlan.TODOs+planFuncs.TODOs))
+	}
+	if validators := cidrValidator(info); validators.Body != "" {
+		bodies["validators"] = validators
+	}
+	return bodies, notes
+}
+
+// translateCRUD rewrites the body of an SDK CRUD function to framework model
+// access. Statements it cannot translate, and statements depending on them,
+// are commented out behind a TODO(migrate) marker so the result still
+// compiles.
+func translateCRUD(fn crudFunc, op string, info ResourceInfo, res resolver) (crudTranslation, error) {
+	position := res.fset.Position(fn.node.Pos())
+	data, err := os.ReadFile(position.Filename)
+	if err != nil {
+		return crudTranslation{}, err
+	}
+	fnSource := data[position.Offset:res.fset.Position(fn.node.End()).Offset]
+
+	// Parse a copy of the function so positions index into src.
+	prefix := "package p\n\n"
+	if _, ok := fn.node.(*ast.FuncLit); ok {
+		prefix += "var _ = "
+	}
+	src := append([]byte(prefix), fnSource...)
+	fset := token.NewFileSet()
+	file, err := parser.ParseFile(fset, position.Filename, src, parser.ParseComments)
+	if err != nil {
+		return crudTranslation{}, err
+	}
+
+	var fnType *ast.FuncType
+	var body *ast.BlockStmt
+	switch decl := file.Decls[0].(type) {
+	case *ast.FuncDecl:
+		fnType, body = decl.Type, decl.Body
+	case *ast.GenDecl:
+		lit := decl.Specs[0].(*ast.ValueSpec).Values[0].(*ast.FuncLit)
+		fnType, body = lit.Type, lit.Body
+	}
+
+	t := &translator{
+		fset:       fset,
+		src:        src,
+		op:         op,
+		name:       info.Name,
+		dataSource: info.DataSource,
+		model:      crudModelVar(op, info.DataSource),
+		attrs:      map[string]Attribute{},
+		imports:    fileImports(fn.file),
+		pkgNames:   res.decls,
+		checker:    res.checker,
+		origin:     fn.node.Pos() - token.Pos(len(prefix)),
+		forced:     map[ast.Stmt]string{},
+	}
+	for _, attr := range info.Attributes {
+		t.attrs[attr.Name] = attr
+	}
+	if op == "configure" {
+		t.qualifyPackage(res, position.Filename)
+	}
+	if err := t.params(fnType); err != nil {
+		return crudTranslation{}, err
+	}
+
+	// Commenting out a statement can leave variables declared by translated
+	// statements unused; comment those out as well until nothing changes.
+	var text string
+	for {
+		text = t.run(body)
+		unused := t.unusedDecls()
+		if len(unused) == 0 {
+			break
+		}
+		for stmt, reason := range unused {
+			t.forced[stmt] = reason
+		}
+	}
+
+	var imports []string
+	for name, importPath := range t.used {
+		if name == path.Base(importPath) {
+			imports = append(imports, strconv.Quote(importPath))
+		} else {
+			imports = append(imports, name+" "+strconv.Quote(importPath))
+		}
+	}
+	if t.usesFmt && t.used["fmt"] == "" {
+		imports = append(imports, strconv.Quote("fmt"))
+	}
+	sort.Strings(imports)
+
+	return crudTranslation{
+		Body:      strings.TrimSpace(text),
+		Imports:   imports,
+		TODOs:     t.todos,
+		UsesTypes: t.usesTypes,
+	}, nil
+}
+
+func crudModelVar(op string, dataSource bool) string {
+	switch {
+	case op == "configure":
+		return "config"
+	case dataSource:
+		return "data"
+	case op == "create" || op == "update":
+		return "plan"
+	default:
+		return "state"
+	}
+}
+
+type translator struct {
+	fset       *token.FileSet
+	src        []byte
+	op         string
+	name       string
+	dataSource bool
+	model      string
+	attrs      map[string]Attribute
+	imports    map[string]string
+	pkgNames   map[string]bool
+
+	// pkgAlias, when set, qualifies the exported names of pkgNames, which
+	// are declared in the package pkgImport, instead of leaving the code
+	// using them as TODO.
+	pkgAlias, pkgImport string
+
+	// checker holds the types of the module's expressions, which start at
+	// origin plus their offset into src.
+	checker *typeChecker
+	origin  token.Pos
+
+	// Parameter names of the SDK function, and whether its last result is
+	// diag.Diagnostics rather than error.
+	data, meta, ctx string
+	context         bool
+
+	// forced marks statements to comment out on the next run.
+	forced map[ast.Stmt]string
+
+	// Per run state.
+	poisoned  map[string]bool
+	decls     map[string][]ast.Stmt
+	refs      map[string]int
+	used      map[string]string
+	todos     int
+	usesTypes bool
+	usesFmt   bool
+}
+
+// params records the names of the SDK function parameters and rejects
+// signatures that are not CRUD functions.
+func (t *translator) params(fnType *ast.FuncType) error {
+	for _, field := range fnType.Params.List {
+		name := "_"
+		if len(field.Names) > 0 {
+			name = field.Names[0].Name
+		}
+		switch typeText := exprText(field.Type); typeText {
+		case "context.Context":
+			t.ctx = name
+		case "*schema.ResourceData":
+			t.data = name
+		default:
+			t.meta = name
+		}
+	}
+	if t.data == "" {
+		return fmt.Errorf("function has no *schema.ResourceData parameter")
+	}
+	if fnType.Results != nil && len(fnType.Results.List) > 0 {
+		results := fnType.Results.List
+		t.context = exprText(results[len(results)-1].Type) == "diag.Diagnostics"
+	}
+	return nil
+}
+
+func (t *translator) run(body *ast.BlockStmt) string {
+	t.poisoned = map[string]bool{}
+	t.decls = map[string][]ast.Stmt{}
+	t.refs = map[string]int{}
+	t.used = map[string]string{}
+	t.todos = 0
+	t.usesTypes = false
+	t.usesFmt = false
+	return t.block(body.List, body.Lbrace, body.Rbrace, true)
+}
+
+func (t *translator) unusedDecls() map[ast.Stmt]string {
+	names := make([]string, 0, len(t.decls))
+	for name := range t.decls {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	unused := map[ast.Stmt]string{}
+	for _, name := range names {
+		if t.refs[name] > 0 {
+			continue
+		}
+		for _, stmt := range t.decls[name] {
+			if _, ok := unused[stmt]; ok {
+				continue
+			}
+			unused[stmt] = fmt.Sprintf("declares %s, which is only used by code left as TODO(migrate)", name)
+		}
+	}
+	return unused
+}
+
+func (t *translator) off(pos token.Pos) int {
+	return t.fset.Position(pos).Offset
+}
+
+// block translates a statement list, keeping the comments and blank lines
+// between statements.
+func (t *translator) block(list []ast.Stmt, lbrace, rbrace token.Pos, top bool) string {
+	var b strings.Builder
+	prev := lbrace + 1
+	removed := false
+	for i, stmt := range list {
+		b.Write(t.src[t.off(prev):t.off(stmt.Pos())])
+		text, remove := t.stmt(stmt, top && i == len(list)-1, removed)
+		b.WriteString(text)
+		removed = remove
+		prev = stmt.End()
+	}
+	b.Write(t.src[t.off(prev):t.off(rbrace)])
+	return b.String()
+}
+
+// stmt translates a single statement. The boolean result reports whether the
+// statement removed the resource from state.
+func (t *translator) stmt(stmt ast.Stmt, last, afterRemove bool) (string, bool) {
+	if reason, ok := t.forced[stmt]; ok {
+		return t.todo(stmt, reason), false
+	}
+
+	switch s := stmt.(type) {
+	case *ast.ReturnStmt:
+		return t.returnStmt(s, last, afterRemove), false
+	case *ast.ExprStmt:
+		if call, ok := t.dataCall(s.X, "Set", "SetId"); ok {
+			return t.setStmt(s, call)
+		}
+	case *ast.AssignStmt:
+		if len(s.Lhs) == 1 && len(s.Rhs) == 1 && isBlank(s.Lhs[0]) {
+			if call, ok := t.dataCall(s.Rhs[0], "Set"); ok {
+				return t.setStmt(s, call)
+			}
+		}
+	case *ast.IfStmt:
+		if call, ok := t.checkedSet(s); ok {
+			return t.setStmt(s, call)
+		}
+		return t.ifStmt(s), false
+	case *ast.BlockStmt:
+		return "{" + t.block(s.List, s.Lbrace, s.Rbrace, false) + "}", false
+	case *ast.ForStmt:
+		return t.compound(s, s.Body, declaredNames(s.Init), s.Init, s.Cond, s.Post), false
+	case *ast.RangeStmt:
+		var names []string
+		if s.Tok == token.DEFINE {
+			names = identNames(s.Key, s.Value)
+		}
+		return t.compound(s, s.Body, names, s.X), false
+	case *ast.SwitchStmt:
+		return t.switchStmt(s), false
+	}
+
+	return t.leaf(stmt), false
+}
+
+// leaf translates a statement without nested statements.
+func (t *translator) leaf(stmt ast.Stmt) string {
+	names := declaredNames(stmt)
+	if reason := t.reserved(names); reason != "" {
+		return t.todo(stmt, reason)
+	}
+	r, reason := t.rewrite(stmt.Pos(), stmt.End(), stmt)
+	if reason != "" {
+		return t.todo(stmt, reason)
+	}
+	t.commit(r)
+	t.declare(stmt, names)
+	return r.text
+}
+
+func (t *translator) compound(stmt ast.Stmt, body *ast.BlockStmt, names []string, header ...ast.Node) string {
+	if reason := t.reserved(names); reason != "" {
+		return t.todo(stmt, reason)
+	}
+	r, reason := t.rewrite(stmt.Pos(), body.Lbrace, header...)
+	if reason != "" {
+		return t.todo(stmt, reason)
+	}
+	t.commit(r)
+	t.declare(stmt, names)
+	return r.text + "{" + t.block(body.List, body.Lbrace, body.Rbrace, false) + "}"
+}
+
+func (t *translator) ifStmt(s *ast.IfStmt) string {
+	// An else-if that cannot be translated takes the whole chain with it.
+	for chain := s; chain != nil; {
+		if reason := t.reserved(declaredNames(chain.Init)); reason != "" {
+			return t.todo(s, reason)
+		}
+		if _, reason := t.rewrite(chain.Pos(), chain.Body.Lbrace, chain.Init, chain.Cond); reason != "" {
+			return t.todo(s, reason)
+		}
+		chain, _ = chain.Else.(*ast.IfStmt)
+	}
+
+	text := t.compound(s, s.Body, declaredNames(s.Init), s.Init, s.Cond)
+	switch e := s.Else.(type) {
+	case *ast.BlockStmt:
+		text += string(t.src[t.off(s.Body.End()):t.off(e.Pos())])
+		text += "{" + t.block(e.List, e.Lbrace, e.Rbrace, false) + "}"
+	case *ast.IfStmt:
+		text += string(t.src[t.off(s.Body.End()):t.off(e.Pos())])
+		text += t.ifStmt(e)
+	}
+	return text
+}
+
+func (t *translator) switchStmt(s *ast.SwitchStmt) string {
+	header := []ast.Node{s.Init, s.Tag}
+	for _, clause := range s.Body.List {
+		for _, expr := range clause.(*ast.CaseClause).List {
+			header = append(header, expr)
+		}
+	}
+	if reason := t.reserved(declaredNames(s.Init)); reason != "" {
+		return t.todo(s, reason)
+	}
+	for _, node := range header {
+		if node == nil {
+			continue
+		}
+		if _, reason := t.rewrite(node.Pos(), node.End(), node); reason != "" {
+			return t.todo(s, reason)
+		}
+	}
+
+	r, _ := t.rewrite(s.Pos(), s.Body.Lbrace, s.Init, s.Tag)
+	t.commit(r)
+	t.declare(s, declaredNames(s.Init))
+
+	var b strings.Builder
+	b.WriteString(r.text + "{")
+	prev := s.Body.Lbrace + 1
+	for _, stmt := range s.Body.List {
+		clause := stmt.(*ast.CaseClause)
+		b.Write(t.src[t.off(prev):t.off(clause.Pos())])
+		cr, _ := t.rewrite(clause.Pos(), clause.Colon+1, exprNodes(clause.List)...)
+		t.commit(cr)
+		b.WriteString(cr.text)
+		end := clause.Colon + 1
+		if len(clause.Body) > 0 {
+			end = clause.Body[len(clause.Body)-1].End()
+		}
+		b.WriteString(t.block(clause.Body, clause.Colon, end, false))
+		prev = end
+	}
+	b.Write(t.src[t.off(prev):t.off(s.Body.Rbrace)])
+	b.WriteString("}")
+	return b.String()
+}
+
+func (t *translator) returnStmt(s *ast.ReturnStmt, last, afterRemove bool) string {
+	if t.op == "configure" {
+		return t.configureReturn(s, last)
+	}
+	if len(s.Results) != 1 {
+		return t.todo(s, "return statement with multiple results")
+	}
+	result := s.Results[0]
+
+	if ident, ok := result.(*ast.Ident); ok && ident.Name == "nil" {
+		switch {
+		case last:
+			return ""
+		case afterRemove || t.op == "delete":
+			return "return"
+		default:
+			return fmt.Sprintf("resp.Diagnostics.Append(resp.State.Set(ctx, &%s)...)\nreturn", t.model)
+		}
+	}
+
+	return t.errorReturn(s, result, last)
+}
+
+// configureReturn translates the return statements of an SDK configure
+// function: the meta value becomes the provider data of resources and data
+// sources, an error a diagnostic.
+func (t *translator) configureReturn(s *ast.ReturnStmt, last bool) string {
+	if len(s.Results) != 2 {
+		return t.todo(s, "return statement without a meta value and an error")
+	}
+	meta, result := s.Results[0], s.Results[1]
+	if !isIdent(result, "nil") {
+		return t.errorReturn(s, result, last)
+	}
+	if isIdent(meta, "nil") {
+		if last {
+			return ""
+		}
+		return "return"
+	}
+
+	r, reason := t.rewrite(meta.Pos(), meta.End(), meta)
+	if reason != "" {
+		return t.todo(s, reason)
+	}
+	t.commit(r)
+	text := fmt.Sprintf("resp.DataSourceData = %s\nresp.ResourceData = resp.DataSourceData", r.text)
+	if !last {
+		text += "\nreturn"
+	}
+	return text
+}
+
+// errorReturn translates returning the error or diagnostics result to an
+// error diagnostic.
+func (t *translator) errorReturn(s *ast.ReturnStmt, result ast.Expr, last bool) string {
+	var detail string
+	call, isCall := result.(*ast.CallExpr)
+	switch {
+	case isCall && t.isDiagCall(call, "FromErr") && len(call.Args) == 1:
+		r, reason := t.rewrite(call.Args[0].Pos(), call.Args[0].End(), call.Args[0])
+		if reason != "" {
+			return t.todo(s, reason)
+		}
+		t.commit(r)
+		detail = errorDetail(r.text, call.Args[0])
+	case isCall && t.isDiagCall(call, "Errorf") && len(call.Args) > 0:
+		r, reason := t.rewrite(call.Args[0].Pos(), call.Rparen, exprNodes(call.Args)...)
+		if reason != "" {
+			return t.todo(s, reason)
+		}
+		t.commit(r)
+		detail = strings.TrimSpace(r.text)
+		if len(call.Args) > 1 {
+			detail = "fmt.Sprintf(" + detail + ")"
+			t.usesFmt = true
+		}
+	case t.context:
+		reason := "returns diagnostics that cannot be translated"
+		if _, why := t.rewrite(result.Pos(), result.End(), result); why != "" {
+			reason = why
+		}
+		return t.todo(s, reason)
+	default:
+		r, reason := t.rewrite(result.Pos(), result.End(), result)
+		if reason != "" {
+			return t.todo(s, reason)
+		}
+		t.commit(r)
+		detail = errorDetail(r.text, result)
+	}
+
+	text := fmt.Sprintf("resp.Diagnostics.AddError(%q, %s)", t.summary(), detail)
+	if !last {
+		text += "\nreturn"
+	}
+	return text
+}
+
+// setStmt translates d.Set and d.SetId, including the common
+// "if err := d.Set(...); err != nil" form.
+func (t *translator) setStmt(stmt ast.Stmt, call *ast.CallExpr) (string, bool) {
+	method := call.Fun.(*ast.SelectorExpr).Sel.Name
+
+	if method == "SetId" {
+		if len(call.Args) != 1 {
+			return t.todo(stmt, "d.SetId with unexpected arguments"), false
+		}
+		if lit, ok := call.Args[0].(*ast.BasicLit); ok && lit.Value == `""` {
+			switch {
+			case t.op == "read" && !t.dataSource:
+				return "resp.State.RemoveResource(ctx)", true
+			case t.op == "delete":
+				return "", false
+			}
+			return t.todo(stmt, fmt.Sprintf(`%s.SetId("") outside Read and Delete`, t.data)), false
+		}
+		if _, ok := t.attrs["id"]; !ok {
+			return t.todo(stmt, "the schema has no id attribute"), false
+		}
+		r, reason := t.rewrite(call.Args[0].Pos(), call.Args[0].End(), call.Args[0])
+		if reason != "" {
+			return t.todo(stmt, reason), false
+		}
+		t.commit(r)
+		t.usesTypes = true
+		return fmt.Sprintf("%s.ID = types.StringValue(%s)", t.model, r.text), false
+	}
+
+	if len(call.Args) != 2 {
+		return t.todo(stmt, "d.Set with unexpected arguments"), false
+	}
+	name, ok := stringLiteral(call.Args[0])
+	if !ok {
+		return t.todo(stmt, fmt.Sprintf("%s.Set with a non-constant key", t.data)), false
+	}
+	attr, ok := t.attrs[name]
+	if !ok {
+		return t.todo(stmt, fmt.Sprintf("%s.Set of %q, which is not a top-level attribute", t.data, name)), false
+	}
+
+	var format string
+	switch typ, _ := lookupCustomType(attr.CustomType); {
+	case attr.CustomType != "" && typ.NewValue == "":
+		return t.todo(stmt, fmt.Sprintf("%s.Set of %s attribute %q, whose constructor returns diagnostics", t.data, customTypeValue(attr.CustomType), name)), false
+	case attr.CustomType != "":
+		format = typ.NewValue
+	case attr.Type == "string":
+		format = "types.StringValue(%s)"
+	case attr.Type == "bool":
+		format = "types.BoolValue(%s)"
+	case attr.Type == "int":
+		format = "types.Int64Value(int64(%s))"
+	case attr.Type == "float":
+		format = "types.Float64Value(%s)"
+	default:
+		return t.todo(stmt, fmt.Sprintf("%s.Set of %s attribute %q", t.data, attr.Type, name)), false
+	}
+
+	value := call.Args[1]
+	switch typ := t.valueType(value); {
+	case typ == nil:
+		return t.todo(stmt, fmt.Sprintf("%s.Set of %q with a value of unknown type", t.data, name)), false
+	case !settable(typ, attr.Type):
+		return t.todo(stmt, fmt.Sprintf("%s.Set of %s attribute %q with a %s value", t.data, attr.Type, name, typ)), false
+	}
+	r, reason := t.rewrite(value.Pos(), value.End(), value)
+	if reason != "" {
+		return t.todo(stmt, reason), false
+	}
+	t.commit(r)
+	t.usesTypes = t.usesTypes || attr.CustomType == ""
+	return fmt.Sprintf("%s.%s = "+format, t.model, goName(name), r.text), false
+}
+
+// checkedSet matches "if err := d.Set(...); err != nil { ... }".
+func (t *translator) checkedSet(s *ast.IfStmt) (*ast.CallExpr, bool) {
+	init, ok := s.Init.(*ast.AssignStmt)
+	if !ok || len(init.Lhs) != 1 || len(init.Rhs) != 1 || s.Else != nil {
+		return nil, false
+	}
+	call, ok := t.dataCall(init.Rhs[0], "Set")
+	if !ok {
+		return nil, false
+	}
+	errName, ok := init.Lhs[0].(*ast.Ident)
+	if !ok {
+		return nil, false
+	}
+	cond, ok := s.Cond.(*ast.BinaryExpr)
+	if !ok || cond.Op != token.NEQ {
+		return nil, false
+	}
+	x, xok := cond.X.(*ast.Ident)
+	y, yok := cond.Y.(*ast.Ident)
+	if !xok || !yok || x.Name != errName.Name || y.Name != "nil" {
+		return nil, false
+	}
+	return call, true
+}
+
+func (t *translator) todo(stmt ast.Stmt, reason string) string {
+	t.todos++
+	if isLeafStmt(stmt) {
+		for _, name := range declaredNames(stmt) {
+			t.poisoned[name] = true
+		}
+	}
+
+	indent := t.fset.Position(stmt.Pos()).Column - 1
+	lines := strings.Split(string(t.src[t.off(stmt.Pos()):t.off(stmt.End())]), "\n")
+
+	var b strings.Builder
+	fmt.Fprintf(&b, "// TODO(migrate): %s\n", reason)
+	for i, line := range lines {
+		if i > 0 {
+			b.WriteString("\n")
+			for j := 0; j < indent && strings.HasPrefix(line, "\t"); j++ {
+				line = line[1:]
+			}
+		}
+		b.WriteString(strings.TrimRight("// "+line, " "))
+	}
+	return b.String()
+}
+
+func (t *translator) reserved(names []string) string {
+	for _, name := range names {
+		if name == t.model || name == "req" || name == "resp" {
+			return fmt.Sprintf("declares %s, which the framework method already uses", name)
+		}
+	}
+	return ""
+}
+
+func (t *translator) declare(stmt ast.Stmt, names []string) {
+	for _, name := range names {
+		if name == "_" {
+			continue
+		}
+		delete(t.poisoned, name)
+		t.decls[name] = append(t.decls[name], stmt)
+	}
+}
+
+func (t *translator) isLocal(name string) bool {
+	_, ok := t.decls[name]
+	return ok || t.poisoned[name]
+}
+
+func (t *translator) summary() string {
+	return fmt.Sprintf("Unable to %s %s", t.op, t.name)
+}
+
+// rewritten is the translated source of a range of the SDK function.
+type rewritten struct {
+	text    string
+	refs    []string
+	imports map[string]string
+}
+
+func (t *translator) commit(r rewritten) {
+	for _, name := range r.refs {
+		t.refs[name]++
+	}
+	for name, importPath := range r.imports {
+		t.used[name] = importPath
+	}
+}
+
+type edit struct {
+	start, end int
+	text       string
+}
+
+// rewrite translates the source between start and end, replacing d.Get and
+// d.Id within nodes. It returns the reason when nodes use anything that has
+// no framework equivalent.
+func (t *translator) rewrite(start, end token.Pos, nodes ...ast.Node) (rewritten, string) {
+	r := rewritten{imports: map[string]string{}}
+	var edits []edit
+	var reason string
+
+	var visit func(ast.Node) bool
+	inspect := func(node ast.Node) {
+		if node != nil {
+			ast.Inspect(node, visit)
+		}
+	}
+	visit = func(node ast.Node) bool {
+		if reason != "" {
+			return false
+		}
+		switch n := node.(type) {
+		case *ast.TypeAssertExpr:
+			if call, ok := t.dataCall(n.X, "Get"); ok {
+				text, why := t.getExpr(call, n.Type)
+				if why != "" {
+					reason = why
+					return false
+				}
+				edits = append(edits, edit{t.off(n.Pos()), t.off(n.End()), text})
+				return false
+			}
+		case *ast.CallExpr:
+			if _, ok := t.dataCall(n, "Id"); ok {
+				if _, ok := t.attrs["id"]; !ok {
+					reason = "the schema has no id attribute"
+					return false
+				}
+				edits = append(edits, edit{t.off(n.Pos()), t.off(n.End()), t.model + ".ID.ValueString()"})
+				return false
+			}
+		case *ast.SelectorExpr:
+			if ident, ok := n.X.(*ast.Ident); ok && !t.isLocal(ident.Name) {
+				if importPath, ok := t.imports[ident.Name]; ok {
+					if why := importReason(ident.Name, importPath); why != "" {
+						reason = why
+						return false
+					}
+					r.imports[ident.Name] = importPath
+					return false
+				}
+			}
+			inspect(n.X)
+			return false
+		case *ast.KeyValueExpr:
+			if _, ok := n.Key.(*ast.Ident); ok {
+				inspect(n.Value)
+				return false
+			}
+		case *ast.AssignStmt:
+			// v, ok := d.Get("name").(T) always succeeds on the model
+			if len(n.Lhs) == 2 && len(n.Rhs) == 1 {
+				if assert, ok := n.Rhs[0].(*ast.TypeAssertExpr); ok {
+					if _, ok := t.dataCall(assert.X, "Get"); ok {
+						edits = append(edits, edit{t.off(assert.End()), t.off(assert.End()), ", true"})
+					}
+				}
+			}
+			if n.Tok == token.DEFINE {
+				for _, rhs := range n.Rhs {
+					inspect(rhs)
+				}
+				return false
+			}
+		case *ast.ValueSpec:
+			inspect(n.Type)
+			for _, value := range n.Values {
+				inspect(value)
+			}
+			return false
+		case *ast.Ident:
+			switch {
+			case n.Name == "_":
+				// Unnamed parameters are recorded as _, which is then
+				// only ever the blank identifier.
+				return false
+			case n.Name == t.data:
+				reason = fmt.Sprintf("uses %s (*schema.ResourceData) in a way that cannot be translated", n.Name)
+				return false
+			case n.Name == t.meta:
+				reason = "uses the provider meta value"
+				return false
+			case t.poisoned[n.Name]:
+				reason = fmt.Sprintf("depends on %s, which was not translated", n.Name)
+				return false
+			case t.pkgNames[n.Name] && !t.isLocal(n.Name):
+				switch {
+				case t.pkgAlias == "":
+					reason = fmt.Sprintf("uses %s from the SDK provider package", n.Name)
+					return false
+				case !ast.IsExported(n.Name):
+					reason = fmt.Sprintf("uses %s, which is unexported in package %s", n.Name, t.pkgImport)
+					return false
+				}
+				edits = append(edits, edit{t.off(n.Pos()), t.off(n.End()), t.pkgAlias + "." + n.Name})
+				r.imports[t.pkgAlias] = t.pkgImport
+			case n.Name == t.ctx && t.ctx != "ctx":
+				edits = append(edits, edit{t.off(n.Pos()), t.off(n.End()), "ctx"})
+			}
+			r.refs = append(r.refs, n.Name)
+		}
+		return true
+	}
+	for _, node := range nodes {
+		inspect(node)
+	}
+	if reason != "" {
+		return rewritten{}, reason
+	}
+
+	base := t.off(start)
+	text := string(t.src[base:t.off(end)])
+	sort.Slice(edits, func(i, j int) bool { return edits[i].start > edits[j].start })
+	for _, e := range edits {
+		text = text[:e.start-base] + e.text + text[e.end-base:]
+	}
+	r.text = text
+	return r, ""
+}
+
+// getExpr translates d.Get("name").(T) to the framework value of the model
+// field.
+func (t *translator) getExpr(call *ast.CallExpr, typ ast.Expr) (string, string) {
+	if len(call.Args) != 1 {
+		return "", fmt.Sprintf("%s.Get with unexpected arguments", t.data)
+	}
+	name, ok := stringLiteral(call.Args[0])
+	if !ok {
+		return "", fmt.Sprintf("%s.Get with a non-constant key", t.data)
+	}
+	if strings.Contains(name, ".") {
+		return "", fmt.Sprintf("%s.Get of nested path %q", t.data, name)
+	}
+	attr, ok := t.attrs[name]
+	if !ok {
+		return "", fmt.Sprintf("%s.Get of %q, which is not a top-level attribute", t.data, name)
+	}
+
+	field := t.model + "." + goName(name)
+	switch typeName := exprText(typ); {
+	case attr.Type == "string" && typeName == "string":
+		return field + ".ValueString()", ""
+	case attr.Type == "bool" && typeName == "bool":
+		return field + ".ValueBool()", ""
+	case attr.Type == "int" && typeName == "int":
+		return "int(" + field + ".ValueInt64())", ""
+	case attr.Type == "float" && typeName == "float64":
+		return field + ".ValueFloat64()", ""
+	default:
+		return "", fmt.Sprintf("%s.Get(%q).(%s) of %s attribute", t.data, name, typeName, attr.Type)
+	}
+}
+
+// dataCall matches a call of one of methods on the *schema.ResourceData
+// parameter.
+func (t *translator) dataCall(expr ast.Expr, methods ...string) (*ast.CallExpr, bool) {
+	call, ok := expr.(*ast.CallExpr)
+	if !ok {
+		return nil, false
+	}
+	sel, ok := call.Fun.(*ast.SelectorExpr)
+	if !ok {
+		return nil, false
+	}
+	ident, ok := sel.X.(*ast.Ident)
+	if !ok || ident.Name != t.data {
+		return nil, false
+	}
+	for _, method := range methods {
+		if sel.Sel.Name == method {
+			return call, true
+		}
+	}
+	return nil, false
+}
+
+func (t *translator) isDiagCall(call *ast.CallExpr, name string) bool {
+	sel, ok := call.Fun.(*ast.SelectorExpr)
+	if !ok || sel.Sel.Name != name {
+		return false
+	}
+	ident, ok := sel.X.(*ast.Ident)
+	return ok && t.imports[ident.Name] == sdkModulePrefix+"/v2/diag"
+}
+
+func importReason(name, importPath string) string {
+	if strings.HasPrefix(importPath, sdkModulePrefix) {
+		return fmt.Sprintf("uses the SDK package %s", name)
+	}
+	if fwPath, ok := frameworkImports[name]; ok && fwPath != importPath {
+		return fmt.Sprintf("uses package %s, which clashes with a framework import", name)
+	}
+	return ""
+}
+
+// fileImports maps the names a file refers to its imports by to their paths.
+func fileImports(file *ast.File) map[string]string {
+	imports := map[string]string{}
+	for _, spec := range file.Imports {
+		importPath, err := strconv.Unquote(spec.Path.Value)
+		if err != nil {
+			continue
+		}
+		name := importName(importPath)
+		if spec.Name != nil {
+			name = spec.Name.Name
+		}
+		if name != "_" && name != "." {
+			imports[name] = importPath
+		}
+	}
+	return imports
+}
+
+var majorVersionSuffix = regexp.MustCompile(`^v[0-9]+$`)
+
+func importName(importPath string) string {
+	name := path.Base(importPath)
+	if majorVersionSuffix.MatchString(name) && path.Dir(importPath) != "." {
+		name = path.Base(path.Dir(importPath))
+	}
+	return name
+}
+
+func declaredNames(stmt ast.Node) []string {
+	switch s := stmt.(type) {
+	case *ast.AssignStmt:
+		if s.Tok == token.DEFINE {
+			return identNames(s.Lhs...)
+		}
+	case *ast.DeclStmt:
+		var names []string
+		if gen, ok := s.Decl.(*ast.GenDecl); ok {
+			for _, spec := range gen.Specs {
+				switch spec := spec.(type) {
+				case *ast.ValueSpec:
+					for _, name := range spec.Names {
+						names = append(names, name.Name)
+					}
+				case *ast.TypeSpec:
+					names = append(names, spec.Name.Name)
+				}
+			}
+		}
+		return names
+	}
+	return nil
+}
+
+func identNames(exprs ...ast.Expr) []string {
+	var names []string
+	for _, expr := range exprs {
+		if ident, ok := expr.(*ast.Ident); ok {
+			names = append(names, ident.Name)
+		}
+	}
+	return names
+}
+
+func isLeafStmt(stmt ast.Stmt) bool {
+	switch stmt.(type) {
+	case *ast.AssignStmt, *ast.DeclStmt:
+		return true
+	}
+	return false
+}
+
+func isBlank(expr ast.Expr) bool {
+	ident, ok := expr.(*ast.Ident)
+	return ok && ident.Name == "_"
+}
+
+func exprNodes(exprs []ast.Expr) []ast.Node {
+	nodes := make([]ast.Node, 0, len(exprs))
+	for _, expr := range exprs {
+		nodes = append(nodes, expr)
+	}
+	return nodes
+}
+
+func stringLiteral(expr ast.Expr) (string, bool) {
+	lit, ok := expr.(*ast.BasicLit)
+	if !ok || lit.Kind != token.STRING {
+		return "", false
+	}
+	value, err := strconv.Unquote(lit.Value)
+	return value, err == nil
+}
+
+func errorDetail(text string, expr ast.Expr) string {
+	switch expr.(type) {
+	case *ast.Ident, *ast.CallExpr, *ast.SelectorExpr:
+		return text + ".Error()"
+	default:
+		return "(" + text + ").Error()"
+	}
+}
+
+func exprText(expr ast.Expr) string {
+	if expr == nil {
+		return ""
+	}
+	return types.ExprString(expr)
+}
@@ -0,0 +1,298 @@
+package migrate
+
+import (
+	"go/ast"
+	"go/format"
+	"go/parser"
+	"go/token"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+// testModulePath is the module path of the sources of testResolver.
+const testModulePath = "example.com/terraform-provider-example"
+
+// testResolver writes files, keyed by slash separated path, to a module and
+// returns the resolver of their packages.
+func testResolver(t *testing.T, files map[string]string) resolver {
+	t.Helper()
+
+	root := t.TempDir()
+	fset := token.NewFileSet()
+	var parsed []*ast.File
+	for name, src := range files {
+		filename := filepath.Join(root, filepath.FromSlash(name))
+		if err := os.MkdirAll(filepath.Dir(filename), 0o755); err != nil {
+			t.Fatalf("create package directory: %v", err)
+		}
+		if err := os.WriteFile(filename, []byte(src), 0o644); err != nil {
+			t.Fatalf("write %s: %v", name, err)
+		}
+		file, err := parser.ParseFile(fset, filename, nil, parser.ParseComments)
+		if err != nil {
+			t.Fatalf("parse %s: %v", name, err)
+		}
+		parsed = append(parsed, file)
+	}
+	res := buildResolver(fset, parsed)
+	res.indexPackageDecls(root, testModulePath)
+	return res
+}
+
+// translateSource translates the function fnName of src, a file of the SDK
+// provider package, as the op method of a resource with attrs.
+func translateSource(t *testing.T, src, fnName, op string, attrs ...Attribute) crudTranslation {
+	t.Helper()
+
+	res := testResolver(t, map[string]string{"resource.go": src})
+	decl, ok := res.funcs[fnName]
+	if !ok {
+		t.Fatalf("no function %s in source", fnName)
+	}
+	file := res.files[res.fset.File(decl.Pos()).Name()]
+
+	info := ResourceInfo{Name: "example_thing", Attributes: attrs}
+	out, err := translateCRUD(crudFunc{name: fnName, file: file, node: decl}, op, info, res)
+	if err != nil {
+		t.Fatalf("translate %s: %v", fnName, err)
+	}
+	return out
+}
+
+// formatBody gofmts a translated function body so it can be compared
+// regardless of the indentation the translation kept from the SDK source.
+func formatBody(t *testing.T, body string) string {
+	t.Helper()
+
+	src, err := format.Source([]byte("package p\n\nfunc f() {\n" + body + "\n}\n"))
+	if err != nil {
+		t.Fatalf("format body: %v\n%s", err, body)
+	}
+	return string(src)
+}
+
+// readSource returns a provider file whose read function resourceThingRead
+// runs body.
+func readSource(body string) string {
+	return `package provider
+
+import (
+	"context"
+	"strconv"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+)
+
+func stringPtr(s string) *string {
+	return &s
+}
+
+func resourceThingRead(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	` + body + `
+	return nil
+}
+`
+}
+
+func TestTranslateBlankParameters(t *testing.T) {
+	t.Parallel()
+
+	src := `package provider
+
+import (
+	"context"
+	"strconv"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+)
+
+func resourceThingCreate(_ context.Context, d *schema.ResourceData, _ interface{}) diag.Diagnostics {
+	name := d.Get("name").(string)
+	var err error
+	_, err = strconv.Atoi(name)
+	if err != nil {
+		return diag.FromErr(err)
+	}
+	for _, part := range name {
+		_ = part
+	}
+	d.SetId(name)
+	return nil
+}
+`
+	out := translateSource(t, src, "resourceThingCreate", "create",
+		Attribute{Name: "id", Type: "string", Computed: true},
+		Attribute{Name: "name", Type: "string", Required: true},
+	)
+	if out.TODOs != 0 {
+		t.Fatalf("expected no TODOs, got %d in:\n%s", out.TODOs, out.Body)
+	}
+	want := formatBody(t, `name := plan.Name.ValueString()
+var err error
+_, err = strconv.Atoi(name)
+if err != nil {
+	resp.Diagnostics.AddError("Unable to create example_thing", err.Error())
+	return
+}
+for _, part := range name {
+	_ = part
+}
+plan.ID = types.StringValue(name)`)
+	if got := formatBody(t, out.Body); got != want {
+		t.Fatalf("unexpected body:\n%s\nwant:\n%s", got, want)
+	}
+}
+
+func TestTranslateSetValueTypes(t *testing.T) {
+	t.Parallel()
+
+	attrs := []Attribute{
+		{Name: "id", Type: "string", Computed: true},
+		{Name: "name", Type: "string", Optional: true},
+		{Name: "count", Type: "int", Optional: true},
+		{Name: "ratio", Type: "float", Optional: true},
+		{Name: "enabled", Type: "bool", Optional: true},
+	}
+	tests := []struct {
+		name string
+		body string
+		want string
+	}{
+		{
+			name: "literals",
+			body: `d.Set("name", "x")
+	d.Set("count", 3)
+	d.Set("ratio", 1)
+	d.Set("enabled", true)`,
+			want: `state.Name = types.StringValue("x")
+state.Count = types.Int64Value(int64(3))
+state.Ratio = types.Float64Value(1)
+state.Enabled = types.BoolValue(true)`,
+		},
+		{
+			name: "d.Get locals and conversions",
+			body: `n := d.Get("count").(int)
+	d.Set("count", n)
+	d.Set("ratio", float64(n))
+	d.Set("name", d.Get("name").(string))`,
+			want: `n := int(state.Count.ValueInt64())
+state.Count = types.Int64Value(int64(n))
+state.Ratio = types.Float64Value(float64(n))
+state.Name = types.StringValue(state.Name.ValueString())`,
+		},
+		{
+			name: "standard library results",
+			body: `d.Set("name", strconv.Itoa(2))`,
+			want: `state.Name = types.StringValue(strconv.Itoa(2))`,
+		},
+		{
+			name: "pointer",
+			body: `d.Set("name", stringPtr("x"))`,
+			want: `// TODO(migrate): d.Set of string attribute "name" with a *string value
+// d.Set("name", stringPtr("x"))`,
+		},
+		{
+			name: "float32",
+			body: `var ratio float32 = 1.5
+	d.Set("ratio", ratio)`,
+			want: `// TODO(migrate): declares ratio, which is only used by code left as TODO(migrate)
+// var ratio float32 = 1.5
+// TODO(migrate): d.Set of float attribute "ratio" with a float32 value
+// d.Set("ratio", ratio)`,
+		},
+		{
+			name: "unknown type",
+			body: `d.Set("enabled", d.HasChange("name"))`,
+			want: `// TODO(migrate): d.Set of "enabled" with a value of unknown type
+// d.Set("enabled", d.HasChange("name"))`,
+		},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			out := translateSource(t, readSource(tt.body), "resourceThingRead", "read", attrs...)
+			if got, want := formatBody(t, out.Body), formatBody(t, tt.want); got != want {
+				t.Fatalf("unexpected body:\n%s\nwant:\n%s", got, want)
+			}
+		})
+	}
+}
+
+func TestTranslateGetExpr(t *testing.T) {
+	t.Parallel()
+
+	attrs := []Attribute{
+		{Name: "id", Type: "string", Computed: true},
+		{Name: "name", Type: "string", Optional: true},
+		{Name: "count", Type: "int", Optional: true},
+		{Name: "ratio", Type: "float", Optional: true},
+		{Name: "enabled", Type: "bool", Optional: true},
+		{Name: "labels", Type: "list", Optional: true, ElemType: &ElemType{Type: "string"}},
+	}
+	tests := []struct {
+		name string
+		body string
+		want string
+	}{
+		{
+			name: "matching types",
+			body: `_ = d.Get("name").(string)
+	_ = d.Get("count").(int)
+	_ = d.Get("ratio").(float64)
+	_ = d.Get("enabled").(bool)`,
+			want: `_ = state.Name.ValueString()
+_ = int(state.Count.ValueInt64())
+_ = state.Ratio.ValueFloat64()
+_ = state.Enabled.ValueBool()`,
+		},
+		{
+			name: "non-constant key",
+			body: `key := "name"
+	_ = d.Get(key).(string)`,
+			want: `// TODO(migrate): declares key, which is only used by code left as TODO(migrate)
+// key := "name"
+// TODO(migrate): d.Get with a non-constant key
+// _ = d.Get(key).(string)`,
+		},
+		{
+			name: "nested path",
+			body: `_ = d.Get("rule.0.cidr").(string)`,
+			want: `// TODO(migrate): d.Get of nested path "rule.0.cidr"
+// _ = d.Get("rule.0.cidr").(string)`,
+		},
+		{
+			name: "unknown attribute",
+			body: `_ = d.Get("missing").(string)`,
+			want: `// TODO(migrate): d.Get of "missing", which is not a top-level attribute
+// _ = d.Get("missing").(string)`,
+		},
+		{
+			name: "mismatched type",
+			body: `_ = d.Get("count").(string)`,
+			want: `// TODO(migrate): d.Get("count").(string) of int attribute
+// _ = d.Get("count").(string)`,
+		},
+		{
+			name: "collection",
+			body: `_ = d.Get("labels").([]interface{})`,
+			want: `// TODO(migrate): d.Get("labels").([]interface{}) of list attribute
+// _ = d.Get("labels").([]interface{})`,
+		},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			out := translateSource(t, readSource(tt.body), "resourceThingRead", "read", attrs...)
+			if got, want := formatBody(t, out.Body), formatBody(t, tt.want); got != want {
+				t.Fatalf("unexpected body:\n%s\nwant:\n%s", got, want)
+			}
+		})
+	}
+}
@@ -3,10 +3,26 @@ package migrate
 import "fmt"
 
 type Options struct {
-	Path            string
-	RegistryAddress string
-	ProviderName    string
-	DryRun          bool
+	Path               string
+	ConfigFile         string
+	RegistryAddress    string
+	ProviderName       string
+	ProtocolVersion    int
+	Layout             Layout
+	TemplatesDir       string
+	Dependencies       Dependencies
+	SkipResources      []string
+	SkipDataSources    []string
+	AttributeOverrides map[string]AttributeOverride
+	MaxItemsOne        map[string]string
+	Resources          []string
+	DataSources        []string
+	AllResources       bool
+	Timeouts           string
+	Configure          string
+	DryRun             bool
+	NoUpgrade          bool
+	Force              bool
 }
 
 type Report struct {
@@ -15,20 +31,42 @@ type Report struct {
 	FrameworkFile   string
 	ProviderName    string
 	RegistryAddress string
+	ProtocolVersion int
 	Attributes      int
+	Resources       int
+	DataSources     int
+	Settings        []Setting
+	ModChanges      []ModChange
 	Notes           []string
 }
 
 func (r Report) Summary() string {
-	msg := fmt.Sprintf("module=%s provider=%s registry=%s attrs=%d", r.ModuleRoot, r.ProviderName, r.RegistryAddress, r.Attributes)
+	msg := fmt.Sprintf("module=%s provider=%s registry=%s protocol=%d attrs=%d resources=%d data_sources=%d", r.ModuleRoot, r.ProviderName, r.RegistryAddress, r.ProtocolVersion, r.Attributes, r.Resources, r.DataSources)
 	if r.MainFile != "" {
 		msg += fmt.Sprintf(" main=%s", r.MainFile)
 	}
 	if r.FrameworkFile != "" {
 		msg += fmt.Sprintf(" framework=%s", r.FrameworkFile)
 	}
+	if len(r.ModChanges) > 0 {
+		msg += fmt.Sprintf(" gomod=%d", len(r.ModChanges))
+	}
 	if len(r.Notes) > 0 {
 		msg += fmt.Sprintf(" notes=%d", len(r.Notes))
 	}
 	return msg
 }
+
+// Details returns one line per setting, planned go.mod change and note, in
+// that order.
+func (r Report) Details() []string {
+	lines := make([]string, 0, len(r.Settings)+len(r.ModChanges)+len(r.Notes))
+	for _, setting := range r.Settings {
+		lines = append(lines, setting.String())
+	}
+	for _, change := range r.ModChanges {
+		lines = append(lines, change.String())
+	}
+	lines = append(lines, r.Notes...)
+	return lines
+}
@@ -0,0 +1,124 @@
+package migrate
+
+import (
+	"fmt"
+	"go/ast"
+	"go/token"
+	"go/types"
+	"strconv"
+)
+
+// StateUpgrade is an SDK state upgrader: the schema version it upgrades from
+// and the schema of that version, parsed from the resource function its
+// Type is derived from.
+type StateUpgrade struct {
+	Version    int64
+	Attributes []Attribute
+	Blocks     []Block
+
+	upgrade crudFunc
+}
+
+// parseStateUpgraders reads SchemaVersion and StateUpgraders of a
+// schema.Resource literal. Migrating a versioned resource without its
+// upgraders would corrupt existing state, so any upgrader that cannot be
+// resolved is an error.
+func parseStateUpgraders(lit *ast.CompositeLit, info *ResourceInfo, res resolver) error {
+	if value, ok := compositeValue(lit, "SchemaVersion"); ok {
+		version, err := intLiteral(value)
+		if err != nil {
+			return fmt.Errorf("SchemaVersion: %w", err)
+		}
+		info.SchemaVersion = version
+	}
+
+	value, ok := compositeValue(lit, "StateUpgraders")
+	if !ok {
+		if info.SchemaVersion > 0 {
+			return fmt.Errorf("SchemaVersion %d without StateUpgraders cannot be migrated (MigrateState is not supported)", info.SchemaVersion)
+		}
+		return nil
+	}
+	upgraders, ok := value.(*ast.CompositeLit)
+	if !ok {
+		return fmt.Errorf("StateUpgraders is not a slice literal")
+	}
+
+	for i, elt := range upgraders.Elts {
+		upgraderLit, ok := elt.(*ast.CompositeLit)
+		if !ok {
+			return fmt.Errorf("state upgrader %d is not a schema.StateUpgrader literal", i)
+		}
+		upgrade, err := parseStateUpgrader(upgraderLit, res)
+		if err != nil {
+			return fmt.Errorf("state upgrader %d: %w", i, err)
+		}
+		info.StateUpgrades = append(info.StateUpgrades, upgrade)
+	}
+	return nil
+}
+
+func parseStateUpgrader(lit *ast.CompositeLit, res resolver) (StateUpgrade, error) {
+	var upgrade StateUpgrade
+
+	value, ok := compositeValue(lit, "Version")
+	if !ok {
+		return upgrade, fmt.Errorf("Version not set")
+	}
+	version, err := intLiteral(value)
+	if err != nil {
+		return upgrade, fmt.Errorf("Version: %w", err)
+	}
+	upgrade.Version = version
+
+	typeExpr, ok := compositeValue(lit, "Type")
+	if !ok {
+		return upgrade, fmt.Errorf("Type not set")
+	}
+	priorLit, err := resolveResourceLiteral(impliedTypeResource(typeExpr), res)
+	if err != nil {
+		return upgrade, fmt.Errorf("Type %s: %w", types.ExprString(typeExpr), err)
+	}
+	attrs, blocks, err := parseResourceSchema(priorLit, res)
+	if err != nil {
+		return upgrade, fmt.Errorf("Type %s: %w", types.ExprString(typeExpr), err)
+	}
+	upgrade.Attributes, _ = withImplicitID(attrs, Attribute{Name: "id", Type: "string", Computed: true})
+	upgrade.Blocks = blocks
+
+	if fn, ok := compositeValue(lit, "Upgrade"); ok {
+		upgrade.upgrade = resolveFunc("Upgrade", fn, res)
+	}
+	return upgrade, nil
+}
+
+// impliedTypeResource strips .CoreConfigSchema().ImpliedType() from an
+// upgrader Type, leaving the expression of the prior schema.Resource.
+func impliedTypeResource(expr ast.Expr) ast.Expr {
+	for _, method := range []string{"ImpliedType", "CoreConfigSchema"} {
+		call, ok := expr.(*ast.CallExpr)
+		if !ok {
+			return expr
+		}
+		sel, ok := call.Fun.(*ast.SelectorExpr)
+		if !ok || sel.Sel.Name != method {
+			return expr
+		}
+		expr = sel.X
+	}
+	return expr
+}
+
+func intLiteral(expr ast.Expr) (int64, error) {
+	lit, ok := expr.(*ast.BasicLit)
+	if !ok || lit.Kind != token.INT {
+		return 0, fmt.Errorf("%s is not an integer literal", types.ExprString(expr))
+	}
+	return strconv.ParseInt(lit.Value, 0, 64)
+}
+
+// upgradeOp is the key of the stub body of the upgrader from version in the
+// bodies passed to renderResource.
+func upgradeOp(version int64) string {
+	return fmt.Sprintf("upgrade from version %d", version)
+}
@@ -0,0 +1,187 @@
+package migrate
+
+import (
+	"go/ast"
+	"go/importer"
+	"go/token"
+	"go/types"
+	"path/filepath"
+)
+
+// typeChecker type-checks the packages of the module on demand, so the
+// values the SDK code passes to d.Set can be matched against the framework
+// value constructors. Only the standard library is imported; expressions
+// depending on the SDK or other modules have no type and are matched
+// syntactically instead.
+type typeChecker struct {
+	fset  *token.FileSet
+	files map[string]*ast.File
+	std   types.Importer
+	// spans holds the type of every expression of the packages checked so
+	// far by its position range, checked the directories of those packages.
+	spans   map[[2]token.Pos]types.Type
+	checked map[string]bool
+}
+
+func newTypeChecker(fset *token.FileSet, files map[string]*ast.File) *typeChecker {
+	return &typeChecker{
+		fset:    fset,
+		files:   files,
+		std:     importer.ForCompiler(fset, "gc", nil),
+		spans:   map[[2]token.Pos]types.Type{},
+		checked: map[string]bool{},
+	}
+}
+
+func (c *typeChecker) Import(path string) (*types.Package, error) {
+	return c.std.Import(path)
+}
+
+// typeOf returns the type of the expression between pos and end, or nil when
+// it has none or its type depends on a package that could not be imported.
+func (c *typeChecker) typeOf(pos, end token.Pos) types.Type {
+	tokenFile := c.fset.File(pos)
+	if tokenFile == nil {
+		return nil
+	}
+	c.check(tokenFile.Name())
+	typ := c.spans[[2]token.Pos{pos, end}]
+	if typ == nil || typ == types.Typ[types.Invalid] {
+		return nil
+	}
+	return typ
+}
+
+// check type-checks the package of filename, ignoring type errors.
+func (c *typeChecker) check(filename string) {
+	dir := filepath.Dir(filename)
+	if c.checked[dir] {
+		return
+	}
+	c.checked[dir] = true
+
+	pkgFile, ok := c.files[filename]
+	if !ok {
+		return
+	}
+	var files []*ast.File
+	for name, file := range c.files {
+		if filepath.Dir(name) == dir && file.Name.Name == pkgFile.Name.Name {
+			files = append(files, file)
+		}
+	}
+	info := &types.Info{Types: map[ast.Expr]types.TypeAndValue{}}
+	conf := types.Config{Importer: c, Error: func(error) {}}
+	_, _ = conf.Check(pkgFile.Name.Name, c.fset, files, info)
+	for expr, tv := range info.Types {
+		if tv.IsValue() {
+			c.spans[[2]token.Pos{expr.Pos(), expr.End()}] = tv.Type
+		}
+	}
+}
+
+// valueType returns the Go type of expr, a value of the translated SDK
+// function, or nil when it is unknown.
+func (t *translator) valueType(expr ast.Expr) types.Type {
+	expr = ast.Unparen(expr)
+	if t.checker != nil {
+		if typ := t.checker.typeOf(t.origin+token.Pos(t.off(expr.Pos())), t.origin+token.Pos(t.off(expr.End()))); typ != nil {
+			return typ
+		}
+	}
+
+	// The SDK package does not type-check, so follow values read with
+	// d.Get and the conversions of them by hand.
+	switch e := expr.(type) {
+	case *ast.TypeAssertExpr:
+		if _, ok := t.dataCall(e.X, "Get"); ok {
+			return basicType(e.Type)
+		}
+	case *ast.CallExpr:
+		fun, ok := e.Fun.(*ast.Ident)
+		if !ok || t.isLocal(fun.Name) || t.pkgNames[fun.Name] {
+			return nil
+		}
+		if fun.Name == "len" || fun.Name == "cap" {
+			return types.Typ[types.Int]
+		}
+		if len(e.Args) == 1 {
+			return basicType(fun)
+		}
+	case *ast.Ident:
+		decls := t.decls[e.Name]
+		if len(decls) != 1 {
+			return nil
+		}
+		assign, ok := decls[0].(*ast.AssignStmt)
+		if !ok || assign.Tok != token.DEFINE {
+			return nil
+		}
+		for i, lhs := range assign.Lhs {
+			if !isIdent(lhs, e.Name) {
+				continue
+			}
+			switch {
+			case len(assign.Rhs) == len(assign.Lhs):
+				return t.valueType(assign.Rhs[i])
+			case i == 0 && len(assign.Rhs) == 1:
+				// v, ok := d.Get("name").(T)
+				if _, ok := ast.Unparen(assign.Rhs[0]).(*ast.TypeAssertExpr); ok {
+					return t.valueType(assign.Rhs[0])
+				}
+			}
+		}
+	}
+	return nil
+}
+
+// basicType returns the predeclared basic type expr names, if any.
+func basicType(expr ast.Expr) types.Type {
+	ident, ok := expr.(*ast.Ident)
+	if !ok {
+		return nil
+	}
+	typeName, ok := types.Universe.Lookup(ident.Name).(*types.TypeName)
+	if !ok {
+		return nil
+	}
+	if _, ok := typeName.Type().(*types.Basic); !ok {
+		return nil
+	}
+	return typeName.Type()
+}
+
+// settable reports whether a value of typ can be passed to the framework
+// value constructor of an attribute of attrType, as setStmt renders it.
+func settable(typ types.Type, attrType string) bool {
+	basic, ok := typ.Underlying().(*types.Basic)
+	if !ok {
+		return false
+	}
+	info := basic.Info()
+	if info&types.IsUntyped != 0 {
+		switch attrType {
+		case "string":
+			return info&types.IsString != 0
+		case "bool":
+			return info&types.IsBoolean != 0
+		case "int":
+			return info&types.IsInteger != 0
+		case "float":
+			return info&(types.IsInteger|types.IsFloat) != 0
+		}
+		return false
+	}
+	switch attrType {
+	case "string":
+		return types.AssignableTo(typ, types.Typ[types.String])
+	case "bool":
+		return types.AssignableTo(typ, types.Typ[types.Bool])
+	case "int":
+		// Rendered as types.Int64Value(int64(v)).
+		return info&types.IsInteger != 0
+	case "float":
+		return types.AssignableTo(typ, types.Typ[types.Float64])
+	}
+	return false
+}
@@ -0,0 +1,3 @@
+module github.com/hashicorp/terraform-plugin-framework-jsontypes
+
+go 1.22.0
@@ -0,0 +1,13 @@
+package jsontypes
+
+import "github.com/hashicorp/terraform-plugin-framework/types"
+
+type NormalizedType struct{}
+
+type Normalized struct {
+	types.String
+}
+
+func NewNormalizedValue(value string) Normalized { return Normalized{String: types.StringValue(value)} }
+func NewNormalizedNull() Normalized              { return Normalized{} }
+func NewNormalizedUnknown() Normalized           { return Normalized{} }
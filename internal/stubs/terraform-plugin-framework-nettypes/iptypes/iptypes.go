@@ -0,0 +1,27 @@
+package iptypes
+
+import "github.com/hashicorp/terraform-plugin-framework/types"
+
+type IPv4AddressType struct{}
+
+type IPv4Address struct {
+	types.String
+}
+
+func NewIPv4AddressValue(value string) IPv4Address {
+	return IPv4Address{String: types.StringValue(value)}
+}
+func NewIPv4AddressNull() IPv4Address    { return IPv4Address{} }
+func NewIPv4AddressUnknown() IPv4Address { return IPv4Address{} }
+
+type IPv6AddressType struct{}
+
+type IPv6Address struct {
+	types.String
+}
+
+func NewIPv6AddressValue(value string) IPv6Address {
+	return IPv6Address{String: types.StringValue(value)}
+}
+func NewIPv6AddressNull() IPv6Address    { return IPv6Address{} }
+func NewIPv6AddressUnknown() IPv6Address { return IPv6Address{} }
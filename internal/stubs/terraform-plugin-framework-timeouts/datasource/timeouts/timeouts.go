@@ -0,0 +1,23 @@
+package timeouts
+
+import (
+	"context"
+	"time"
+
+	"github.com/hashicorp/terraform-plugin-framework/datasource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+)
+
+type Value struct{}
+
+func Block(ctx context.Context) schema.Block {
+	return nil
+}
+
+func Attributes(ctx context.Context) schema.Attribute {
+	return nil
+}
+
+func (t Value) Read(ctx context.Context, defaultTimeout time.Duration) (time.Duration, diag.Diagnostics) {
+	return defaultTimeout, nil
+}
@@ -0,0 +1,3 @@
+module github.com/hashicorp/terraform-plugin-framework-timeouts
+
+go 1.22.0
@@ -0,0 +1,46 @@
+package timeouts
+
+import (
+	"context"
+	"time"
+
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema"
+)
+
+type Opts struct {
+	Create            bool
+	Read              bool
+	Update            bool
+	Delete            bool
+	CreateDescription string
+	ReadDescription   string
+	UpdateDescription string
+	DeleteDescription string
+}
+
+type Value struct{}
+
+func Block(ctx context.Context, opts Opts) schema.Block {
+	return nil
+}
+
+func Attributes(ctx context.Context, opts Opts) schema.Attribute {
+	return nil
+}
+
+func (t Value) Create(ctx context.Context, defaultTimeout time.Duration) (time.Duration, diag.Diagnostics) {
+	return defaultTimeout, nil
+}
+
+func (t Value) Read(ctx context.Context, defaultTimeout time.Duration) (time.Duration, diag.Diagnostics) {
+	return defaultTimeout, nil
+}
+
+func (t Value) Update(ctx context.Context, defaultTimeout time.Duration) (time.Duration, diag.Diagnostics) {
+	return defaultTimeout, nil
+}
+
+func (t Value) Delete(ctx context.Context, defaultTimeout time.Duration) (time.Duration, diag.Diagnostics) {
+	return defaultTimeout, nil
+}
@@ -0,0 +1,30 @@
+package timetypes
+
+import (
+	"time"
+
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+)
+
+type RFC3339Type struct{}
+
+type RFC3339 struct {
+	types.String
+}
+
+func NewRFC3339Value(value string) (RFC3339, diag.Diagnostics) {
+	return RFC3339{String: types.StringValue(value)}, nil
+}
+
+func NewRFC3339ValueMust(value string) RFC3339 { return RFC3339{String: types.StringValue(value)} }
+func NewRFC3339TimeValue(value time.Time) RFC3339 {
+	return RFC3339{String: types.StringValue(value.Format(time.RFC3339))}
+}
+func NewRFC3339Null() RFC3339    { return RFC3339{} }
+func NewRFC3339Unknown() RFC3339 { return RFC3339{} }
+
+func (v RFC3339) ValueRFC3339Time() (time.Time, diag.Diagnostics) {
+	t, _ := time.Parse(time.RFC3339, v.ValueString())
+	return t, nil
+}
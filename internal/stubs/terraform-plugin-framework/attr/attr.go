@@ -0,0 +1,5 @@
+package attr
+
+type Type interface{}
+
+type Value interface{}
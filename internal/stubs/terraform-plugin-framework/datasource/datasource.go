@@ -1,3 +1,52 @@
 package datasource
 
-type DataSource interface{}
+import (
+	"context"
+
+	"github.com/hashicorp/terraform-plugin-framework/datasource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+	"github.com/hashicorp/terraform-plugin-framework/tfsdk"
+)
+
+type DataSource interface {
+	Metadata(context.Context, MetadataRequest, *MetadataResponse)
+	Schema(context.Context, SchemaRequest, *SchemaResponse)
+	Read(context.Context, ReadRequest, *ReadResponse)
+}
+
+type DataSourceWithConfigure interface {
+	DataSource
+	Configure(context.Context, ConfigureRequest, *ConfigureResponse)
+}
+
+type MetadataRequest struct {
+	ProviderTypeName string
+}
+
+type MetadataResponse struct {
+	TypeName string
+}
+
+type SchemaRequest struct{}
+
+type SchemaResponse struct {
+	Schema      schema.Schema
+	Diagnostics diag.Diagnostics
+}
+
+type ConfigureRequest struct {
+	ProviderData interface{}
+}
+
+type ConfigureResponse struct {
+	Diagnostics diag.Diagnostics
+}
+
+type ReadRequest struct {
+	Config tfsdk.Config
+}
+
+type ReadResponse struct {
+	State       tfsdk.State
+	Diagnostics diag.Diagnostics
+}
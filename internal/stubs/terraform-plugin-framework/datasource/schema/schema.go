@@ -0,0 +1,130 @@
+package schema
+
+import (
+	"github.com/hashicorp/terraform-plugin-framework/schema/validator"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/hashicorp/terraform-plugin-framework/types/basetypes"
+)
+
+type Schema struct {
+	Description string
+	Attributes  map[string]Attribute
+	Blocks      map[string]Block
+}
+
+type Block interface{}
+
+type Attribute interface{}
+
+type NestedBlockObject struct {
+	Attributes map[string]Attribute
+	Blocks     map[string]Block
+}
+
+type StringAttribute struct {
+	Optional    bool
+	Required    bool
+	Computed    bool
+	Sensitive   bool
+	Description string
+	CustomType  basetypes.StringTypable
+	Validators  []validator.String
+}
+
+type BoolAttribute struct {
+	Optional    bool
+	Required    bool
+	Computed    bool
+	Sensitive   bool
+	Description string
+}
+
+type Int64Attribute struct {
+	Optional    bool
+	Required    bool
+	Computed    bool
+	Sensitive   bool
+	Description string
+}
+
+type Float64Attribute struct {
+	Optional    bool
+	Required    bool
+	Computed    bool
+	Sensitive   bool
+	Description string
+}
+
+type ListAttribute struct {
+	Optional    bool
+	Required    bool
+	Computed    bool
+	Sensitive   bool
+	Description string
+	ElementType types.Type
+}
+
+type SetAttribute struct {
+	Optional    bool
+	Required    bool
+	Computed    bool
+	Sensitive   bool
+	Description string
+	ElementType types.Type
+}
+
+type MapAttribute struct {
+	Optional    bool
+	Required    bool
+	Computed    bool
+	Sensitive   bool
+	Description string
+	ElementType types.Type
+}
+
+type ListNestedBlock struct {
+	Description  string
+	NestedObject NestedBlockObject
+}
+
+type SetNestedBlock struct {
+	Description  string
+	NestedObject NestedBlockObject
+}
+
+type SingleNestedBlock struct {
+	Description string
+	Attributes  map[string]Attribute
+	Blocks      map[string]Block
+}
+
+type SingleNestedAttribute struct {
+	Description string
+	Attributes  map[string]Attribute
+	Required    bool
+	Optional    bool
+	Computed    bool
+	Sensitive   bool
+}
+
+type NestedAttributeObject struct {
+	Attributes map[string]Attribute
+}
+
+type ListNestedAttribute struct {
+	Description  string
+	NestedObject NestedAttributeObject
+	Required     bool
+	Optional     bool
+	Computed     bool
+	Sensitive    bool
+}
+
+type SetNestedAttribute struct {
+	Description  string
+	NestedObject NestedAttributeObject
+	Required     bool
+	Optional     bool
+	Computed     bool
+	Sensitive    bool
+}
@@ -0,0 +1,38 @@
+package diag
+
+import "github.com/hashicorp/terraform-plugin-framework/path"
+
+type Diagnostic interface {
+	Summary() string
+	Detail() string
+}
+
+type Diagnostics []Diagnostic
+
+func (d *Diagnostics) Append(in ...Diagnostic) {
+	*d = append(*d, in...)
+}
+
+func (d *Diagnostics) AddError(summary, detail string) {
+	*d = append(*d, basic{summary: summary, detail: detail})
+}
+
+func (d *Diagnostics) AddAttributeError(path path.Path, summary, detail string) {
+	*d = append(*d, basic{summary: summary, detail: detail})
+}
+
+func (d *Diagnostics) AddWarning(summary, detail string) {
+	*d = append(*d, basic{summary: summary, detail: detail})
+}
+
+func (d Diagnostics) HasError() bool {
+	return len(d) > 0
+}
+
+type basic struct {
+	summary string
+	detail  string
+}
+
+func (b basic) Summary() string { return b.summary }
+func (b basic) Detail() string  { return b.detail }
@@ -0,0 +1,13 @@
+package path
+
+type Path struct {
+	steps []string
+}
+
+func Root(name string) Path {
+	return Path{steps: []string{name}}
+}
+
+func (p Path) AtName(name string) Path {
+	return Path{steps: append(append([]string{}, p.steps...), name)}
+}
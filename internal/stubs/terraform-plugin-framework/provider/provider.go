@@ -4,8 +4,11 @@ import (
 	"context"
 
 	"github.com/hashicorp/terraform-plugin-framework/datasource"
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+	"github.com/hashicorp/terraform-plugin-framework/provider/metaschema"
 	"github.com/hashicorp/terraform-plugin-framework/provider/schema"
 	"github.com/hashicorp/terraform-plugin-framework/resource"
+	"github.com/hashicorp/terraform-plugin-framework/tfsdk"
 )
 
 type Provider interface {
@@ -16,6 +19,11 @@ type Provider interface {
 	Resources(context.Context) []func() resource.Resource
 }
 
+type ProviderWithMetaSchema interface {
+	Provider
+	MetaSchema(context.Context, MetaSchemaRequest, *MetaSchemaResponse)
+}
+
 type MetadataRequest struct{}
 type MetadataResponse struct {
 	TypeName string
@@ -26,8 +34,16 @@ type SchemaResponse struct {
 	Schema schema.Schema
 }
 
-type ConfigureRequest struct{}
+type MetaSchemaRequest struct{}
+type MetaSchemaResponse struct {
+	Schema metaschema.Schema
+}
+
+type ConfigureRequest struct {
+	Config tfsdk.Config
+}
 type ConfigureResponse struct {
 	DataSourceData interface{}
+	Diagnostics    diag.Diagnostics
 	ResourceData   interface{}
 }
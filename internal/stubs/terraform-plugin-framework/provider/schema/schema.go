@@ -1,18 +1,35 @@
 package schema
 
-import "github.com/hashicorp/terraform-plugin-framework/types"
+import (
+	"context"
+
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/hashicorp/terraform-plugin-framework/types/basetypes"
+	"github.com/hashicorp/terraform-plugin-go/tftypes"
+)
 
 type Schema struct {
 	Attributes map[string]Attribute
 	Blocks     map[string]Block
 }
 
+func (s Schema) Type() basetypes.ObjectTypable {
+	return objectType{}
+}
+
+type objectType struct{}
+
+func (objectType) TerraformType(context.Context) tftypes.Type {
+	return tftypes.Object{}
+}
+
 type Block interface{}
 
 type Attribute interface{}
 
 type NestedBlockObject struct {
 	Attributes map[string]Attribute
+	Blocks     map[string]Block
 }
 
 type StringAttribute struct {
@@ -83,3 +100,23 @@ type SetNestedBlock struct {
 	Description  string
 	NestedObject NestedBlockObject
 }
+
+type NestedAttributeObject struct {
+	Attributes map[string]Attribute
+}
+
+type ListNestedAttribute struct {
+	Description  string
+	NestedObject NestedAttributeObject
+	Required     bool
+	Optional     bool
+	Sensitive    bool
+}
+
+type SetNestedAttribute struct {
+	Description  string
+	NestedObject NestedAttributeObject
+	Required     bool
+	Optional     bool
+	Sensitive    bool
+}
@@ -1,10 +1,45 @@
 package providerserver
 
 import (
+	"context"
+
 	"github.com/hashicorp/terraform-plugin-framework/provider"
 	"github.com/hashicorp/terraform-plugin-go/tfprotov5"
+	"github.com/hashicorp/terraform-plugin-go/tfprotov6"
 )
 
-func NewProtocol5(_ provider.Provider) func() tfprotov5.ProviderServer {
-	return func() tfprotov5.ProviderServer { return nil }
+func NewProtocol5(p provider.Provider) func() tfprotov5.ProviderServer {
+	return func() tfprotov5.ProviderServer { return &server5{provider: p} }
+}
+
+func NewProtocol6(p provider.Provider) func() tfprotov6.ProviderServer {
+	return func() tfprotov6.ProviderServer { return &server6{provider: p} }
+}
+
+type server5 struct {
+	provider provider.Provider
+}
+
+func (s *server5) ConfigureProvider(ctx context.Context, _ *tfprotov5.ConfigureProviderRequest) (*tfprotov5.ConfigureProviderResponse, error) {
+	var resp provider.ConfigureResponse
+	s.provider.Configure(ctx, provider.ConfigureRequest{}, &resp)
+	out := &tfprotov5.ConfigureProviderResponse{}
+	for _, d := range resp.Diagnostics {
+		out.Diagnostics = append(out.Diagnostics, &tfprotov5.Diagnostic{Severity: tfprotov5.DiagnosticSeverityError, Summary: d.Summary(), Detail: d.Detail()})
+	}
+	return out, nil
+}
+
+type server6 struct {
+	provider provider.Provider
+}
+
+func (s *server6) ConfigureProvider(ctx context.Context, _ *tfprotov6.ConfigureProviderRequest) (*tfprotov6.ConfigureProviderResponse, error) {
+	var resp provider.ConfigureResponse
+	s.provider.Configure(ctx, provider.ConfigureRequest{}, &resp)
+	out := &tfprotov6.ConfigureProviderResponse{}
+	for _, d := range resp.Diagnostics {
+		out.Diagnostics = append(out.Diagnostics, &tfprotov6.Diagnostic{Severity: tfprotov6.DiagnosticSeverityError, Summary: d.Summary(), Detail: d.Detail()})
+	}
+	return out, nil
 }
@@ -1,3 +1,140 @@
 package resource
 
-type Resource interface{}
+import (
+	"context"
+
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+	"github.com/hashicorp/terraform-plugin-framework/path"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/tfsdk"
+)
+
+type Resource interface {
+	Metadata(context.Context, MetadataRequest, *MetadataResponse)
+	Schema(context.Context, SchemaRequest, *SchemaResponse)
+	Create(context.Context, CreateRequest, *CreateResponse)
+	Read(context.Context, ReadRequest, *ReadResponse)
+	Update(context.Context, UpdateRequest, *UpdateResponse)
+	Delete(context.Context, DeleteRequest, *DeleteResponse)
+}
+
+type ResourceWithConfigure interface {
+	Resource
+	Configure(context.Context, ConfigureRequest, *ConfigureResponse)
+}
+
+type ResourceWithImportState interface {
+	Resource
+	ImportState(context.Context, ImportStateRequest, *ImportStateResponse)
+}
+
+type ResourceWithUpgradeState interface {
+	Resource
+	UpgradeState(context.Context) map[int64]StateUpgrader
+}
+
+type ResourceWithModifyPlan interface {
+	Resource
+	ModifyPlan(context.Context, ModifyPlanRequest, *ModifyPlanResponse)
+}
+
+type MetadataRequest struct {
+	ProviderTypeName string
+}
+
+type MetadataResponse struct {
+	TypeName string
+}
+
+type SchemaRequest struct{}
+
+type SchemaResponse struct {
+	Schema      schema.Schema
+	Diagnostics diag.Diagnostics
+}
+
+type ConfigureRequest struct {
+	ProviderData interface{}
+}
+
+type ConfigureResponse struct {
+	Diagnostics diag.Diagnostics
+}
+
+type CreateRequest struct {
+	Config tfsdk.Config
+	Plan   tfsdk.Plan
+}
+
+type CreateResponse struct {
+	State       tfsdk.State
+	Diagnostics diag.Diagnostics
+}
+
+type ReadRequest struct {
+	State tfsdk.State
+}
+
+type ReadResponse struct {
+	State       tfsdk.State
+	Diagnostics diag.Diagnostics
+}
+
+type UpdateRequest struct {
+	Config tfsdk.Config
+	Plan   tfsdk.Plan
+	State  tfsdk.State
+}
+
+type UpdateResponse struct {
+	State       tfsdk.State
+	Diagnostics diag.Diagnostics
+}
+
+type DeleteRequest struct {
+	State tfsdk.State
+}
+
+type DeleteResponse struct {
+	State       tfsdk.State
+	Diagnostics diag.Diagnostics
+}
+
+type ImportStateRequest struct {
+	ID string
+}
+
+type ImportStateResponse struct {
+	State       tfsdk.State
+	Diagnostics diag.Diagnostics
+}
+
+func ImportStatePassthroughID(ctx context.Context, attrPath path.Path, req ImportStateRequest, resp *ImportStateResponse) {
+	resp.Diagnostics.Append(resp.State.SetAttribute(ctx, attrPath, req.ID)...)
+}
+
+type StateUpgrader struct {
+	PriorSchema   *schema.Schema
+	StateUpgrader func(context.Context, UpgradeStateRequest, *UpgradeStateResponse)
+}
+
+type UpgradeStateRequest struct {
+	State *tfsdk.State
+}
+
+type UpgradeStateResponse struct {
+	State       tfsdk.State
+	Diagnostics diag.Diagnostics
+}
+
+type ModifyPlanRequest struct {
+	Config tfsdk.Config
+	Plan   tfsdk.Plan
+	State  tfsdk.State
+}
+
+type ModifyPlanResponse struct {
+	Plan            tfsdk.Plan
+	RequiresReplace []path.Path
+	Diagnostics     diag.Diagnostics
+}
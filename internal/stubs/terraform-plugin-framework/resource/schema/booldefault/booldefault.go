@@ -0,0 +1,9 @@
+package booldefault
+
+import "github.com/hashicorp/terraform-plugin-framework/resource/schema/defaults"
+
+type staticDefault struct{}
+
+func (staticDefault) Description() string { return "" }
+
+func StaticBool(_ bool) defaults.Bool { return staticDefault{} }
@@ -0,0 +1,25 @@
+package boolplanmodifier
+
+import (
+	"context"
+
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/planmodifier"
+)
+
+type modifier struct{}
+
+func (modifier) Description() string { return "" }
+
+func UseStateForUnknown() planmodifier.Bool { return modifier{} }
+
+func RequiresReplace() planmodifier.Bool { return modifier{} }
+
+type RequiresReplaceIfFunc func(context.Context, planmodifier.BoolRequest, *RequiresReplaceIfFuncResponse)
+
+type RequiresReplaceIfFuncResponse struct {
+	Diagnostics     diag.Diagnostics
+	RequiresReplace bool
+}
+
+func RequiresReplaceIf(_ RequiresReplaceIfFunc, _, _ string) planmodifier.Bool { return modifier{} }
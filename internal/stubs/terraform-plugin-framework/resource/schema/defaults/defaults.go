@@ -0,0 +1,29 @@
+package defaults
+
+type String interface {
+	Description() string
+}
+
+type Bool interface {
+	Description() string
+}
+
+type Int64 interface {
+	Description() string
+}
+
+type Float64 interface {
+	Description() string
+}
+
+type List interface {
+	Description() string
+}
+
+type Set interface {
+	Description() string
+}
+
+type Map interface {
+	Description() string
+}
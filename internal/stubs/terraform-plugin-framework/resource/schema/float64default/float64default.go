@@ -0,0 +1,9 @@
+package float64default
+
+import "github.com/hashicorp/terraform-plugin-framework/resource/schema/defaults"
+
+type staticDefault struct{}
+
+func (staticDefault) Description() string { return "" }
+
+func StaticFloat64(_ float64) defaults.Float64 { return staticDefault{} }
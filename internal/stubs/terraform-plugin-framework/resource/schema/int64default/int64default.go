@@ -0,0 +1,9 @@
+package int64default
+
+import "github.com/hashicorp/terraform-plugin-framework/resource/schema/defaults"
+
+type staticDefault struct{}
+
+func (staticDefault) Description() string { return "" }
+
+func StaticInt64(_ int64) defaults.Int64 { return staticDefault{} }
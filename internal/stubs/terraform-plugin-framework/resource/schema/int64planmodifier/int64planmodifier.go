@@ -0,0 +1,25 @@
+package int64planmodifier
+
+import (
+	"context"
+
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/planmodifier"
+)
+
+type modifier struct{}
+
+func (modifier) Description() string { return "" }
+
+func UseStateForUnknown() planmodifier.Int64 { return modifier{} }
+
+func RequiresReplace() planmodifier.Int64 { return modifier{} }
+
+type RequiresReplaceIfFunc func(context.Context, planmodifier.Int64Request, *RequiresReplaceIfFuncResponse)
+
+type RequiresReplaceIfFuncResponse struct {
+	Diagnostics     diag.Diagnostics
+	RequiresReplace bool
+}
+
+func RequiresReplaceIf(_ RequiresReplaceIfFunc, _, _ string) planmodifier.Int64 { return modifier{} }
@@ -0,0 +1,25 @@
+package listplanmodifier
+
+import (
+	"context"
+
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/planmodifier"
+)
+
+type modifier struct{}
+
+func (modifier) Description() string { return "" }
+
+func UseStateForUnknown() planmodifier.List { return modifier{} }
+
+func RequiresReplace() planmodifier.List { return modifier{} }
+
+type RequiresReplaceIfFunc func(context.Context, planmodifier.ListRequest, *RequiresReplaceIfFuncResponse)
+
+type RequiresReplaceIfFuncResponse struct {
+	Diagnostics     diag.Diagnostics
+	RequiresReplace bool
+}
+
+func RequiresReplaceIf(_ RequiresReplaceIfFunc, _, _ string) planmodifier.List { return modifier{} }
@@ -0,0 +1,25 @@
+package mapplanmodifier
+
+import (
+	"context"
+
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/planmodifier"
+)
+
+type modifier struct{}
+
+func (modifier) Description() string { return "" }
+
+func UseStateForUnknown() planmodifier.Map { return modifier{} }
+
+func RequiresReplace() planmodifier.Map { return modifier{} }
+
+type RequiresReplaceIfFunc func(context.Context, planmodifier.MapRequest, *RequiresReplaceIfFuncResponse)
+
+type RequiresReplaceIfFuncResponse struct {
+	Diagnostics     diag.Diagnostics
+	RequiresReplace bool
+}
+
+func RequiresReplaceIf(_ RequiresReplaceIfFunc, _, _ string) planmodifier.Map { return modifier{} }
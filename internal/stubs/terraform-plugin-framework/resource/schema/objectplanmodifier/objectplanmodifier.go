@@ -0,0 +1,11 @@
+package objectplanmodifier
+
+import "github.com/hashicorp/terraform-plugin-framework/resource/schema/planmodifier"
+
+type modifier struct{}
+
+func (modifier) Description() string { return "" }
+
+func UseStateForUnknown() planmodifier.Object { return modifier{} }
+
+func RequiresReplace() planmodifier.Object { return modifier{} }
@@ -0,0 +1,77 @@
+package planmodifier
+
+import "github.com/hashicorp/terraform-plugin-framework/types"
+
+type String interface {
+	Description() string
+}
+
+type Bool interface {
+	Description() string
+}
+
+type Int64 interface {
+	Description() string
+}
+
+type Float64 interface {
+	Description() string
+}
+
+type List interface {
+	Description() string
+}
+
+type Set interface {
+	Description() string
+}
+
+type Map interface {
+	Description() string
+}
+
+type Object interface {
+	Description() string
+}
+
+type StringRequest struct {
+	ConfigValue types.String
+	PlanValue   types.String
+	StateValue  types.String
+}
+
+type BoolRequest struct {
+	ConfigValue types.Bool
+	PlanValue   types.Bool
+	StateValue  types.Bool
+}
+
+type Int64Request struct {
+	ConfigValue types.Int64
+	PlanValue   types.Int64
+	StateValue  types.Int64
+}
+
+type Float64Request struct {
+	ConfigValue types.Float64
+	PlanValue   types.Float64
+	StateValue  types.Float64
+}
+
+type ListRequest struct {
+	ConfigValue types.List
+	PlanValue   types.List
+	StateValue  types.List
+}
+
+type SetRequest struct {
+	ConfigValue types.Set
+	PlanValue   types.Set
+	StateValue  types.Set
+}
+
+type MapRequest struct {
+	ConfigValue types.Map
+	PlanValue   types.Map
+	StateValue  types.Map
+}
@@ -0,0 +1,153 @@
+package schema
+
+import (
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/defaults"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/planmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/schema/validator"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/hashicorp/terraform-plugin-framework/types/basetypes"
+)
+
+type Schema struct {
+	Description string
+	Attributes  map[string]Attribute
+	Blocks      map[string]Block
+	Version     int64
+}
+
+type Block interface{}
+
+type Attribute interface{}
+
+type NestedBlockObject struct {
+	Attributes map[string]Attribute
+	Blocks     map[string]Block
+}
+
+type StringAttribute struct {
+	Optional      bool
+	Required      bool
+	Computed      bool
+	Sensitive     bool
+	Description   string
+	CustomType    basetypes.StringTypable
+	Validators    []validator.String
+	PlanModifiers []planmodifier.String
+	Default       defaults.String
+}
+
+type BoolAttribute struct {
+	Optional      bool
+	Required      bool
+	Computed      bool
+	Sensitive     bool
+	Description   string
+	PlanModifiers []planmodifier.Bool
+	Default       defaults.Bool
+}
+
+type Int64Attribute struct {
+	Optional      bool
+	Required      bool
+	Computed      bool
+	Sensitive     bool
+	Description   string
+	PlanModifiers []planmodifier.Int64
+	Default       defaults.Int64
+}
+
+type Float64Attribute struct {
+	Optional      bool
+	Required      bool
+	Computed      bool
+	Sensitive     bool
+	Description   string
+	PlanModifiers []planmodifier.Float64
+	Default       defaults.Float64
+}
+
+type ListAttribute struct {
+	Optional      bool
+	Required      bool
+	Computed      bool
+	Sensitive     bool
+	Description   string
+	PlanModifiers []planmodifier.List
+	Default       defaults.List
+	ElementType   types.Type
+}
+
+type SetAttribute struct {
+	Optional      bool
+	Required      bool
+	Computed      bool
+	Sensitive     bool
+	Description   string
+	PlanModifiers []planmodifier.Set
+	Default       defaults.Set
+	ElementType   types.Type
+}
+
+type MapAttribute struct {
+	Optional      bool
+	Required      bool
+	Computed      bool
+	Sensitive     bool
+	Description   string
+	PlanModifiers []planmodifier.Map
+	Default       defaults.Map
+	ElementType   types.Type
+}
+
+type ListNestedBlock struct {
+	Description   string
+	NestedObject  NestedBlockObject
+	PlanModifiers []planmodifier.List
+}
+
+type SetNestedBlock struct {
+	Description   string
+	NestedObject  NestedBlockObject
+	PlanModifiers []planmodifier.Set
+}
+
+type SingleNestedBlock struct {
+	Description   string
+	Attributes    map[string]Attribute
+	Blocks        map[string]Block
+	PlanModifiers []planmodifier.Object
+}
+
+type SingleNestedAttribute struct {
+	Description   string
+	Attributes    map[string]Attribute
+	Required      bool
+	Optional      bool
+	Computed      bool
+	Sensitive     bool
+	PlanModifiers []planmodifier.Object
+}
+
+type NestedAttributeObject struct {
+	Attributes map[string]Attribute
+}
+
+type ListNestedAttribute struct {
+	Description   string
+	NestedObject  NestedAttributeObject
+	Required      bool
+	Optional      bool
+	Computed      bool
+	Sensitive     bool
+	PlanModifiers []planmodifier.List
+}
+
+type SetNestedAttribute struct {
+	Description   string
+	NestedObject  NestedAttributeObject
+	Required      bool
+	Optional      bool
+	Computed      bool
+	Sensitive     bool
+	PlanModifiers []planmodifier.Set
+}
@@ -0,0 +1,9 @@
+package stringdefault
+
+import "github.com/hashicorp/terraform-plugin-framework/resource/schema/defaults"
+
+type staticDefault struct{}
+
+func (staticDefault) Description() string { return "" }
+
+func StaticString(_ string) defaults.String { return staticDefault{} }
@@ -0,0 +1,28 @@
+package validator
+
+import (
+	"context"
+
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+	"github.com/hashicorp/terraform-plugin-framework/path"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+)
+
+type Describer interface {
+	Description(context.Context) string
+	MarkdownDescription(context.Context) string
+}
+
+type String interface {
+	Describer
+	ValidateString(context.Context, StringRequest, *StringResponse)
+}
+
+type StringRequest struct {
+	Path        path.Path
+	ConfigValue types.String
+}
+
+type StringResponse struct {
+	Diagnostics diag.Diagnostics
+}
@@ -0,0 +1,61 @@
+package tfsdk
+
+import (
+	"context"
+
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+	"github.com/hashicorp/terraform-plugin-framework/path"
+	"github.com/hashicorp/terraform-plugin-go/tftypes"
+)
+
+type Config struct{}
+
+func (c Config) Get(_ context.Context, _ interface{}) diag.Diagnostics {
+	return nil
+}
+
+func (c Config) GetAttribute(_ context.Context, _ path.Path, _ interface{}) diag.Diagnostics {
+	return nil
+}
+
+type Plan struct {
+	Raw tftypes.Value
+}
+
+func (p Plan) Get(_ context.Context, _ interface{}) diag.Diagnostics {
+	return nil
+}
+
+func (p Plan) GetAttribute(_ context.Context, _ path.Path, _ interface{}) diag.Diagnostics {
+	return nil
+}
+
+func (p *Plan) Set(_ context.Context, _ interface{}) diag.Diagnostics {
+	return nil
+}
+
+func (p *Plan) SetAttribute(_ context.Context, _ path.Path, _ interface{}) diag.Diagnostics {
+	return nil
+}
+
+type State struct {
+	Raw tftypes.Value
+}
+
+func (s State) Get(_ context.Context, _ interface{}) diag.Diagnostics {
+	return nil
+}
+
+func (s State) GetAttribute(_ context.Context, _ path.Path, _ interface{}) diag.Diagnostics {
+	return nil
+}
+
+func (s *State) Set(_ context.Context, _ interface{}) diag.Diagnostics {
+	return nil
+}
+
+func (s *State) SetAttribute(_ context.Context, _ path.Path, _ interface{}) diag.Diagnostics {
+	return nil
+}
+
+func (s *State) RemoveResource(_ context.Context) {}
@@ -0,0 +1,17 @@
+package basetypes
+
+import (
+	"context"
+
+	"github.com/hashicorp/terraform-plugin-go/tftypes"
+)
+
+type StringTypable interface{}
+
+type StringValuable interface {
+	ValueString() string
+}
+
+type ObjectTypable interface {
+	TerraformType(context.Context) tftypes.Type
+}
@@ -1,6 +1,8 @@
 package types
 
-type Type interface{}
+import "github.com/hashicorp/terraform-plugin-framework/attr"
+
+type Type = attr.Type
 
 type baseType struct{}
 
@@ -9,4 +11,98 @@ var (
 	BoolType    Type = baseType{}
 	Int64Type   Type = baseType{}
 	Float64Type Type = baseType{}
+	NumberType  Type = baseType{}
 )
+
+type ListType struct {
+	ElemType Type
+}
+
+type SetType struct {
+	ElemType Type
+}
+
+type MapType struct {
+	ElemType Type
+}
+
+type ObjectType struct {
+	AttrTypes map[string]Type
+}
+
+type String struct {
+	value string
+}
+
+func StringValue(v string) String { return String{value: v} }
+func StringNull() String          { return String{} }
+func StringUnknown() String       { return String{} }
+
+func (v String) ValueString() string { return v.value }
+func (v String) IsNull() bool        { return false }
+func (v String) IsUnknown() bool     { return false }
+
+type Bool struct {
+	value bool
+}
+
+func BoolValue(v bool) Bool { return Bool{value: v} }
+func BoolNull() Bool        { return Bool{} }
+func BoolUnknown() Bool     { return Bool{} }
+
+func (v Bool) ValueBool() bool { return v.value }
+func (v Bool) IsNull() bool    { return false }
+func (v Bool) IsUnknown() bool { return false }
+
+type Int64 struct {
+	value int64
+}
+
+func Int64Value(v int64) Int64 { return Int64{value: v} }
+func Int64Null() Int64         { return Int64{} }
+func Int64Unknown() Int64      { return Int64{} }
+
+func (v Int64) ValueInt64() int64 { return v.value }
+func (v Int64) IsNull() bool      { return false }
+func (v Int64) IsUnknown() bool   { return false }
+
+type Float64 struct {
+	value float64
+}
+
+func Float64Value(v float64) Float64 { return Float64{value: v} }
+func Float64Null() Float64           { return Float64{} }
+func Float64Unknown() Float64        { return Float64{} }
+
+func (v Float64) ValueFloat64() float64 { return v.value }
+func (v Float64) IsNull() bool          { return false }
+func (v Float64) IsUnknown() bool       { return false }
+
+type List struct{}
+
+func ListUnknown(_ Type) List { return List{} }
+
+func (v List) Elements() []attr.Value { return nil }
+func (v List) IsNull() bool           { return false }
+func (v List) IsUnknown() bool        { return false }
+
+type Set struct{}
+
+func SetUnknown(_ Type) Set { return Set{} }
+
+func (v Set) Elements() []attr.Value { return nil }
+func (v Set) IsNull() bool           { return false }
+func (v Set) IsUnknown() bool        { return false }
+
+type Map struct{}
+
+func MapUnknown(_ Type) Map { return Map{} }
+
+func (v Map) Elements() map[string]attr.Value { return nil }
+func (v Map) IsNull() bool                    { return false }
+func (v Map) IsUnknown() bool                 { return false }
+
+type Object struct{}
+
+func (v Object) IsNull() bool    { return false }
+func (v Object) IsUnknown() bool { return false }
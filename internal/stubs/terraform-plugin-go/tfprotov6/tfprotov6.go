@@ -0,0 +1,43 @@
+package tfprotov6
+
+import (
+	"context"
+
+	"github.com/hashicorp/terraform-plugin-go/tftypes"
+)
+
+type ProviderServer interface {
+	ConfigureProvider(context.Context, *ConfigureProviderRequest) (*ConfigureProviderResponse, error)
+}
+
+type ConfigureProviderRequest struct {
+	TerraformVersion string
+	Config           *DynamicValue
+}
+
+type ConfigureProviderResponse struct {
+	Diagnostics []*Diagnostic
+}
+
+type DiagnosticSeverity int32
+
+const (
+	DiagnosticSeverityInvalid DiagnosticSeverity = 0
+	DiagnosticSeverityError   DiagnosticSeverity = 1
+	DiagnosticSeverityWarning DiagnosticSeverity = 2
+)
+
+type Diagnostic struct {
+	Severity DiagnosticSeverity
+	Summary  string
+	Detail   string
+}
+
+type DynamicValue struct {
+	MsgPack []byte
+	JSON    []byte
+}
+
+func NewDynamicValue(_ tftypes.Type, _ tftypes.Value) (DynamicValue, error) {
+	return DynamicValue{}, nil
+}
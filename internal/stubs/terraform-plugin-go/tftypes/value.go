@@ -0,0 +1,28 @@
+package tftypes
+
+type Type interface {
+	String() string
+}
+
+type Object struct {
+	AttributeTypes map[string]Type
+}
+
+func (o Object) String() string { return "tftypes.Object" }
+
+type Value struct {
+	typ  Type
+	null bool
+}
+
+func NewValue(t Type, val interface{}) Value {
+	return Value{typ: t, null: val == nil}
+}
+
+func (v Value) Type() Type {
+	return v.typ
+}
+
+func (v Value) IsNull() bool {
+	return v.null
+}
@@ -2,16 +2,37 @@ package tf5muxserver
 
 import (
 	"context"
+	"fmt"
 
 	"github.com/hashicorp/terraform-plugin-go/tfprotov5"
 )
 
-type MuxServer struct{}
+type MuxServer struct {
+	servers []tfprotov5.ProviderServer
+}
 
-func NewMuxServer(_ context.Context, _ ...func() tfprotov5.ProviderServer) (*MuxServer, error) {
-	return &MuxServer{}, nil
+func NewMuxServer(_ context.Context, servers ...func() tfprotov5.ProviderServer) (*MuxServer, error) {
+	m := &MuxServer{}
+	for _, server := range servers {
+		m.servers = append(m.servers, server())
+	}
+	return m, nil
 }
 
 func (m *MuxServer) ProviderServer() tfprotov5.ProviderServer {
-	return nil
+	return m
+}
+
+// ConfigureProvider configures every server in order, like the real mux
+// server.
+func (m *MuxServer) ConfigureProvider(ctx context.Context, req *tfprotov5.ConfigureProviderRequest) (*tfprotov5.ConfigureProviderResponse, error) {
+	var diags []*tfprotov5.Diagnostic
+	for _, server := range m.servers {
+		resp, err := server.ConfigureProvider(ctx, req)
+		if err != nil {
+			return resp, fmt.Errorf("error configuring %T: %w", server, err)
+		}
+		diags = append(diags, resp.Diagnostics...)
+	}
+	return &tfprotov5.ConfigureProviderResponse{Diagnostics: diags}, nil
 }
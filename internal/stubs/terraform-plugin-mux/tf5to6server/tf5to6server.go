@@ -0,0 +1,32 @@
+package tf5to6server
+
+import (
+	"context"
+
+	"github.com/hashicorp/terraform-plugin-go/tfprotov5"
+	"github.com/hashicorp/terraform-plugin-go/tfprotov6"
+)
+
+func UpgradeServer(_ context.Context, v5server func() tfprotov5.ProviderServer) (tfprotov6.ProviderServer, error) {
+	return v5tov6Server{v5Server: v5server()}, nil
+}
+
+type v5tov6Server struct {
+	v5Server tfprotov5.ProviderServer
+}
+
+func (s v5tov6Server) ConfigureProvider(ctx context.Context, req *tfprotov6.ConfigureProviderRequest) (*tfprotov6.ConfigureProviderResponse, error) {
+	v5req := &tfprotov5.ConfigureProviderRequest{TerraformVersion: req.TerraformVersion}
+	if req.Config != nil {
+		v5req.Config = &tfprotov5.DynamicValue{MsgPack: req.Config.MsgPack, JSON: req.Config.JSON}
+	}
+	v5resp, err := s.v5Server.ConfigureProvider(ctx, v5req)
+	if err != nil {
+		return nil, err
+	}
+	resp := &tfprotov6.ConfigureProviderResponse{}
+	for _, d := range v5resp.Diagnostics {
+		resp.Diagnostics = append(resp.Diagnostics, &tfprotov6.Diagnostic{Severity: tfprotov6.DiagnosticSeverity(d.Severity), Summary: d.Summary, Detail: d.Detail})
+	}
+	return resp, nil
+}
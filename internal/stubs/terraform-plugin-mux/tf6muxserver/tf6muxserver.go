@@ -0,0 +1,38 @@
+package tf6muxserver
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/hashicorp/terraform-plugin-go/tfprotov6"
+)
+
+type MuxServer struct {
+	servers []tfprotov6.ProviderServer
+}
+
+func NewMuxServer(_ context.Context, servers ...func() tfprotov6.ProviderServer) (*MuxServer, error) {
+	m := &MuxServer{}
+	for _, server := range servers {
+		m.servers = append(m.servers, server())
+	}
+	return m, nil
+}
+
+func (m *MuxServer) ProviderServer() tfprotov6.ProviderServer {
+	return m
+}
+
+// ConfigureProvider configures every server in order, like the real mux
+// server.
+func (m *MuxServer) ConfigureProvider(ctx context.Context, req *tfprotov6.ConfigureProviderRequest) (*tfprotov6.ConfigureProviderResponse, error) {
+	var diags []*tfprotov6.Diagnostic
+	for _, server := range m.servers {
+		resp, err := server.ConfigureProvider(ctx, req)
+		if err != nil {
+			return resp, fmt.Errorf("error configuring %T: %w", server, err)
+		}
+		diags = append(diags, resp.Diagnostics...)
+	}
+	return &tfprotov6.ConfigureProviderResponse{Diagnostics: diags}, nil
+}
@@ -0,0 +1,16 @@
+package diag
+
+type Diagnostics []Diagnostic
+
+type Diagnostic struct {
+	Summary string
+	Detail  string
+}
+
+func FromErr(_ error) Diagnostics {
+	return nil
+}
+
+func Errorf(_ string, _ ...interface{}) Diagnostics {
+	return nil
+}
@@ -0,0 +1,47 @@
+package customdiff
+
+import (
+	"context"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+)
+
+type ResourceConditionFunc func(ctx context.Context, d *schema.ResourceDiff, meta interface{}) bool
+
+type ValueChangeConditionFunc func(ctx context.Context, old, new, meta interface{}) bool
+
+type ValueConditionFunc func(ctx context.Context, value, meta interface{}) bool
+
+type ValueChangeValidationFunc func(ctx context.Context, old, new, meta interface{}) error
+
+func All(funcs ...schema.CustomizeDiffFunc) schema.CustomizeDiffFunc {
+	return nil
+}
+
+func Sequence(funcs ...schema.CustomizeDiffFunc) schema.CustomizeDiffFunc {
+	return nil
+}
+
+func If(cond ResourceConditionFunc, f schema.CustomizeDiffFunc) schema.CustomizeDiffFunc {
+	return nil
+}
+
+func IfValueChange(key string, cond ValueChangeConditionFunc, f schema.CustomizeDiffFunc) schema.CustomizeDiffFunc {
+	return nil
+}
+
+func ForceNewIf(key string, f ResourceConditionFunc) schema.CustomizeDiffFunc {
+	return nil
+}
+
+func ForceNewIfChange(key string, f ValueChangeConditionFunc) schema.CustomizeDiffFunc {
+	return nil
+}
+
+func ComputedIf(key string, f ResourceConditionFunc) schema.CustomizeDiffFunc {
+	return nil
+}
+
+func ValidateChange(key string, f ValueChangeValidationFunc) schema.CustomizeDiffFunc {
+	return nil
+}
@@ -1,40 +1,240 @@
 package schema
 
-import "github.com/hashicorp/terraform-plugin-go/tfprotov5"
+import (
+	"context"
+	"time"
+
+	"github.com/hashicorp/terraform-plugin-go/tfprotov5"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+)
 
 type Provider struct {
 	Schema         map[string]*Schema
+	SchemaFunc     func() map[string]*Schema
 	ResourcesMap   map[string]*Resource
 	DataSourcesMap map[string]*Resource
+
+	ProviderMetaSchema map[string]*Schema
+
+	ConfigureFunc        ConfigureFunc
+	ConfigureContextFunc ConfigureContextFunc
+
+	meta interface{}
 }
 
+type ConfigureFunc func(*ResourceData) (interface{}, error)
+
+type ConfigureContextFunc func(context.Context, *ResourceData) (interface{}, diag.Diagnostics)
+
 func (p *Provider) Meta() interface{} {
-	return nil
+	return p.meta
+}
+
+func (p *Provider) SetMeta(v interface{}) {
+	p.meta = v
 }
 
 type Resource struct {
-	Schema map[string]*Schema
+	Schema     map[string]*Schema
+	SchemaFunc func() map[string]*Schema
+
+	Create CreateFunc
+	Read   ReadFunc
+	Update UpdateFunc
+	Delete DeleteFunc
+
+	CreateContext CreateContextFunc
+	ReadContext   ReadContextFunc
+	UpdateContext UpdateContextFunc
+	DeleteContext DeleteContextFunc
+
+	CreateWithoutTimeout CreateContextFunc
+	ReadWithoutTimeout   ReadContextFunc
+	UpdateWithoutTimeout UpdateContextFunc
+	DeleteWithoutTimeout DeleteContextFunc
+
+	Importer *ResourceImporter
+	Timeouts *ResourceTimeout
+
+	SchemaVersion  int
+	StateUpgraders []StateUpgrader
+
+	CustomizeDiff CustomizeDiffFunc
+}
+
+type CustomizeDiffFunc func(context.Context, *ResourceDiff, interface{}) error
+
+type ResourceDiff struct{}
+
+func (d *ResourceDiff) Get(_ string) interface{} {
+	return nil
+}
+
+func (d *ResourceDiff) GetChange(_ string) (interface{}, interface{}) {
+	return nil, nil
+}
+
+func (d *ResourceDiff) HasChange(_ string) bool {
+	return false
+}
+
+func (d *ResourceDiff) SetNewComputed(_ string) error {
+	return nil
+}
+
+func (d *ResourceDiff) ForceNew(_ string) error {
+	return nil
+}
+
+// StateUpgrader.Type is a cty.Type in the SDK.
+type StateUpgrader struct {
+	Version int
+	Type    interface{}
+	Upgrade StateUpgradeFunc
+}
+
+type StateUpgradeFunc func(ctx context.Context, rawState map[string]interface{}, meta interface{}) (map[string]interface{}, error)
+
+type CoreConfigSchema struct{}
+
+func (r *Resource) CoreConfigSchema() *CoreConfigSchema {
+	return &CoreConfigSchema{}
+}
+
+func (b *CoreConfigSchema) ImpliedType() interface{} {
+	return nil
+}
+
+type ResourceImporter struct {
+	State        StateFunc
+	StateContext StateContextFunc
+}
+
+type StateFunc func(*ResourceData, interface{}) ([]*ResourceData, error)
+type StateContextFunc func(context.Context, *ResourceData, interface{}) ([]*ResourceData, error)
+
+func ImportStatePassthrough(d *ResourceData, _ interface{}) ([]*ResourceData, error) {
+	return []*ResourceData{d}, nil
+}
+
+func ImportStatePassthroughContext(_ context.Context, d *ResourceData, _ interface{}) ([]*ResourceData, error) {
+	return []*ResourceData{d}, nil
+}
+
+type ResourceTimeout struct {
+	Create  *time.Duration
+	Read    *time.Duration
+	Update  *time.Duration
+	Delete  *time.Duration
+	Default *time.Duration
+}
+
+func DefaultTimeout(tx interface{}) *time.Duration {
+	var td time.Duration
+	switch raw := tx.(type) {
+	case time.Duration:
+		return &raw
+	case int64:
+		td = time.Duration(raw)
+	case float64:
+		td = time.Duration(int64(raw))
+	}
+	return &td
+}
+
+type CreateFunc func(*ResourceData, interface{}) error
+type ReadFunc func(*ResourceData, interface{}) error
+type UpdateFunc func(*ResourceData, interface{}) error
+type DeleteFunc func(*ResourceData, interface{}) error
+
+type CreateContextFunc func(context.Context, *ResourceData, interface{}) diag.Diagnostics
+type ReadContextFunc func(context.Context, *ResourceData, interface{}) diag.Diagnostics
+type UpdateContextFunc func(context.Context, *ResourceData, interface{}) diag.Diagnostics
+type DeleteContextFunc func(context.Context, *ResourceData, interface{}) diag.Diagnostics
+
+// ResourceData reads every key of its schema as the zero value of its type,
+// like the real ResourceData of a null configuration.
+type ResourceData struct {
+	schema map[string]*Schema
+}
+
+func (d *ResourceData) Get(key string) interface{} {
+	s, ok := d.schema[key]
+	if !ok {
+		return nil
+	}
+	switch s.Type {
+	case TypeString:
+		return ""
+	case TypeBool:
+		return false
+	case TypeInt:
+		return 0
+	case TypeFloat:
+		return 0.0
+	case TypeMap:
+		return map[string]interface{}{}
+	default:
+		return []interface{}{}
+	}
+}
+
+func (d *ResourceData) GetOk(_ string) (interface{}, bool) {
+	return nil, false
+}
+
+func (d *ResourceData) Set(_ string, _ interface{}) error {
+	return nil
+}
+
+func (d *ResourceData) Id() string {
+	return ""
+}
+
+func (d *ResourceData) SetId(_ string) {}
+
+func (d *ResourceData) HasChange(_ string) bool {
+	return false
 }
 
 type Schema struct {
-	Type          ValueType
-	Optional      bool
-	Required      bool
-	Computed      bool
-	Sensitive     bool
-	Description   string
-	Elem          interface{}
-	Default       interface{}
-	DefaultFunc   interface{}
-	Deprecated    string
-	ConflictsWith []string
-	ExactlyOneOf  []string
-	AtLeastOneOf  []string
-	RequiredWith  []string
-	MinItems      int
-	MaxItems      int
+	Type             ValueType
+	Optional         bool
+	Required         bool
+	Computed         bool
+	Sensitive        bool
+	ForceNew         bool
+	Description      string
+	Elem             interface{}
+	Default          interface{}
+	DefaultFunc      interface{}
+	Deprecated       string
+	ConflictsWith    []string
+	ExactlyOneOf     []string
+	AtLeastOneOf     []string
+	RequiredWith     []string
+	MinItems         int
+	MaxItems         int
+	ValidateFunc     SchemaValidateFunc
+	ValidateDiagFunc SchemaValidateDiagFunc
+	DiffSuppressFunc SchemaDiffSuppressFunc
+	ConfigMode       SchemaConfigMode
 }
 
+type SchemaConfigMode int
+
+const (
+	SchemaConfigModeAuto SchemaConfigMode = iota
+	SchemaConfigModeAttr
+	SchemaConfigModeBlock
+)
+
+type SchemaValidateFunc func(interface{}, string) ([]string, []error)
+
+type SchemaValidateDiagFunc func(interface{}, interface{}) diag.Diagnostics
+
+type SchemaDiffSuppressFunc func(k, oldValue, newValue string, d *ResourceData) bool
+
 type ValueType int
 
 const (
@@ -47,8 +247,40 @@ const (
 	TypeMap
 )
 
-func NewGRPCProviderServer(_ *Provider) tfprotov5.ProviderServer {
-	return nil
+func NewGRPCProviderServer(p *Provider) tfprotov5.ProviderServer {
+	return &grpcProviderServer{provider: p}
+}
+
+type grpcProviderServer struct {
+	provider *Provider
+}
+
+// ConfigureProvider runs the configure function and keeps its result as the
+// provider meta value, like the real server.
+func (s *grpcProviderServer) ConfigureProvider(ctx context.Context, _ *tfprotov5.ConfigureProviderRequest) (*tfprotov5.ConfigureProviderResponse, error) {
+	resp := &tfprotov5.ConfigureProviderResponse{}
+	var meta interface{}
+	var diags diag.Diagnostics
+	d := &ResourceData{schema: s.provider.Schema}
+	if s.provider.SchemaFunc != nil {
+		d.schema = s.provider.SchemaFunc()
+	}
+	switch {
+	case s.provider.ConfigureContextFunc != nil:
+		meta, diags = s.provider.ConfigureContextFunc(ctx, d)
+	case s.provider.ConfigureFunc != nil:
+		var err error
+		if meta, err = s.provider.ConfigureFunc(d); err != nil {
+			diags = append(diags, diag.Diagnostic{Summary: err.Error()})
+		}
+	}
+	for _, d := range diags {
+		resp.Diagnostics = append(resp.Diagnostics, &tfprotov5.Diagnostic{Severity: tfprotov5.DiagnosticSeverityError, Summary: d.Summary, Detail: d.Detail})
+	}
+	if len(diags) == 0 {
+		s.provider.SetMeta(meta)
+	}
+	return resp, nil
 }
 
 func EnvDefaultFunc(_ string, _ interface{}) interface{} {
@@ -58,3 +290,7 @@ func EnvDefaultFunc(_ string, _ interface{}) interface{} {
 func MultiEnvDefaultFunc(_ []string, _ interface{}) interface{} {
 	return nil
 }
+
+func NoopContext(_ context.Context, _ *ResourceData, _ interface{}) diag.Diagnostics {
+	return nil
+}
@@ -0,0 +1,12 @@
+package structure
+
+import "github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+
+func SuppressJsonDiff(k, old, new string, d *schema.ResourceData) bool {
+	return old == new
+}
+
+func NormalizeJsonString(jsonString interface{}) (string, error) {
+	s, _ := jsonString.(string)
+	return s, nil
+}
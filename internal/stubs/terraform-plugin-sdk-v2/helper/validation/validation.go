@@ -0,0 +1,31 @@
+package validation
+
+import "github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+
+func StringIsJSON(i interface{}, k string) ([]string, []error) {
+	return nil, nil
+}
+
+func IsRFC3339Time(i interface{}, k string) ([]string, []error) {
+	return nil, nil
+}
+
+func IsCIDR(i interface{}, k string) ([]string, []error) {
+	return nil, nil
+}
+
+func IsIPv4Address(i interface{}, k string) ([]string, []error) {
+	return nil, nil
+}
+
+func IsIPv6Address(i interface{}, k string) ([]string, []error) {
+	return nil, nil
+}
+
+func StringInSlice(valid []string, ignoreCase bool) schema.SchemaValidateFunc {
+	return nil
+}
+
+func ToDiagFunc(validator schema.SchemaValidateFunc) schema.SchemaValidateDiagFunc {
+	return nil
+}
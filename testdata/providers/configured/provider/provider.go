@@ -0,0 +1,42 @@
+package provider
+
+import "github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+
+func Provider() *schema.Provider {
+	return &schema.Provider{
+		Schema: map[string]*schema.Schema{
+			"endpoint": {
+				Type:        schema.TypeString,
+				Optional:    true,
+				Description: "Base API endpoint",
+			},
+			"api_key": {
+				Type:     schema.TypeString,
+				Optional: true,
+			},
+			"legacy_mode": {
+				Type:     schema.TypeBool,
+				Optional: true,
+			},
+		},
+		ResourcesMap: map[string]*schema.Resource{
+			"configured_widget": resourceWidget(),
+			"configured_gadget": resourceGadget(),
+		},
+		DataSourcesMap: map[string]*schema.Resource{
+			"configured_widget": dataSourceWidget(),
+		},
+	}
+}
+
+func resourceWidget() *schema.Resource {
+	return &schema.Resource{}
+}
+
+func resourceGadget() *schema.Resource {
+	return &schema.Resource{}
+}
+
+func dataSourceWidget() *schema.Resource {
+	return &schema.Resource{}
+}
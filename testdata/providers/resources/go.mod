@@ -0,0 +1,5 @@
+module github.com/acme/terraform-provider-resources
+
+go 1.22.0
+
+require github.com/hashicorp/terraform-plugin-sdk/v2 v2.0.0
@@ -0,0 +1,31 @@
+package provider
+
+import "context"
+
+// DefaultEndpoint is the API endpoint used when the provider sets none.
+const DefaultEndpoint = "https://api.example.com"
+
+type Client struct {
+	Endpoint string
+}
+
+func NewClient(endpoint string) *Client {
+	return &Client{Endpoint: endpoint}
+}
+
+type Widget struct {
+	Name string
+	Size int
+}
+
+func (c *Client) CreateWidget(_ context.Context, name string, _ int) (string, error) {
+	return name, nil
+}
+
+func (c *Client) GetWidget(_ context.Context, id string) (*Widget, error) {
+	return &Widget{Name: id}, nil
+}
+
+func (c *Client) DeleteWidget(_ context.Context, _ string) error {
+	return nil
+}
@@ -0,0 +1,20 @@
+package provider
+
+import "github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+
+var features = []string{"alpha", "gamma"}
+
+// dataSourceFeatures adds an attribute per feature in a loop over a package
+// level slice, which the migration unrolls.
+func dataSourceFeatures() *schema.Resource {
+	return &schema.Resource{
+		ReadContext: schema.NoopContext,
+		SchemaFunc: func() map[string]*schema.Schema {
+			s := map[string]*schema.Schema{}
+			for _, feature := range features {
+				s[feature] = &schema.Schema{Type: schema.TypeBool, Computed: true}
+			}
+			return s
+		},
+	}
+}
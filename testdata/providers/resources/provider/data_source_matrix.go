@@ -0,0 +1,25 @@
+package provider
+
+import "github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+
+// dataSourceMatrix nests objects in a list element, which the framework has
+// no element type for.
+func dataSourceMatrix() *schema.Resource {
+	return &schema.Resource{
+		ReadContext: schema.NoopContext,
+		Schema: map[string]*schema.Schema{
+			"cells": {
+				Type:     schema.TypeList,
+				Computed: true,
+				Elem: &schema.Schema{
+					Type: schema.TypeList,
+					Elem: &schema.Resource{
+						Schema: map[string]*schema.Schema{
+							"value": {Type: schema.TypeString, Computed: true},
+						},
+					},
+				},
+			},
+		},
+	}
+}
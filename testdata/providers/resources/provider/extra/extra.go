@@ -0,0 +1,77 @@
+// Package extra is a service package registering its own resources.
+package extra
+
+import (
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+)
+
+// ServicePrefix is the prefix of the resource types of the service.
+const ServicePrefix = "resources_"
+
+type attributeKey string
+
+const (
+	keyLabel      attributeKey = "label"
+	keyRegion                  = "region"
+	labelRequired              = !optionalLabel
+	optionalLabel              = false
+	maxLabel                   = 8 * 8
+)
+
+var regions = []string{"eu", "us"}
+
+var defaultZone = "eu-1a"
+
+var betaEnabled = os.Getenv("EXTRA_BETA") != ""
+
+// Resources returns the resources of the service.
+func Resources() map[string]*schema.Resource {
+	return map[string]*schema.Resource{
+		ServicePrefix + "extra": resourceExtra(),
+	}
+}
+
+func resourceExtra() *schema.Resource {
+	return &schema.Resource{
+		ReadContext: schema.NoopContext,
+		SchemaFunc:  extraSchema,
+	}
+}
+
+func extraSchema() map[string]*schema.Schema {
+	s := map[string]*schema.Schema{
+		string(keyLabel): {
+			Type:        schema.TypeString,
+			Required:    labelRequired,
+			Description: fmt.Sprintf("Label of the %s, at most %d characters", "extra", maxLabel),
+		},
+		keyRegion: {
+			Type:        schema.TypeString,
+			Optional:    true,
+			Description: "One of " + strings.Join([]string{"eu", "us"}, ", "),
+		},
+	}
+	s["zone"] = &schema.Schema{
+		Type:        schema.TypeString,
+		Optional:    true,
+		Default:     defaultZone,
+		Description: "Zone in one of " + strings.Join(regions, ", "),
+	}
+	addTags(s)
+	if betaEnabled {
+		s["beta"] = &schema.Schema{Type: schema.TypeBool, Optional: true}
+	}
+	return s
+}
+
+func addTags(s map[string]*schema.Schema) {
+	s["tags"] = &schema.Schema{
+		Type:     schema.TypeMap,
+		Optional: true,
+		Elem:     &schema.Schema{Type: schema.TypeString},
+	}
+}
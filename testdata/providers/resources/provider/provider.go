@@ -0,0 +1,86 @@
+package provider
+
+import (
+	"context"
+	"maps"
+
+	"github.com/acme/terraform-provider-resources/provider/extra"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+)
+
+func Provider() *schema.Provider {
+	resources := map[string]*schema.Resource{
+		"resources_widget": resourceWidget(),
+	}
+	maps.Copy(resources, extra.Resources())
+	for name, resource := range legacyResources() {
+		resources[name] = resource
+	}
+	resources[extra.ServicePrefix+"counter"] = resourceCounter()
+	registerPlugins(resources)
+
+	return &schema.Provider{
+		SchemaFunc: func() map[string]*schema.Schema {
+			return map[string]*schema.Schema{
+				"endpoint": {
+					Type:        schema.TypeString,
+					Optional:    true,
+					Description: "Base API endpoint",
+				},
+			}
+		},
+		ProviderMetaSchema: map[string]*schema.Schema{
+			"module_name": {
+				Type:        schema.TypeString,
+				Optional:    true,
+				Description: "Name of the module using the provider",
+			},
+			"labels": {
+				Type:     schema.TypeMap,
+				Optional: true,
+				Elem:     &schema.Schema{Type: schema.TypeString},
+			},
+			"team": {
+				Type:     schema.TypeString,
+				Optional: true,
+				Default:  "platform",
+			},
+		},
+		ConfigureContextFunc: providerConfigure,
+		ResourcesMap:         resources,
+		DataSourcesMap: map[string]*schema.Resource{
+			"resources_widget":   dataSourceWidget(),
+			"resources_matrix":   dataSourceMatrix(),
+			"resources_features": dataSourceFeatures(),
+		},
+	}
+}
+
+func providerConfigure(_ context.Context, d *schema.ResourceData) (interface{}, diag.Diagnostics) {
+	endpoint, ok := d.Get("endpoint").(string)
+	if !ok {
+		return nil, diag.Errorf("endpoint is not a string")
+	}
+	if endpoint == "" {
+		endpoint = DefaultEndpoint
+	}
+	logConfigure(d)
+	client := NewClient(endpoint)
+	return client, nil
+}
+
+// logConfigure records the provider configuration, which the framework
+// Configure cannot call.
+func logConfigure(d *schema.ResourceData) {}
+
+func legacyResources() map[string]*schema.Resource {
+	return map[string]*schema.Resource{
+		"resources_gadget": resourceGadget(),
+		"resources_legacy": resourceLegacy(),
+	}
+}
+
+// registerPlugins adds resources discovered at run time, which the migration
+// cannot see.
+func registerPlugins(resources map[string]*schema.Resource) {}
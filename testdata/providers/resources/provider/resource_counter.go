@@ -0,0 +1,48 @@
+package provider
+
+import (
+	"context"
+	"strconv"
+	"strings"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+)
+
+// Counters are identified by their numeric value.
+func resourceCounter() *schema.Resource {
+	return &schema.Resource{
+		CreateContext: resourceCounterCreate,
+		ReadContext:   resourceCounterRead,
+		Importer: &schema.ResourceImporter{
+			StateContext: resourceCounterImport,
+		},
+		Schema: map[string]*schema.Schema{
+			"value": {
+				Type:     schema.TypeInt,
+				Required: true,
+			},
+		},
+	}
+}
+
+func resourceCounterCreate(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	d.SetId(strconv.Itoa(d.Get("value").(int)))
+	return nil
+}
+
+func resourceCounterRead(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	value, err := strconv.Atoi(d.Id())
+	if err != nil {
+		return diag.FromErr(err)
+	}
+
+	d.Set("value", value)
+	return nil
+}
+
+// resourceCounterImport accepts IDs with a leading "counter-" prefix.
+func resourceCounterImport(ctx context.Context, d *schema.ResourceData, meta interface{}) ([]*schema.ResourceData, error) {
+	d.SetId(strings.TrimPrefix(d.Id(), "counter-"))
+	return []*schema.ResourceData{d}, nil
+}
@@ -0,0 +1,132 @@
+package provider
+
+import (
+	"context"
+	"strings"
+	"time"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/structure"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/validation"
+)
+
+const (
+	defaultWeight = 1.5
+	createTimeout = 90 * time.Second
+)
+
+func resourceGadget() *schema.Resource {
+	return &schema.Resource{
+		CreateContext: func(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+			serial := d.Get("serial").(string)
+			d.SetId(serial)
+			d.Set("weight", float64(len(serial)))
+			d.Set("manifest", "{}")
+			d.Set("expires_at", time.Now().Add(24*time.Hour).Format(time.RFC3339))
+			return nil
+		},
+		ReadContext: schema.NoopContext,
+		Timeouts: &schema.ResourceTimeout{
+			Create: schema.DefaultTimeout(createTimeout),
+		},
+		Schema: map[string]*schema.Schema{
+			"serial": {
+				Type:     schema.TypeString,
+				Required: true,
+			},
+			"weight": {
+				Type:     schema.TypeFloat,
+				Optional: true,
+				Default:  defaultWeight,
+			},
+			"manifest": {
+				Type:             schema.TypeString,
+				Optional:         true,
+				Computed:         true,
+				ValidateFunc:     validation.StringIsJSON,
+				DiffSuppressFunc: structure.SuppressJsonDiff,
+			},
+			"expires_at": {
+				Type:             schema.TypeString,
+				Computed:         true,
+				ValidateDiagFunc: validation.ToDiagFunc(validation.IsRFC3339Time),
+			},
+			"model": {
+				Type:             schema.TypeString,
+				Optional:         true,
+				DiffSuppressFunc: suppressCaseDiff,
+			},
+			"limits": {
+				Type:     schema.TypeMap,
+				Optional: true,
+				Elem: &schema.Schema{
+					Type: schema.TypeList,
+					Elem: &schema.Schema{Type: schema.TypeInt},
+				},
+			},
+			"schedule": {
+				Type:     schema.TypeList,
+				Optional: true,
+				Elem: &schema.Schema{
+					Type: schema.TypeMap,
+					Elem: &schema.Schema{Type: schema.TypeString},
+				},
+			},
+			"credentials": {
+				Type:      schema.TypeList,
+				Optional:  true,
+				Sensitive: true,
+				Elem: &schema.Resource{
+					Schema: map[string]*schema.Schema{
+						"username": {Type: schema.TypeString, Required: true},
+						"password": {Type: schema.TypeString, Required: true},
+						"token": {
+							Type:     schema.TypeList,
+							Optional: true,
+							Elem: &schema.Resource{
+								Schema: map[string]*schema.Schema{
+									"value": {Type: schema.TypeString, Optional: true},
+								},
+							},
+						},
+					},
+				},
+			},
+			"ports": {
+				Type:       schema.TypeSet,
+				Optional:   true,
+				ConfigMode: schema.SchemaConfigModeAttr,
+				Elem: &schema.Resource{
+					Schema: map[string]*schema.Schema{
+						"number":   {Type: schema.TypeInt, Required: true},
+						"protocol": {Type: schema.TypeString, Optional: true},
+					},
+				},
+			},
+			"dimensions": {
+				Type:     schema.TypeList,
+				Optional: true,
+				ForceNew: true,
+				MaxItems: 1,
+				Elem: &schema.Resource{
+					Schema: map[string]*schema.Schema{
+						"height": {
+							Type:     schema.TypeInt,
+							Required: true,
+						},
+						"width": {
+							Type:     schema.TypeInt,
+							Required: true,
+						},
+					},
+				},
+			},
+		},
+	}
+}
+
+// suppressCaseDiff ignores changes in case, as the API does.
+func suppressCaseDiff(k, old, new string, d *schema.ResourceData) bool {
+	return strings.EqualFold(old, new)
+}
@@ -0,0 +1,32 @@
+package provider
+
+import (
+	"context"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+)
+
+// resourceLegacy keeps its prior schema type behind a helper, which the
+// migration cannot resolve.
+func resourceLegacy() *schema.Resource {
+	return &schema.Resource{
+		ReadContext:   schema.NoopContext,
+		SchemaVersion: 1,
+		StateUpgraders: []schema.StateUpgrader{
+			{
+				Version: 0,
+				Type:    resourceLegacyTypeV0(),
+				Upgrade: func(ctx context.Context, rawState map[string]interface{}, meta interface{}) (map[string]interface{}, error) {
+					return rawState, nil
+				},
+			},
+		},
+		Schema: map[string]*schema.Schema{
+			"name": {Type: schema.TypeString, Required: true},
+		},
+	}
+}
+
+func resourceLegacyTypeV0() interface{} {
+	return (&schema.Resource{}).CoreConfigSchema().ImpliedType()
+}
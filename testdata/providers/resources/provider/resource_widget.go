@@ -0,0 +1,233 @@
+package provider
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/customdiff"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/validation"
+)
+
+func resourceWidget() *schema.Resource {
+	return &schema.Resource{
+		CreateContext: resourceWidgetCreate,
+		ReadContext:   resourceWidgetRead,
+		UpdateContext: resourceWidgetUpdate,
+		DeleteContext: resourceWidgetDelete,
+		Importer: &schema.ResourceImporter{
+			StateContext: schema.ImportStatePassthroughContext,
+		},
+		SchemaVersion: 1,
+		StateUpgraders: []schema.StateUpgrader{
+			{
+				Version: 0,
+				Type:    resourceWidgetV0().CoreConfigSchema().ImpliedType(),
+				Upgrade: resourceWidgetStateUpgradeV0,
+			},
+		},
+		CustomizeDiff: customdiff.All(
+			customdiff.ForceNewIfChange("size", func(ctx context.Context, old, new, meta interface{}) bool {
+				return new.(int) < old.(int)
+			}),
+			customdiff.ComputedIf("labels", func(ctx context.Context, d *schema.ResourceDiff, meta interface{}) bool {
+				return d.HasChange("name")
+			}),
+			resourceWidgetCustomizeDiff,
+		),
+		Timeouts: &schema.ResourceTimeout{
+			Create:  schema.DefaultTimeout(10 * time.Minute),
+			Default: schema.DefaultTimeout(5 * time.Minute),
+		},
+		Schema: map[string]*schema.Schema{
+			"name": {
+				Type:        schema.TypeString,
+				Required:    true,
+				ForceNew:    true,
+				Description: "Widget name",
+			},
+			"size": {
+				Type:     schema.TypeInt,
+				Optional: true,
+				Default:  3,
+			},
+			"enabled": {
+				Type:     schema.TypeBool,
+				Optional: true,
+			},
+			"gateway": {
+				Type:         schema.TypeString,
+				Optional:     true,
+				ValidateFunc: validation.IsIPv4Address,
+			},
+			"labels": {
+				Type:     schema.TypeList,
+				Optional: true,
+				Computed: true,
+				Elem:     &schema.Schema{Type: schema.TypeString},
+			},
+			"rule": {
+				Type:     schema.TypeList,
+				Optional: true,
+				Elem: &schema.Resource{
+					Schema: map[string]*schema.Schema{
+						"cidr": {
+							Type:         schema.TypeString,
+							Required:     true,
+							ForceNew:     true,
+							ValidateFunc: validation.IsCIDR,
+						},
+						"port_range": {
+							Type:     schema.TypeList,
+							Optional: true,
+							ForceNew: true,
+							Elem: &schema.Resource{
+								Schema: map[string]*schema.Schema{
+									"from": {Type: schema.TypeInt, Required: true},
+									"to":   {Type: schema.TypeInt, Required: true},
+								},
+							},
+						},
+					},
+				},
+			},
+		},
+	}
+}
+
+// resourceWidgetV0 is the schema before labels replaced the comma separated
+// tags attribute.
+func resourceWidgetV0() *schema.Resource {
+	return &schema.Resource{
+		Schema: map[string]*schema.Schema{
+			"name": {Type: schema.TypeString, Required: true},
+			"size": {Type: schema.TypeInt, Optional: true},
+			"tags": {Type: schema.TypeString, Optional: true},
+		},
+	}
+}
+
+func resourceWidgetStateUpgradeV0(ctx context.Context, rawState map[string]interface{}, meta interface{}) (map[string]interface{}, error) {
+	if tags, ok := rawState["tags"].(string); ok && tags != "" {
+		rawState["labels"] = strings.Split(tags, ",")
+	}
+	delete(rawState, "tags")
+	return rawState, nil
+}
+
+// resourceWidgetCustomizeDiff rejects disabling a widget that has rules.
+func resourceWidgetCustomizeDiff(ctx context.Context, d *schema.ResourceDiff, meta interface{}) error {
+	if !d.Get("enabled").(bool) && len(d.Get("rule").([]interface{})) > 0 {
+		return fmt.Errorf("a widget with rules must be enabled")
+	}
+	return nil
+}
+
+func resourceWidgetCreate(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	client := meta.(*Client)
+
+	name := d.Get("name").(string)
+	size := d.Get("size").(int)
+	if size == 0 {
+		return diag.Errorf("size of widget %s must not be zero", name)
+	}
+
+	id, err := client.CreateWidget(ctx, name, size)
+	if err != nil {
+		return diag.FromErr(err)
+	}
+	d.SetId(id)
+
+	return resourceWidgetRead(ctx, d, meta)
+}
+
+func resourceWidgetRead(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	client := meta.(*Client)
+
+	widget, err := client.GetWidget(ctx, d.Id())
+	if err != nil {
+		return diag.FromErr(err)
+	}
+	if widget == nil {
+		d.SetId("")
+		return nil
+	}
+
+	if err := d.Set("size", widget.Size); err != nil {
+		return diag.FromErr(err)
+	}
+	return nil
+}
+
+func resourceWidgetUpdate(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	// Names are stored trimmed.
+	name := strings.TrimSpace(d.Get("name").(string))
+	if err := d.Set("name", name); err != nil {
+		return diag.FromErr(err)
+	}
+	if !d.Get("enabled").(bool) {
+		return diag.FromErr(fmt.Errorf("widget %s is disabled", name))
+	}
+
+	return resourceWidgetRead(ctx, d, meta)
+}
+
+func resourceWidgetDelete(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	client := meta.(*Client)
+	if err := client.DeleteWidget(ctx, d.Id()); err != nil {
+		return diag.FromErr(err)
+	}
+
+	d.SetId("")
+	return nil
+}
+
+func dataSourceWidget() *schema.Resource {
+	return &schema.Resource{
+		Read: dataSourceWidgetRead,
+		Timeouts: &schema.ResourceTimeout{
+			Read: schema.DefaultTimeout(2 * time.Minute),
+		},
+		Schema: map[string]*schema.Schema{
+			"name": {
+				Type:     schema.TypeString,
+				Required: true,
+			},
+			"size": {
+				Type:     schema.TypeInt,
+				Computed: true,
+			},
+			"status": {
+				Type:     schema.TypeList,
+				Computed: true,
+				Elem: &schema.Resource{
+					Schema: map[string]*schema.Schema{
+						"state": {Type: schema.TypeString, Optional: true},
+						"events": {
+							Type:     schema.TypeList,
+							Computed: true,
+							Elem: &schema.Resource{
+								Schema: map[string]*schema.Schema{
+									"message": {Type: schema.TypeString, Computed: true},
+								},
+							},
+						},
+					},
+				},
+			},
+		},
+	}
+}
+
+func dataSourceWidgetRead(d *schema.ResourceData, meta interface{}) error {
+	name := d.Get("name").(string)
+	if name == "" {
+		return fmt.Errorf("name must not be empty")
+	}
+
+	d.Set("size", len(name))
+	return nil
+}
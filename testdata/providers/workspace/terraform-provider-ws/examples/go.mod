@@ -0,0 +1,3 @@
+module github.com/acme/terraform-provider-ws/examples
+
+go 1.22.0
@@ -0,0 +1,12 @@
+package main
+
+import (
+	"github.com/acme/terraform-provider-ws/provider"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/plugin"
+)
+
+func main() {
+	plugin.Serve(&plugin.ServeOpts{
+		ProviderFunc: provider.Provider,
+	})
+}